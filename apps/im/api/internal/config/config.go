@@ -1,8 +1,11 @@
 package config
 
 import (
+	"github.com/zeromicro/go-zero/core/stores/redis"
 	"github.com/zeromicro/go-zero/rest"
 	"github.com/zeromicro/go-zero/zrpc"
+	"imooc.com/easy-chat/pkg/membership"
+	"imooc.com/easy-chat/pkg/mqx"
 )
 
 type Config struct {
@@ -12,6 +15,11 @@ type Config struct {
 	UserRpc   zrpc.RpcClientConf
 	SocialRpc zrpc.RpcClientConf
 
+	Redisx redis.RedisConf
+
+	// GroupMembers 群成员列表的缓存，成员变化时由社交服务发布通知使缓存失效
+	GroupMembers membership.Conf `json:",optional"`
+
 	Mongo struct {
 		Url string
 		Db  string
@@ -20,4 +28,35 @@ type Config struct {
 	JwtAuth struct {
 		AccessSecret string
 	}
+
+	// Queue 消息队列的后端，kafka、redis 或进程内的 memory，各个服务需要一致
+	Queue mqx.Conf `json:",optional"`
+
+	// MsgRevokeTransfer 审核不通过的消息通过撤回队列撤回
+	MsgRevokeTransfer struct {
+		Topic string
+		Addrs []string
+	}
+
+	// MsgChatTransfer 机器人通过 incoming webhook 发送的消息
+	MsgChatTransfer struct {
+		Topic string
+		Addrs []string
+	}
+
+	// Admins 管理员，可以处理审核队列与发送系统公告，系统用户始终是管理员
+	Admins []string `json:",optional"`
+
+	// Export 会话导出
+	Export struct {
+		// 归档保存的目录，归档由 task 写入 MsgExport.Dir、由 api 从这里读取下载，
+		// 两者必须是同一个目录：部署在同一台机器，或挂载同一个共享存储（如 NFS）
+		Dir string `json:",default=./data/export"`
+		// 下载链接的签名密钥，必须配置且不少于 16 个字节，不能与 JwtAuth.AccessSecret 相同
+		SignSecret string
+		// 下载链接的有效期，单位秒
+		LinkTtl int64 `json:",default=600"`
+		// 每个用户每小时最多发起的导出次数
+		MaxPerHour int64 `json:",default=3"`
+	}
 }
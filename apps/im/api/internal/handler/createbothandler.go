@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"imooc.com/easy-chat/apps/im/api/internal/logic"
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+)
+
+func createBotHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.CreateBotReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.Error(w, err)
+			return
+		}
+
+		l := logic.NewCreateBotLogic(r.Context(), svcCtx)
+		resp, err := l.CreateBot(&req)
+		if err != nil {
+			httpx.Error(w, err)
+		} else {
+			httpx.OkJson(w, resp)
+		}
+	}
+}
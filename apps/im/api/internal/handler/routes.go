@@ -30,6 +30,30 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/chatlog",
 				Handler: getChatLogHandler(serverCtx),
 			},
+			{
+				// 获取引用了某条消息的全部回复
+				Method:  http.MethodGet,
+				Path:    "/chatlog/replies",
+				Handler: getRepliesHandler(serverCtx),
+			},
+			{
+				// 展开合并转发的聊天记录
+				Method:  http.MethodGet,
+				Path:    "/chatlog/forward",
+				Handler: getMergeForwardHandler(serverCtx),
+			},
+			{
+				// 搜索聊天记录
+				Method:  http.MethodGet,
+				Path:    "/chatlog/search",
+				Handler: searchChatLogHandler(serverCtx),
+			},
+			{
+				// 获取会话的置顶消息
+				Method:  http.MethodGet,
+				Path:    "/conversation/pins",
+				Handler: getPinnedMessagesHandler(serverCtx),
+			},
 			{
 				// 获取会话
 				Method:  http.MethodGet,
@@ -42,6 +66,30 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/conversation",
 				Handler: putConversationsHandler(serverCtx),
 			},
+			{
+				// 设置会话的阅后即焚
+				Method:  http.MethodPut,
+				Path:    "/conversation/disappear",
+				Handler: setDisappearHandler(serverCtx),
+			},
+			{
+				// 设置群聊的撤回策略
+				Method:  http.MethodPut,
+				Path:    "/conversation/revokePolicy",
+				Handler: setRevokePolicyHandler(serverCtx),
+			},
+			{
+				// 获取群聊的撤回审计记录
+				Method:  http.MethodGet,
+				Path:    "/conversation/revokeAudits",
+				Handler: getRevokeAuditsHandler(serverCtx),
+			},
+			{
+				// 设置群聊的慢速模式
+				Method:  http.MethodPut,
+				Path:    "/conversation/slowMode",
+				Handler: setSlowModeHandler(serverCtx),
+			},
 			{
 				// 建立会话
 				Method:  http.MethodPost,
@@ -64,4 +112,172 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 		rest.WithJwt(serverCtx.Config.JwtAuth.AccessSecret),
 		rest.WithPrefix("/v1/im"),
 	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 创建定时消息
+				Method:  http.MethodPost,
+				Path:    "/scheduled",
+				Handler: createScheduledMessageHandler(serverCtx),
+			},
+			{
+				// 获取定时消息列表
+				Method:  http.MethodGet,
+				Path:    "/scheduled",
+				Handler: listScheduledMessagesHandler(serverCtx),
+			},
+			{
+				// 取消定时消息
+				Method:  http.MethodDelete,
+				Path:    "/scheduled",
+				Handler: cancelScheduledMessageHandler(serverCtx),
+			},
+		},
+		rest.WithJwt(serverCtx.Config.JwtAuth.AccessSecret),
+		rest.WithPrefix("/v1/im"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 发起会话导出
+				Method:  http.MethodPost,
+				Path:    "/export",
+				Handler: createExportHandler(serverCtx),
+			},
+			{
+				// 查询导出任务
+				Method:  http.MethodGet,
+				Path:    "/export",
+				Handler: getExportHandler(serverCtx),
+			},
+			{
+				// 获取导出任务列表
+				Method:  http.MethodGet,
+				Path:    "/export/list",
+				Handler: listExportsHandler(serverCtx),
+			},
+		},
+		rest.WithJwt(serverCtx.Config.JwtAuth.AccessSecret),
+		rest.WithPrefix("/v1/im"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 下载导出的归档，使用签名链接鉴权
+				Method:  http.MethodGet,
+				Path:    "/export/download",
+				Handler: downloadExportHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/v1/im"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 获取待审核的消息，仅管理员可用
+				Method:  http.MethodGet,
+				Path:    "/moderation/reviews",
+				Handler: listModerationReviewsHandler(serverCtx),
+			},
+			{
+				// 处理待审核的消息，仅管理员可用
+				Method:  http.MethodPut,
+				Path:    "/moderation/review",
+				Handler: resolveModerationReviewHandler(serverCtx),
+			},
+		},
+		rest.WithJwt(serverCtx.Config.JwtAuth.AccessSecret),
+		rest.WithPrefix("/v1/im"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 发送系统公告，仅管理员可用
+				Method:  http.MethodPost,
+				Path:    "/announcement",
+				Handler: createAnnouncementHandler(serverCtx),
+			},
+			{
+				// 获取系统公告列表，仅管理员可用
+				Method:  http.MethodGet,
+				Path:    "/announcement/list",
+				Handler: listAnnouncementsHandler(serverCtx),
+			},
+		},
+		rest.WithJwt(serverCtx.Config.JwtAuth.AccessSecret),
+		rest.WithPrefix("/v1/im"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 创建机器人
+				Method:  http.MethodPost,
+				Path:    "/bot",
+				Handler: createBotHandler(serverCtx),
+			},
+			{
+				// 获取我创建的机器人
+				Method:  http.MethodGet,
+				Path:    "/bot/list",
+				Handler: listBotsHandler(serverCtx),
+			},
+			{
+				// 修改机器人的 outgoing webhook
+				Method:  http.MethodPut,
+				Path:    "/bot/webhook",
+				Handler: updateBotWebhookHandler(serverCtx),
+			},
+			{
+				// 重置机器人的签名密钥与 incoming webhook 的 token
+				Method:  http.MethodPost,
+				Path:    "/bot/keys/reset",
+				Handler: resetBotKeysHandler(serverCtx),
+			},
+			{
+				// 邀请机器人进群，需要是机器人的创建者以及群主或管理员
+				Method:  http.MethodPost,
+				Path:    "/bot/invite",
+				Handler: inviteBotHandler(serverCtx),
+			},
+		},
+		rest.WithJwt(serverCtx.Config.JwtAuth.AccessSecret),
+		rest.WithPrefix("/v1/im"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 机器人的 incoming webhook，使用 token 鉴权
+				Method:  http.MethodPost,
+				Path:    "/bot/hook/:token",
+				Handler: botHookHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/v1/im"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 获取消费失败的死信，仅管理员可用
+				Method:  http.MethodGet,
+				Path:    "/deadletters",
+				Handler: listDeadLettersHandler(serverCtx),
+			},
+			{
+				// 把死信重放到原 topic 重新处理，仅管理员可用
+				Method:  http.MethodPut,
+				Path:    "/deadletter/replay",
+				Handler: replayDeadLetterHandler(serverCtx),
+			},
+		},
+		rest.WithJwt(serverCtx.Config.JwtAuth.AccessSecret),
+		rest.WithPrefix("/v1/im"),
+	)
 }
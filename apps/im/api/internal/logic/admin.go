@@ -0,0 +1,19 @@
+package logic
+
+import (
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+// isAdmin 是否为管理员，系统用户与配置中的管理员
+func isAdmin(svcCtx *svc.ServiceContext, userId string) bool {
+	if userId == constants.SYSTEM_ROOT_UID {
+		return true
+	}
+	for _, admin := range svcCtx.Config.Admins {
+		if admin == userId {
+			return true
+		}
+	}
+	return false
+}
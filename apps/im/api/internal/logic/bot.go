@@ -0,0 +1,67 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/webhook"
+)
+
+func toBot(bot *immodels.Bot) *types.Bot {
+	return &types.Bot{
+		Id:         bot.ID,
+		Name:       bot.Name,
+		Avatar:     bot.Avatar,
+		WebhookUrl: bot.WebhookUrl,
+		Events:     bot.Events,
+		Secret:     bot.Secret,
+		Token:      bot.Token,
+		CreateTime: bot.CreateAt.UnixMilli(),
+	}
+}
+
+// checkBotWebhook 校验 webhook 地址与订阅的事件，返回去重后的事件
+func checkBotWebhook(webhookUrl string, events []string) ([]string, error) {
+	if webhookUrl != "" {
+		if err := webhook.ValidateURL(webhookUrl); err != nil {
+			return nil, errors.New("webhook 地址无效")
+		}
+	}
+
+	var (
+		res  []string
+		seen = make(map[string]struct{}, len(events))
+	)
+	for _, event := range events {
+		if event != immodels.BotEventMessage && event != immodels.BotEventCommand {
+			return nil, errors.New("不支持的订阅事件")
+		}
+		if _, ok := seen[event]; ok {
+			continue
+		}
+		seen[event] = struct{}{}
+		res = append(res, event)
+	}
+	if webhookUrl != "" && len(res) == 0 {
+		return nil, errors.New("请选择订阅的事件")
+	}
+	return res, nil
+}
+
+// ownedBot 查询用户创建的机器人
+func ownedBot(ctx context.Context, svcCtx *svc.ServiceContext, botId, userId string) (*immodels.Bot, error) {
+	bot, err := svcCtx.BotModel.FindOne(ctx, botId)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			return nil, errors.New("机器人不存在")
+		}
+		return nil, err
+	}
+	if bot.OwnerId != userId {
+		return nil, errors.New("只能管理自己创建的机器人")
+	}
+	return bot, nil
+}
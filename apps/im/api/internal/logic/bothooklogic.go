@@ -0,0 +1,80 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/pkg/card"
+	"imooc.com/easy-chat/pkg/constants"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type BotHookLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewBotHookLogic(ctx context.Context, svcCtx *svc.ServiceContext) *BotHookLogic {
+	return &BotHookLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// BotHook 机器人向所在的群发送消息，消息与用户消息走相同的投递流程
+func (l *BotHookLogic) BotHook(req *types.BotHookReq) (resp *types.BotHookResp, err error) {
+	bot, err := l.svcCtx.BotModel.FindByToken(l.ctx, req.Token)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			return nil, errors.New("token 无效")
+		}
+		return nil, err
+	}
+
+	if strings.TrimSpace(req.Content) == "" {
+		return nil, errors.New("消息内容不能为空")
+	}
+	switch constants.MType(req.MsgType) {
+	case constants.TextMType:
+	case constants.CardMType:
+		if _, err := card.Parse(req.Content); err != nil {
+			return nil, errors.New("卡片格式错误")
+		}
+	default:
+		return nil, errors.New("机器人只能发送文本与卡片消息")
+	}
+	role, err := groupRole(l.ctx, l.svcCtx, req.GroupId, bot.ID)
+	if err != nil {
+		return nil, err
+	}
+	if role == 0 {
+		return nil, errors.New("机器人不在群中")
+	}
+
+	err = l.svcCtx.MsgChatTransferClient.Push(&mq.MsgChatTransfer{
+		ConversationId: req.GroupId,
+		ChatType:       constants.GroupChatType,
+		SendId:         bot.ID,
+		RecvId:         req.GroupId,
+		SendTime:       time.Now().UnixMilli(),
+		MType:          constants.MType(req.MsgType),
+		Content:        req.Content,
+		Mentions:       req.Mentions,
+		MsgFrom:        constants.BotMsgFrom,
+	})
+	if err != nil {
+		l.Errorf("BotHook push err %v, botId %v", err, bot.ID)
+		return nil, err
+	}
+
+	return &types.BotHookResp{}, nil
+}
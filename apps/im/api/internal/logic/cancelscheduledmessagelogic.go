@@ -0,0 +1,46 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CancelScheduledMessageLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCancelScheduledMessageLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CancelScheduledMessageLogic {
+	return &CancelScheduledMessageLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CancelScheduledMessage 取消定时消息，已开始发送的消息无法取消
+func (l *CancelScheduledMessageLogic) CancelScheduledMessage(req *types.CancelScheduledMessageReq) (resp *types.CancelScheduledMessageResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	err = l.svcCtx.ScheduledMessageModel.Cancel(l.ctx, req.Id, userId)
+	switch {
+	case err == nil:
+		return &types.CancelScheduledMessageResp{}, nil
+	case errors.Is(err, immodels.ErrNotFound), errors.Is(err, immodels.ErrInvalidObjectId):
+		return nil, errors.New("定时消息不存在或已发送")
+	default:
+		l.Errorf("CancelScheduledMessage Cancel err %v", err)
+		return nil, err
+	}
+}
@@ -0,0 +1,85 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 指定用户发送公告时最多的用户数，更多的用户请按群或注册时间发送
+const maxAnnouncementUsers = 10000
+
+type CreateAnnouncementLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateAnnouncementLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateAnnouncementLogic {
+	return &CreateAnnouncementLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateAnnouncement 创建系统公告，由 task 分批发送到接收者的系统会话中
+func (l *CreateAnnouncementLogic) CreateAnnouncement(req *types.CreateAnnouncementReq) (resp *types.CreateAnnouncementResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+	if !isAdmin(l.svcCtx, userId) {
+		return nil, errors.New("仅管理员可以发送系统公告")
+	}
+
+	if strings.TrimSpace(req.MsgContent) == "" {
+		return nil, errors.New("公告内容不能为空")
+	}
+
+	announcement := &immodels.Announcement{
+		MsgType:    constants.MType(req.MsgType),
+		MsgContent: req.MsgContent,
+		CreateBy:   userId,
+		Target:     req.Target,
+	}
+	switch req.Target {
+	case immodels.AnnouncementToUsers:
+		if len(req.UserIds) == 0 {
+			return nil, errors.New("请指定接收公告的用户")
+		}
+		if len(req.UserIds) > maxAnnouncementUsers {
+			return nil, errors.New("指定的用户过多")
+		}
+		announcement.UserIds = req.UserIds
+	case immodels.AnnouncementToGroup:
+		if req.GroupId == "" {
+			return nil, errors.New("请指定接收公告的群")
+		}
+		announcement.GroupId = req.GroupId
+	case immodels.AnnouncementToRegistered:
+		if req.RegisterStart <= 0 && req.RegisterEnd <= 0 {
+			return nil, errors.New("请指定注册时间的范围")
+		}
+		if req.RegisterEnd > 0 && req.RegisterStart >= req.RegisterEnd {
+			return nil, errors.New("注册时间的范围无效")
+		}
+		announcement.RegisterStart = req.RegisterStart
+		announcement.RegisterEnd = req.RegisterEnd
+	}
+
+	if err := l.svcCtx.AnnouncementModel.Insert(l.ctx, announcement); err != nil {
+		l.Errorf("CreateAnnouncement Insert err %v", err)
+		return nil, err
+	}
+
+	return &types.CreateAnnouncementResp{Id: announcement.ID.Hex()}, nil
+}
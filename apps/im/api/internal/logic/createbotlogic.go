@@ -0,0 +1,88 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/user/rpc/userclient"
+	"imooc.com/easy-chat/pkg/ctxdata"
+	"imooc.com/easy-chat/pkg/webhook"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// 机器人名称的最大长度，与用户昵称一致
+	maxBotNameLen = 24
+	// 每个用户最多创建的机器人数
+	maxBotsPerUser = 20
+)
+
+type CreateBotLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateBotLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateBotLogic {
+	return &CreateBotLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateBot 创建机器人账号，返回签名密钥与 incoming webhook 的 token
+func (l *CreateBotLogic) CreateBot(req *types.CreateBotReq) (resp *types.CreateBotResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxBotNameLen {
+		return nil, errors.New("机器人名称不能为空且不能超过24个字")
+	}
+	events, err := checkBotWebhook(req.WebhookUrl, req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	bots, err := l.svcCtx.BotModel.ListByOwner(l.ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if len(bots) >= maxBotsPerUser {
+		return nil, errors.New("创建的机器人数量已达上限")
+	}
+
+	account, err := l.svcCtx.User.CreateBot(l.ctx, &userclient.CreateBotReq{
+		Nickname: name,
+		Avatar:   req.Avatar,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bot := &immodels.Bot{
+		ID:         account.Id,
+		OwnerId:    userId,
+		Name:       name,
+		Avatar:     req.Avatar,
+		WebhookUrl: req.WebhookUrl,
+		Events:     events,
+		Secret:     webhook.NewSecret(),
+		Token:      webhook.NewSecret(),
+	}
+	if err := l.svcCtx.BotModel.Insert(l.ctx, bot); err != nil {
+		l.Errorf("CreateBot Insert err %v, botId %v", err, account.Id)
+		return nil, err
+	}
+
+	return &types.CreateBotResp{Bot: toBot(bot)}, nil
+}
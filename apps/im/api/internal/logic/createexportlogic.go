@@ -0,0 +1,95 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateExportLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateExportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateExportLogic {
+	return &CreateExportLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateExport 发起会话导出，由 task 异步生成归档，完成后通过 ws 通知
+// 只有会话成员可以导出，每个会话同时只能有一个进行中的任务，并限制每小时的导出次数
+func (l *CreateExportLogic) CreateExport(req *types.CreateExportReq) (resp *types.CreateExportResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	switch req.Format {
+	case immodels.ExportFormatJSON, immodels.ExportFormatHTML, immodels.ExportFormatCSV:
+	default:
+		return nil, errors.New("不支持的导出格式")
+	}
+
+	chatType, err := l.checkMember(userId, req.ConversationId)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &immodels.ExportJob{
+		UserId:         userId,
+		ConversationId: req.ConversationId,
+		ChatType:       chatType,
+		Format:         req.Format,
+	}
+	err = l.svcCtx.ExportJobModel.Create(l.ctx, job, l.svcCtx.Config.Export.MaxPerHour, time.Hour)
+	switch {
+	case err == nil:
+	case errors.Is(err, immodels.ErrExportActive):
+		return nil, errors.New("该会话已有进行中的导出任务")
+	case errors.Is(err, immodels.ErrExportRateLimited):
+		return nil, errors.New("导出过于频繁，请稍后再试")
+	default:
+		l.Errorf("CreateExport Create err %v", err)
+		return nil, err
+	}
+
+	return &types.CreateExportResp{Id: job.ID.Hex()}, nil
+}
+
+// checkMember 确认用户是会话成员，返回会话类型
+func (l *CreateExportLogic) checkMember(userId, conversationId string) (constants.ChatType, error) {
+	conversations, err := l.svcCtx.ConversationsModel.FindByUserId(l.ctx, userId)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			return 0, errors.New("不是会话成员，无法导出")
+		}
+		return 0, err
+	}
+	conversation, ok := conversations.ConversationList[conversationId]
+	if !ok {
+		return 0, errors.New("不是会话成员，无法导出")
+	}
+
+	if conversation.ChatType == constants.GroupChatType {
+		role, err := groupRole(l.ctx, l.svcCtx, conversationId, userId)
+		if err != nil {
+			return 0, err
+		}
+		if role == 0 {
+			return 0, errors.New("不是会话成员，无法导出")
+		}
+	}
+	return conversation.ChatType, nil
+}
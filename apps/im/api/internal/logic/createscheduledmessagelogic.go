@@ -0,0 +1,76 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/ctxdata"
+	"imooc.com/easy-chat/pkg/wuid"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 定时消息最多可提前设置的时间
+const maxScheduleAhead = 30 * 24 * time.Hour
+
+type CreateScheduledMessageLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateScheduledMessageLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateScheduledMessageLogic {
+	return &CreateScheduledMessageLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateScheduledMessage 创建定时消息，到期后由 task 投递到消息队列按普通消息发送
+func (l *CreateScheduledMessageLogic) CreateScheduledMessage(req *types.CreateScheduledMessageReq) (resp *types.CreateScheduledMessageResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	if req.RecvId == "" || req.MsgContent == "" {
+		return nil, errors.New("接收者和消息内容不能为空")
+	}
+	now := time.Now()
+	if req.SendAt <= now.UnixMilli() || req.SendAt > now.Add(maxScheduleAhead).UnixMilli() {
+		return nil, errors.New("发送时间需在当前时间之后的30天内")
+	}
+
+	// 会话id的生成规则与 ws 发送消息一致
+	var conversationId string
+	switch constants.ChatType(req.ChatType) {
+	case constants.SingleChatType:
+		conversationId = wuid.CombineId(userId, req.RecvId)
+	case constants.GroupChatType:
+		conversationId = req.RecvId
+	default:
+		return nil, errors.New("聊天类型有误")
+	}
+
+	data := &immodels.ScheduledMessage{
+		ConversationId: conversationId,
+		ChatType:       constants.ChatType(req.ChatType),
+		SendId:         userId,
+		RecvId:         req.RecvId,
+		MsgType:        constants.MType(req.MsgType),
+		MsgContent:     req.MsgContent,
+		SendAt:         req.SendAt,
+	}
+	if err = l.svcCtx.ScheduledMessageModel.Insert(l.ctx, data); err != nil {
+		l.Errorf("CreateScheduledMessage Insert err %v", err)
+		return nil, err
+	}
+
+	return &types.CreateScheduledMessageResp{Id: data.ID.Hex()}, nil
+}
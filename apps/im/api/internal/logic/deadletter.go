@@ -0,0 +1,24 @@
+package logic
+
+import (
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+)
+
+func toDeadLetter(letter *immodels.DeadLetter) *types.DeadLetter {
+	return &types.DeadLetter{
+		Id:          letter.ID.Hex(),
+		Topic:       letter.Topic,
+		Key:         letter.Key,
+		Value:       letter.Value,
+		Error:       letter.Error,
+		Attempts:    int32(letter.Attempts),
+		Permanent:   letter.Permanent,
+		FailTime:    letter.FailTime,
+		Status:      int32(letter.Status),
+		ReplayBy:    letter.ReplayBy,
+		ReplayTime:  letter.ReplayTime,
+		ReplayCount: int32(letter.ReplayCount),
+		ReplayError: letter.ReplayError,
+	}
+}
@@ -0,0 +1,73 @@
+package logic
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+var errExportLinkInvalid = errors.New("下载链接无效或已过期")
+
+type DownloadExportLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDownloadExportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DownloadExportLogic {
+	return &DownloadExportLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// DownloadExport 校验签名后返回归档文件
+func (l *DownloadExportLogic) DownloadExport(req *types.DownloadExportReq, w http.ResponseWriter, r *http.Request) error {
+	if req.Expires < time.Now().Unix() {
+		return errExportLinkInvalid
+	}
+	if !hmac.Equal([]byte(req.Sign), []byte(signExport(l.svcCtx, req.Id, req.Expires))) {
+		return errExportLinkInvalid
+	}
+
+	job, err := l.svcCtx.ExportJobModel.FindOne(l.ctx, req.Id)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) || errors.Is(err, immodels.ErrInvalidObjectId) {
+			return errExportLinkInvalid
+		}
+		l.Errorf("DownloadExport FindOne err %v", err)
+		return err
+	}
+	if job.Status != immodels.ExportDone || job.ExpireAt <= time.Now().UnixMilli() {
+		return errExportLinkInvalid
+	}
+
+	f, err := os.Open(filepath.Join(l.svcCtx.Config.Export.Dir, filepath.Base(job.FileName)))
+	if err != nil {
+		l.Errorf("DownloadExport open archive err %v, id %v", err, req.Id)
+		return errExportLinkInvalid
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="chat-export-%s.zip"`, req.Id))
+	http.ServeContent(w, r, "", info.ModTime(), f)
+	return nil
+}
@@ -0,0 +1,58 @@
+package logic
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+)
+
+// exportDownloadPath 下载归档的路由，见 downloadExport
+const exportDownloadPath = "/v1/im/export/download"
+
+// signExport 对任务ID与过期时间签名，下载链接不需要登录，凭签名访问
+func signExport(svcCtx *svc.ServiceContext, id string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(svcCtx.Config.Export.SignSecret))
+	mac.Write([]byte(id + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// exportDownloadUrl 生成带签名的下载链接，有效期为 Export.LinkTtl
+func exportDownloadUrl(svcCtx *svc.ServiceContext, id string) string {
+	expires := time.Now().Add(time.Duration(svcCtx.Config.Export.LinkTtl) * time.Second).Unix()
+
+	query := url.Values{}
+	query.Set("id", id)
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("sign", signExport(svcCtx, id, expires))
+	return fmt.Sprintf("%s?%s", exportDownloadPath, query.Encode())
+}
+
+func toExportJob(svcCtx *svc.ServiceContext, job *immodels.ExportJob) *types.ExportJob {
+	res := &types.ExportJob{
+		Id:             job.ID.Hex(),
+		ConversationId: job.ConversationId,
+		Format:         job.Format,
+		Status:         int32(job.Status),
+		MsgCount:       int32(job.MsgCount),
+		Truncated:      job.Truncated,
+		FileSize:       job.FileSize,
+		CreateTime:     job.CreateAt.UnixMilli(),
+		FinishTime:     job.FinishAt,
+		ExpireAt:       job.ExpireAt,
+	}
+	switch job.Status {
+	case immodels.ExportDone:
+		res.DownloadUrl = exportDownloadUrl(svcCtx, res.Id)
+	case immodels.ExportFailed:
+		res.Error = job.LastErr
+	}
+	return res
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/jinzhu/copier"
 	"imooc.com/easy-chat/apps/im/rpc/imclient"
+	"imooc.com/easy-chat/pkg/ctxdata"
 
 	"imooc.com/easy-chat/apps/im/api/internal/svc"
 	"imooc.com/easy-chat/apps/im/api/internal/types"
@@ -33,6 +34,10 @@ func (l *GetChatLogLogic) GetChatLog(req *types.ChatLogReq) (resp *types.ChatLog
 		StartSendTime:  req.StartSendTime,
 		EndSendTime:    req.EndSendTime,
 		Count:          req.Count,
+		UserId:         ctxdata.GetUId(l.ctx),
+		Direction:      req.Direction,
+		Cursor:         req.Cursor,
+		AroundMsgId:    req.AroundMsgId,
 	})
 	if err != nil {
 		return nil, err
@@ -2,8 +2,8 @@ package logic
 
 import (
 	"context"
+	"imooc.com/easy-chat/apps/im/immodels"
 	"imooc.com/easy-chat/apps/im/rpc/im"
-	"imooc.com/easy-chat/apps/social/rpc/socialclient"
 	"imooc.com/easy-chat/apps/user/rpc/user"
 	"imooc.com/easy-chat/pkg/bitmap"
 	"imooc.com/easy-chat/pkg/constants"
@@ -40,44 +40,57 @@ func (l *GetChatLogReadRecordsLogic) GetChatLogReadRecords(req *types.GetChatLog
 	}
 
 	var (
-		chatlog = chatlogs.List[0]
-		reads   = []string{chatlog.SendId}
-		unreads []string
-		ids     []string
+		chatlog   = chatlogs.List[0]
+		reads     = []string{chatlog.SendId}
+		unreads   []string
+		delivered []string
+		pending   []string
+		ids       []string
 	)
 
-	// 分别设置已读未读
+	isRead, err := l.readChecker(chatlog)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分别设置已读、已送达未读、未送达
 	switch constants.ChatType(chatlog.ChatType) {
 	case constants.SingleChatType:
-		if len(chatlog.ReadRecords) == 0 || chatlog.ReadRecords[0] == 0 {
-			unreads = []string{chatlog.RecvId}
-		} else {
+		switch {
+		case isRead(chatlog.RecvId):
 			reads = append(reads, chatlog.RecvId)
+		case len(chatlog.DeliveredRecords) > 0 && chatlog.DeliveredRecords[0] != 0:
+			delivered = append(delivered, chatlog.RecvId)
+		default:
+			pending = append(pending, chatlog.RecvId)
 		}
 		ids = []string{chatlog.RecvId, chatlog.SendId}
 	case constants.GroupChatType:
-		groupUsers, err := l.svcCtx.Social.GroupUsers(l.ctx, &socialclient.GroupUsersReq{
-			GroupId: chatlog.RecvId,
-		})
+		groupUsers, err := l.svcCtx.GroupMembers.Members(l.ctx, chatlog.RecvId)
 		if err != nil {
 			return nil, err
 		}
 
-		bitmaps := bitmap.Load(chatlog.ReadRecords)
-		for _, members := range groupUsers.List {
+		deliveredBitmaps := bitmap.Load(chatlog.DeliveredRecords)
+		for _, members := range groupUsers {
 			ids = append(ids, members.UserId)
 
 			if members.UserId == chatlog.SendId {
 				continue
 			}
 
-			if bitmaps.IsSet(members.UserId) {
+			switch {
+			case isRead(members.UserId):
 				reads = append(reads, members.UserId)
-			} else {
-				unreads = append(unreads, members.UserId)
+			case deliveredBitmaps.IsSet(members.UserId):
+				delivered = append(delivered, members.UserId)
+			default:
+				pending = append(pending, members.UserId)
 			}
 		}
 	}
+	// 未读包括已送达未读和未送达
+	unreads = append(append(unreads, delivered...), pending...)
 
 	userEntitys, err := l.svcCtx.User.FindUser(l.ctx, &user.FindUserReq{
 		Ids: ids,
@@ -96,14 +109,43 @@ func (l *GetChatLogReadRecordsLogic) GetChatLogReadRecords(req *types.GetChatLog
 			reads[i] = u.Phone
 		}
 	}
-	for i, unread := range unreads {
-		if u := userEntitySet[unread]; u != nil {
-			unreads[i] = u.Phone
+	for _, list := range [][]string{unreads, delivered, pending} {
+		for i, uid := range list {
+			if u := userEntitySet[uid]; u != nil {
+				list[i] = u.Phone
+			}
 		}
 	}
 
 	return &types.GetChatLogReadRecordsResp{
-		Reads:   reads,
-		UnReads: unreads,
+		Reads:     reads,
+		UnReads:   unreads,
+		Delivered: delivered,
+		Pending:   pending,
 	}, nil
 }
+
+// readChecker 返回判断成员是否已读该消息的方法
+// 有序号的消息由成员的已读游标判断，早期没有序号的消息仍使用消息上的已读记录
+func (l *GetChatLogReadRecordsLogic) readChecker(chatlog *im.ChatLog) (func(userId string) bool, error) {
+	if chatlog.Seq > 0 {
+		list, err := l.svcCtx.ReadCursorModel.ListByConversationId(l.ctx, chatlog.ConversationId)
+		if err != nil {
+			return nil, err
+		}
+		cursors := immodels.ReadCursors(list)
+		return func(userId string) bool {
+			return cursors[userId] >= chatlog.Seq
+		}, nil
+	}
+
+	if constants.ChatType(chatlog.ChatType) == constants.SingleChatType {
+		read := len(chatlog.ReadRecords) > 0 && chatlog.ReadRecords[0] != 0
+		return func(string) bool {
+			return read
+		}, nil
+	}
+
+	bitmaps := bitmap.Load(chatlog.ReadRecords)
+	return bitmaps.IsSet, nil
+}
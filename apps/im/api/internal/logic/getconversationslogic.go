@@ -117,15 +117,13 @@ func (l *GetConversationsLogic) GetConversations(req *types.GetConversationsReq)
 
 			// 为每个群组获取成员数量
 			for _, groupId := range groupIds {
-				membersResp, err := l.svcCtx.Social.GroupUsers(l.ctx, &socialclient.GroupUsersReq{
-					GroupId: groupId,
-				})
+				members, err := l.svcCtx.GroupMembers.Members(l.ctx, groupId)
 				if err != nil {
 					l.Errorf("获取群组[%s]成员失败: %v", groupId, err)
 					// 即使获取失败，也设置为0，避免前端显示问题
 					groupMemberCountMap[groupId] = 0
 				} else {
-					groupMemberCountMap[groupId] = int32(len(membersResp.List))
+					groupMemberCountMap[groupId] = int32(len(members))
 				}
 			}
 		}
@@ -138,6 +136,7 @@ func (l *GetConversationsLogic) GetConversations(req *types.GetConversationsReq)
 				l.Errorf("复制会话数据失败: %v", err)
 				return nil, err
 			}
+			apiConversation.Unread = conversation.ToRead
 
 			// 根据聊天类型填充额外信息
 			if conversation.ChatType == int32(constants.GroupChatType) {
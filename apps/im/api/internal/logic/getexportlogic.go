@@ -0,0 +1,49 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetExportLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetExportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetExportLogic {
+	return &GetExportLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetExport 查询导出任务，导出完成时返回签名的下载链接
+func (l *GetExportLogic) GetExport(req *types.GetExportReq) (resp *types.GetExportResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	job, err := l.svcCtx.ExportJobModel.FindOne(l.ctx, req.Id)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) || errors.Is(err, immodels.ErrInvalidObjectId) {
+			return nil, errors.New("导出任务不存在")
+		}
+		l.Errorf("GetExport FindOne err %v", err)
+		return nil, err
+	}
+	if job.UserId != userId {
+		return nil, errors.New("导出任务不存在")
+	}
+
+	return &types.GetExportResp{Job: toExportJob(l.svcCtx, job)}, nil
+}
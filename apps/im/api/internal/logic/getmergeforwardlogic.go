@@ -0,0 +1,51 @@
+package logic
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jinzhu/copier"
+	"imooc.com/easy-chat/apps/im/rpc/imclient"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetMergeForwardLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetMergeForwardLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetMergeForwardLogic {
+	return &GetMergeForwardLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetMergeForward 展开合并转发的聊天记录
+func (l *GetMergeForwardLogic) GetMergeForward(req *types.GetMergeForwardReq) (resp *types.GetMergeForwardResp, err error) {
+	var path []string
+	if req.Path != "" {
+		path = strings.Split(req.Path, ",")
+	}
+
+	data, err := l.svcCtx.GetMergeForward(l.ctx, &imclient.GetMergeForwardReq{
+		UserId: ctxdata.GetUId(l.ctx),
+		MsgId:  req.MsgId,
+		Path:   path,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var res types.GetMergeForwardResp
+	copier.Copy(&res, data)
+
+	return &res, nil
+}
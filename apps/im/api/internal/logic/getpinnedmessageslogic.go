@@ -0,0 +1,44 @@
+package logic
+
+import (
+	"context"
+
+	"github.com/jinzhu/copier"
+	"imooc.com/easy-chat/apps/im/rpc/imclient"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetPinnedMessagesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetPinnedMessagesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetPinnedMessagesLogic {
+	return &GetPinnedMessagesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetPinnedMessages 获取会话的置顶消息
+func (l *GetPinnedMessagesLogic) GetPinnedMessages(req *types.GetPinnedMessagesReq) (resp *types.GetPinnedMessagesResp, err error) {
+	data, err := l.svcCtx.GetPinnedMessages(l.ctx, &imclient.GetPinnedMessagesReq{
+		UserId:         ctxdata.GetUId(l.ctx),
+		ConversationId: req.ConversationId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var res types.GetPinnedMessagesResp
+	copier.Copy(&res, data)
+
+	return &res, nil
+}
@@ -0,0 +1,44 @@
+package logic
+
+import (
+	"context"
+
+	"github.com/jinzhu/copier"
+	"imooc.com/easy-chat/apps/im/rpc/imclient"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetRepliesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetRepliesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetRepliesLogic {
+	return &GetRepliesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetReplies 获取引用了某条消息的全部回复
+func (l *GetRepliesLogic) GetReplies(req *types.GetRepliesReq) (resp *types.GetRepliesResp, err error) {
+	data, err := l.svcCtx.GetReplies(l.ctx, &imclient.GetRepliesReq{
+		ConversationId: req.ConversationId,
+		MsgId:          req.MsgId,
+		Count:          req.Count,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var res types.GetRepliesResp
+	copier.Copy(&res, data)
+
+	return &res, nil
+}
@@ -0,0 +1,64 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetRevokeAuditsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetRevokeAuditsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetRevokeAuditsLogic {
+	return &GetRevokeAuditsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetRevokeAudits 获取群聊的撤回审计记录，仅群主和管理员可以查看
+func (l *GetRevokeAuditsLogic) GetRevokeAudits(req *types.GetRevokeAuditsReq) (resp *types.GetRevokeAuditsResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	role, err := groupRole(l.ctx, l.svcCtx, req.ConversationId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !isGroupManagerRole(role) {
+		return nil, errors.New("仅群主和管理员可以查看撤回记录")
+	}
+
+	audits, err := l.svcCtx.RevokeAuditModel.ListByConversationId(l.ctx, req.ConversationId, req.EndRevokeTime, req.Count)
+	if err != nil {
+		l.Errorf("GetRevokeAudits ListByConversationId err %v", err)
+		return nil, err
+	}
+
+	list := make([]*types.RevokeAudit, 0, len(audits))
+	for _, audit := range audits {
+		list = append(list, &types.RevokeAudit{
+			MsgId:        audit.MsgId,
+			OperatorId:   audit.OperatorId,
+			OperatorRole: int32(audit.OperatorRole),
+			SendId:       audit.SendId,
+			MsgType:      int32(audit.MsgType),
+			MsgContent:   audit.MsgContent,
+			SendTime:     audit.SendTime,
+			RevokeTime:   audit.RevokeTime,
+		})
+	}
+
+	return &types.GetRevokeAuditsResp{List: list}, nil
+}
@@ -0,0 +1,21 @@
+package logic
+
+import (
+	"context"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+// groupRole 查询用户在群中的角色，不是群成员时返回 0
+func groupRole(ctx context.Context, svcCtx *svc.ServiceContext, groupId, userId string) (constants.GroupRoleLevel, error) {
+	roleLevel, err := svcCtx.GroupMembers.Role(ctx, groupId, userId)
+	if err != nil {
+		return 0, err
+	}
+	return constants.GroupRoleLevel(roleLevel), nil
+}
+
+func isGroupManagerRole(role constants.GroupRoleLevel) bool {
+	return role == constants.CreatorGroupRoleLevel || role == constants.ManagerGroupRoleLevel
+}
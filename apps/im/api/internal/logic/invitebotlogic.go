@@ -0,0 +1,66 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/social/rpc/socialclient"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type InviteBotLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewInviteBotLogic(ctx context.Context, svcCtx *svc.ServiceContext) *InviteBotLogic {
+	return &InviteBotLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// InviteBot 邀请机器人进群，群主或管理员邀请时无需审核直接进群
+func (l *InviteBotLogic) InviteBot(req *types.InviteBotReq) (resp *types.InviteBotResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	if _, err := ownedBot(l.ctx, l.svcCtx, req.BotId, userId); err != nil {
+		return nil, err
+	}
+	role, err := groupRole(l.ctx, l.svcCtx, req.GroupId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !isGroupManagerRole(role) {
+		return nil, errors.New("只有群主或管理员可以邀请机器人")
+	}
+
+	res, err := l.svcCtx.Social.GroupPutin(l.ctx, &socialclient.GroupPutinReq{
+		GroupId:    req.GroupId,
+		ReqId:      req.BotId,
+		ReqMsg:     "邀请机器人",
+		ReqTime:    time.Now().UnixMilli(),
+		JoinSource: int32(constants.InviteGroupJoinSource),
+		InviterUid: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.GroupId == "" {
+		// 机器人已在群中或已有待处理的申请
+		l.Infof("InviteBot bot %v not joined group %v directly", req.BotId, req.GroupId)
+	}
+
+	return &types.InviteBotResp{}, nil
+}
@@ -0,0 +1,69 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListAnnouncementsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListAnnouncementsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListAnnouncementsLogic {
+	return &ListAnnouncementsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListAnnouncements 获取系统公告及其发送进度，按创建时间倒序
+func (l *ListAnnouncementsLogic) ListAnnouncements(req *types.ListAnnouncementsReq) (resp *types.ListAnnouncementsResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+	if !isAdmin(l.svcCtx, userId) {
+		return nil, errors.New("仅管理员可以查看系统公告")
+	}
+
+	announcements, err := l.svcCtx.AnnouncementModel.List(l.ctx, req.Cursor, req.Count)
+	if err != nil {
+		if errors.Is(err, immodels.ErrInvalidCursor) {
+			return nil, errors.New("分页游标无效")
+		}
+		l.Errorf("ListAnnouncements List err %v", err)
+		return nil, err
+	}
+
+	resp = &types.ListAnnouncementsResp{
+		List: make([]*types.Announcement, 0, len(announcements)),
+	}
+	for _, announcement := range announcements {
+		resp.List = append(resp.List, &types.Announcement{
+			Id:         announcement.ID.Hex(),
+			MsgType:    int32(announcement.MsgType),
+			MsgContent: announcement.MsgContent,
+			CreateBy:   announcement.CreateBy,
+			Target:     announcement.Target,
+			Status:     int32(announcement.Status),
+			SentCount:  announcement.SentCount,
+			Error:      announcement.LastErr,
+			CreateTime: announcement.CreateAt.UnixMilli(),
+			FinishTime: announcement.FinishAt,
+		})
+	}
+	if len(announcements) > 0 {
+		resp.Cursor = announcements[len(announcements)-1].ID.Hex()
+	}
+	return resp, nil
+}
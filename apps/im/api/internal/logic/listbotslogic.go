@@ -0,0 +1,44 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListBotsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListBotsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListBotsLogic {
+	return &ListBotsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListBotsLogic) ListBots(req *types.ListBotsReq) (resp *types.ListBotsResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	bots, err := l.svcCtx.BotModel.ListByOwner(l.ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &types.ListBotsResp{List: make([]*types.Bot, 0, len(bots))}
+	for _, bot := range bots {
+		resp.List = append(resp.List, toBot(bot))
+	}
+	return resp, nil
+}
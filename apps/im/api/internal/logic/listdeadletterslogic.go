@@ -0,0 +1,58 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListDeadLettersLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListDeadLettersLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListDeadLettersLogic {
+	return &ListDeadLettersLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListDeadLetters 获取消费失败的死信，按失败时间倒序
+func (l *ListDeadLettersLogic) ListDeadLetters(req *types.ListDeadLettersReq) (resp *types.ListDeadLettersResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+	if !isAdmin(l.svcCtx, userId) {
+		return nil, errors.New("仅管理员可以查看死信")
+	}
+
+	letters, err := l.svcCtx.DeadLetterModel.List(l.ctx, req.Topic, int(req.Status), req.Cursor, req.Count)
+	if err != nil {
+		if errors.Is(err, immodels.ErrInvalidCursor) {
+			return nil, errors.New("分页游标无效")
+		}
+		l.Errorf("ListDeadLetters List err %v", err)
+		return nil, err
+	}
+
+	resp = &types.ListDeadLettersResp{
+		List: make([]*types.DeadLetter, 0, len(letters)),
+	}
+	for _, letter := range letters {
+		resp.List = append(resp.List, toDeadLetter(letter))
+	}
+	if len(letters) > 0 {
+		resp.Cursor = letters[len(letters)-1].ID.Hex()
+	}
+	return resp, nil
+}
@@ -0,0 +1,50 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 导出任务列表返回的最大条数
+const maxListExports = 50
+
+type ListExportsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListExportsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListExportsLogic {
+	return &ListExportsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListExports 获取当前用户最近的导出任务
+func (l *ListExportsLogic) ListExports(req *types.ListExportsReq) (resp *types.ListExportsResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	data, err := l.svcCtx.ExportJobModel.ListByUserId(l.ctx, userId, maxListExports)
+	if err != nil {
+		l.Errorf("ListExports ListByUserId err %v", err)
+		return nil, err
+	}
+
+	list := make([]*types.ExportJob, 0, len(data))
+	for _, datum := range data {
+		list = append(list, toExportJob(l.svcCtx, datum))
+	}
+
+	return &types.ListExportsResp{List: list}, nil
+}
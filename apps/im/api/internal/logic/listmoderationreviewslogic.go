@@ -0,0 +1,58 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListModerationReviewsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListModerationReviewsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListModerationReviewsLogic {
+	return &ListModerationReviewsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListModerationReviews 获取审核队列，按进入队列的时间倒序
+func (l *ListModerationReviewsLogic) ListModerationReviews(req *types.ListModerationReviewsReq) (resp *types.ListModerationReviewsResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+	if !isAdmin(l.svcCtx, userId) {
+		return nil, errors.New("仅管理员可以查看审核队列")
+	}
+
+	reviews, err := l.svcCtx.ModerationReviewModel.List(l.ctx, int(req.Status), req.Cursor, req.Count)
+	if err != nil {
+		if errors.Is(err, immodels.ErrInvalidCursor) {
+			return nil, errors.New("分页游标无效")
+		}
+		l.Errorf("ListModerationReviews List err %v", err)
+		return nil, err
+	}
+
+	resp = &types.ListModerationReviewsResp{
+		List: make([]*types.ModerationReview, 0, len(reviews)),
+	}
+	for _, review := range reviews {
+		resp.List = append(resp.List, toModerationReview(review))
+	}
+	if len(reviews) > 0 {
+		resp.Cursor = reviews[len(reviews)-1].ID.Hex()
+	}
+	return resp, nil
+}
@@ -0,0 +1,62 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListScheduledMessagesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListScheduledMessagesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListScheduledMessagesLogic {
+	return &ListScheduledMessagesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ListScheduledMessages 获取当前用户的定时消息
+func (l *ListScheduledMessagesLogic) ListScheduledMessages(req *types.ListScheduledMessagesReq) (resp *types.ListScheduledMessagesResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	var status []int
+	if !req.All {
+		status = []int{immodels.ScheduledPending}
+	}
+
+	data, err := l.svcCtx.ScheduledMessageModel.ListBySendId(l.ctx, userId, status)
+	if err != nil {
+		l.Errorf("ListScheduledMessages ListBySendId err %v", err)
+		return nil, err
+	}
+
+	list := make([]*types.ScheduledMessage, 0, len(data))
+	for _, datum := range data {
+		list = append(list, &types.ScheduledMessage{
+			Id:             datum.ID.Hex(),
+			ConversationId: datum.ConversationId,
+			ChatType:       int32(datum.ChatType),
+			RecvId:         datum.RecvId,
+			MsgType:        int32(datum.MsgType),
+			MsgContent:     datum.MsgContent,
+			SendAt:         datum.SendAt,
+			Status:         int32(datum.Status),
+		})
+	}
+
+	return &types.ListScheduledMessagesResp{List: list}, nil
+}
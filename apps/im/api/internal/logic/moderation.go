@@ -0,0 +1,24 @@
+package logic
+
+import (
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+)
+
+func toModerationReview(review *immodels.ModerationReview) *types.ModerationReview {
+	return &types.ModerationReview{
+		Id:             review.ID.Hex(),
+		MsgId:          review.MsgId,
+		ConversationId: review.ConversationId,
+		ChatType:       int32(review.ChatType),
+		SendId:         review.SendId,
+		MsgContent:     review.MsgContent,
+		SendTime:       review.SendTime,
+		Words:          review.Words,
+		Status:         int32(review.Status),
+		ReviewBy:       review.ReviewBy,
+		ReviewNote:     review.ReviewNote,
+		ReviewTime:     review.ReviewTime,
+		CreateTime:     review.CreateAt.UnixMilli(),
+	}
+}
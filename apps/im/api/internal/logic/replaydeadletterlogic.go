@@ -0,0 +1,51 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ReplayDeadLetterLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewReplayDeadLetterLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ReplayDeadLetterLogic {
+	return &ReplayDeadLetterLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ReplayDeadLetter 请求重放死信，由 task 投递到原 topic 重新处理
+func (l *ReplayDeadLetterLogic) ReplayDeadLetter(req *types.ReplayDeadLetterReq) (resp *types.ReplayDeadLetterResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+	if !isAdmin(l.svcCtx, userId) {
+		return nil, errors.New("仅管理员可以重放死信")
+	}
+
+	if err := l.svcCtx.DeadLetterModel.Replay(l.ctx, req.Id, userId); err != nil {
+		switch {
+		case errors.Is(err, immodels.ErrInvalidObjectId):
+			return nil, errors.New("死信不存在")
+		case errors.Is(err, immodels.ErrNotFound):
+			return nil, errors.New("死信不存在或正在等待重放")
+		}
+		l.Errorf("ReplayDeadLetter Replay err %v, id %v", err, req.Id)
+		return nil, err
+	}
+
+	return &types.ReplayDeadLetterResp{}, nil
+}
@@ -0,0 +1,47 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/pkg/ctxdata"
+	"imooc.com/easy-chat/pkg/webhook"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ResetBotKeysLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewResetBotKeysLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ResetBotKeysLogic {
+	return &ResetBotKeysLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ResetBotKeys 重置签名密钥与 token，用于密钥泄露的场景
+func (l *ResetBotKeysLogic) ResetBotKeys(req *types.ResetBotKeysReq) (resp *types.ResetBotKeysResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	bot, err := ownedBot(l.ctx, l.svcCtx, req.BotId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	bot.Secret = webhook.NewSecret()
+	bot.Token = webhook.NewSecret()
+	if err := l.svcCtx.BotModel.ResetKeys(l.ctx, bot.ID, bot.Secret, bot.Token); err != nil {
+		return nil, err
+	}
+	return &types.ResetBotKeysResp{Bot: toBot(bot)}, nil
+}
@@ -0,0 +1,76 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ResolveModerationReviewLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewResolveModerationReviewLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ResolveModerationReviewLogic {
+	return &ResolveModerationReviewLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ResolveModerationReview 处理待审核的消息，不通过时以系统身份撤回消息
+func (l *ResolveModerationReviewLogic) ResolveModerationReview(req *types.ResolveModerationReviewReq) (resp *types.ResolveModerationReviewResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+	if !isAdmin(l.svcCtx, userId) {
+		return nil, errors.New("仅管理员可以处理审核队列")
+	}
+
+	review, err := l.svcCtx.ModerationReviewModel.FindOne(l.ctx, req.Id)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) || errors.Is(err, immodels.ErrInvalidObjectId) {
+			return nil, errors.New("审核记录不存在")
+		}
+		l.Errorf("ResolveModerationReview FindOne err %v, id %v", err, req.Id)
+		return nil, err
+	}
+
+	status := immodels.ReviewApproved
+	if req.Action == "remove" {
+		status = immodels.ReviewRemoved
+	}
+	if err := l.svcCtx.ModerationReviewModel.Resolve(l.ctx, req.Id, status, userId, req.Note); err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			return nil, errors.New("该消息已审核")
+		}
+		l.Errorf("ResolveModerationReview Resolve err %v, id %v", err, req.Id)
+		return nil, err
+	}
+
+	if status == immodels.ReviewRemoved {
+		err = l.svcCtx.MsgRevokeTransferClient.Push(&mq.MsgRevokeTransfer{
+			MsgId:          review.MsgId,
+			ConversationId: review.ConversationId,
+			ChatType:       int32(review.ChatType),
+			SendId:         constants.SYSTEM_ROOT_UID,
+		})
+		if err != nil {
+			l.Errorf("ResolveModerationReview push revoke err %v, msgId %v", err, review.MsgId)
+			return nil, err
+		}
+	}
+
+	return &types.ResolveModerationReviewResp{}, nil
+}
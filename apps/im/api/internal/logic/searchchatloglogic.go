@@ -0,0 +1,122 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jinzhu/copier"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/im/rpc/imclient"
+	"imooc.com/easy-chat/apps/social/rpc/socialclient"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SearchChatLogLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSearchChatLogLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SearchChatLogLogic {
+	return &SearchChatLogLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// SearchChatLog 在当前用户的会话中搜索聊天记录
+// 群聊只搜索用户当前所在的群，退群后不能再搜索到群里的消息
+func (l *SearchChatLogLogic) SearchChatLog(req *types.SearchChatLogReq) (resp *types.SearchChatLogResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+	if strings.TrimSpace(req.Keyword) == "" {
+		return nil, errors.New("搜索关键词不能为空")
+	}
+	if req.StartTime > 0 && req.EndTime > 0 && req.StartTime > req.EndTime {
+		return nil, errors.New("开始时间不能晚于结束时间")
+	}
+
+	conversationIds, err := l.searchableConversations(userId)
+	if err != nil {
+		return nil, err
+	}
+	if len(conversationIds) == 0 {
+		return &types.SearchChatLogResp{List: []*types.SearchHit{}}, nil
+	}
+
+	data, err := l.svcCtx.SearchChatLog(l.ctx, &imclient.SearchChatLogReq{
+		UserId:          userId,
+		Keyword:         req.Keyword,
+		ConversationIds: conversationIds,
+		ConversationId:  req.ConversationId,
+		SendId:          req.SendId,
+		MType:           req.MType,
+		StartTime:       req.StartTime,
+		EndTime:         req.EndTime,
+		Cursor:          req.Cursor,
+		Count:           req.Count,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := types.SearchChatLogResp{List: []*types.SearchHit{}}
+	copier.Copy(&res, data)
+
+	return &res, nil
+}
+
+// searchableConversations 用户可以搜索的会话：全部私聊，以及用户当前所在的群
+func (l *SearchChatLogLogic) searchableConversations(userId string) ([]string, error) {
+	conversations, err := l.svcCtx.ConversationsModel.FindByUserId(l.ctx, userId)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			return nil, nil
+		}
+		l.Errorf("SearchChatLog FindByUserId err %v", err)
+		return nil, err
+	}
+
+	var groups map[string]bool
+	res := make([]string, 0, len(conversations.ConversationList))
+	for conversationId, conversation := range conversations.ConversationList {
+		if conversation.ChatType == constants.GroupChatType {
+			if groups == nil {
+				if groups, err = l.joinedGroups(userId); err != nil {
+					return nil, err
+				}
+			}
+			if !groups[conversationId] {
+				continue
+			}
+		}
+		res = append(res, conversationId)
+	}
+	return res, nil
+}
+
+func (l *SearchChatLogLogic) joinedGroups(userId string) (map[string]bool, error) {
+	groupResp, err := l.svcCtx.Social.GroupList(l.ctx, &socialclient.GroupListReq{
+		UserId: userId,
+	})
+	if err != nil {
+		l.Errorf("SearchChatLog GroupList err %v", err)
+		return nil, err
+	}
+
+	groups := make(map[string]bool, len(groupResp.List))
+	for _, group := range groupResp.List {
+		groups[group.Id] = true
+	}
+	return groups, nil
+}
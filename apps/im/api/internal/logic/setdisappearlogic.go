@@ -0,0 +1,111 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	minDisappearTtl = 5
+	maxDisappearTtl = 7 * 24 * 3600
+)
+
+type SetDisappearLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSetDisappearLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SetDisappearLogic {
+	return &SetDisappearLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// SetDisappear 设置会话的阅后即焚，只对设置之后发送的消息生效
+// 私聊双方都可以设置，群聊仅群主和管理员可以设置
+func (l *SetDisappearLogic) SetDisappear(req *types.SetDisappearReq) (resp *types.SetDisappearResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	mode := constants.DisappearMode(req.Mode)
+	switch mode {
+	case constants.DisappearOff:
+	case constants.DisappearAfterSend, constants.DisappearAfterRead:
+		if req.Ttl < minDisappearTtl || req.Ttl > maxDisappearTtl {
+			return nil, errors.New("阅后即焚时长需在5秒到7天之间")
+		}
+	default:
+		return nil, errors.New("不支持的阅后即焚方式")
+	}
+
+	conversation, err := l.svcCtx.ConversationModel.FindOne(l.ctx, req.ConversationId)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			return nil, errors.New("会话不存在")
+		}
+		l.Errorf("SetDisappear FindOne err %v", err)
+		return nil, err
+	}
+
+	if err := l.checkPermission(userId, conversation); err != nil {
+		return nil, err
+	}
+
+	var disappear *immodels.ConversationDisappear
+	if mode != constants.DisappearOff {
+		disappear = &immodels.ConversationDisappear{
+			Mode:    mode,
+			Ttl:     req.Ttl,
+			SetBy:   userId,
+			SetTime: time.Now().UnixMilli(),
+		}
+	}
+
+	if err := l.svcCtx.ConversationModel.UpdateDisappear(l.ctx, req.ConversationId, disappear); err != nil {
+		l.Errorf("SetDisappear UpdateDisappear err %v", err)
+		return nil, err
+	}
+
+	return &types.SetDisappearResp{}, nil
+}
+
+func (l *SetDisappearLogic) checkPermission(userId string, conversation *immodels.Conversation) error {
+	switch conversation.ChatType {
+	case constants.SingleChatType:
+		conversations, err := l.svcCtx.ConversationsModel.FindByUserId(l.ctx, userId)
+		if err != nil {
+			if errors.Is(err, immodels.ErrNotFound) {
+				return errors.New("无权设置该会话")
+			}
+			return err
+		}
+		if _, ok := conversations.ConversationList[conversation.ConversationId]; !ok {
+			return errors.New("无权设置该会话")
+		}
+	case constants.GroupChatType:
+		role, err := groupRole(l.ctx, l.svcCtx, conversation.ConversationId, userId)
+		if err != nil {
+			return err
+		}
+		if !isGroupManagerRole(role) {
+			return errors.New("仅群主和管理员可以设置阅后即焚")
+		}
+	default:
+		return errors.New("不支持的会话类型")
+	}
+	return nil
+}
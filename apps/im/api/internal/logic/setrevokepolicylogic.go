@@ -0,0 +1,83 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const maxRevokeWindow = 7 * 24 * 3600
+
+type SetRevokePolicyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSetRevokePolicyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SetRevokePolicyLogic {
+	return &SetRevokePolicyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// SetRevokePolicy 设置群聊的撤回策略，仅群主可以设置
+func (l *SetRevokePolicyLogic) SetRevokePolicy(req *types.SetRevokePolicyReq) (resp *types.SetRevokePolicyResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	if req.SenderWindow < 0 || req.SenderWindow > maxRevokeWindow ||
+		req.ManagerWindow < 0 || req.ManagerWindow > maxRevokeWindow {
+		return nil, errors.New("撤回时间窗口不能超过7天")
+	}
+
+	conversation, err := l.svcCtx.ConversationModel.FindOne(l.ctx, req.ConversationId)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			return nil, errors.New("会话不存在")
+		}
+		l.Errorf("SetRevokePolicy FindOne err %v", err)
+		return nil, err
+	}
+	if conversation.ChatType != constants.GroupChatType {
+		return nil, errors.New("仅群聊支持设置撤回策略")
+	}
+
+	role, err := groupRole(l.ctx, l.svcCtx, conversation.ConversationId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if role != constants.CreatorGroupRoleLevel {
+		return nil, errors.New("仅群主可以设置撤回策略")
+	}
+
+	var policy *immodels.ConversationRevokePolicy
+	if !req.Reset {
+		policy = &immodels.ConversationRevokePolicy{
+			SenderWindow:     req.SenderWindow,
+			ManagerRevoke:    req.ManagerRevoke,
+			ManagerWindow:    req.ManagerWindow,
+			CreatorUnlimited: req.CreatorUnlimited,
+			SetBy:            userId,
+			SetTime:          time.Now().UnixMilli(),
+		}
+	}
+
+	if err := l.svcCtx.ConversationModel.UpdateRevokePolicy(l.ctx, req.ConversationId, policy); err != nil {
+		l.Errorf("SetRevokePolicy UpdateRevokePolicy err %v", err)
+		return nil, err
+	}
+
+	return &types.SetRevokePolicyResp{}, nil
+}
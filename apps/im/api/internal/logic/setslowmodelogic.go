@@ -0,0 +1,79 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const maxSlowModeInterval = 3600
+
+type SetSlowModeLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSetSlowModeLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SetSlowModeLogic {
+	return &SetSlowModeLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// SetSlowMode 设置群聊的慢速模式，群主和管理员可以设置
+func (l *SetSlowModeLogic) SetSlowMode(req *types.SetSlowModeReq) (resp *types.SetSlowModeResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	if req.Interval < 0 || req.Interval > maxSlowModeInterval {
+		return nil, errors.New("发言间隔不能超过1小时")
+	}
+
+	conversation, err := l.svcCtx.ConversationModel.FindOne(l.ctx, req.ConversationId)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			return nil, errors.New("会话不存在")
+		}
+		l.Errorf("SetSlowMode FindOne err %v", err)
+		return nil, err
+	}
+	if conversation.ChatType != constants.GroupChatType {
+		return nil, errors.New("仅群聊支持设置慢速模式")
+	}
+
+	role, err := groupRole(l.ctx, l.svcCtx, conversation.ConversationId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !isGroupManagerRole(role) {
+		return nil, errors.New("仅群主和管理员可以设置慢速模式")
+	}
+
+	var slowMode *immodels.ConversationSlowMode
+	if req.Interval > 0 {
+		slowMode = &immodels.ConversationSlowMode{
+			Interval: req.Interval,
+			SetBy:    userId,
+			SetTime:  time.Now().UnixMilli(),
+		}
+	}
+
+	if err := l.svcCtx.ConversationModel.UpdateSlowMode(l.ctx, req.ConversationId, slowMode); err != nil {
+		l.Errorf("SetSlowMode UpdateSlowMode err %v", err)
+		return nil, err
+	}
+
+	return &types.SetSlowModeResp{}, nil
+}
@@ -0,0 +1,47 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"imooc.com/easy-chat/apps/im/api/internal/svc"
+	"imooc.com/easy-chat/apps/im/api/internal/types"
+	"imooc.com/easy-chat/pkg/ctxdata"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateBotWebhookLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateBotWebhookLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateBotWebhookLogic {
+	return &UpdateBotWebhookLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// UpdateBotWebhook 修改 outgoing webhook，地址为空时不再向机器人投递消息
+func (l *UpdateBotWebhookLogic) UpdateBotWebhook(req *types.UpdateBotWebhookReq) (resp *types.UpdateBotWebhookResp, err error) {
+	userId := ctxdata.GetUId(l.ctx)
+	if userId == "" {
+		return nil, errors.New("用户未登录")
+	}
+
+	if _, err := ownedBot(l.ctx, l.svcCtx, req.BotId, userId); err != nil {
+		return nil, err
+	}
+	events, err := checkBotWebhook(req.WebhookUrl, req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.svcCtx.BotModel.UpdateWebhook(l.ctx, req.BotId, req.WebhookUrl, events); err != nil {
+		return nil, err
+	}
+	return &types.UpdateBotWebhookResp{}, nil
+}
@@ -1,33 +1,85 @@
 package svc
 
 import (
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/stores/redis"
 	"github.com/zeromicro/go-zero/zrpc"
 	"imooc.com/easy-chat/apps/im/api/internal/config"
 	"imooc.com/easy-chat/apps/im/immodels"
 	"imooc.com/easy-chat/apps/im/rpc/imclient"
 	"imooc.com/easy-chat/apps/social/rpc/socialclient"
+	"imooc.com/easy-chat/apps/task/mq/mqclient"
 	"imooc.com/easy-chat/apps/user/rpc/userclient"
+	"imooc.com/easy-chat/pkg/membership"
+	"imooc.com/easy-chat/pkg/mqx"
 )
 
 type ServiceContext struct {
 	Config config.Config
 
+	*redis.Redis
+
 	imclient.Im
 	userclient.User
 	socialclient.Social
 
 	immodels.UserMessageDeletesModel
 	immodels.ConversationsModel
+	immodels.ConversationModel
+	immodels.ScheduledMessageModel
+	immodels.ReadCursorModel
+	immodels.RevokeAuditModel
+	immodels.ExportJobModel
+	immodels.ModerationReviewModel
+	immodels.AnnouncementModel
+	immodels.BotModel
+	immodels.DeadLetterModel
+
+	MsgRevokeTransferClient mqclient.MsgRevokeTransferClient
+	MsgChatTransferClient   mqclient.MsgChatTransferClient
+
+	GroupMembers *membership.Cache
 }
 
+// 下载链接签名密钥的最小长度
+const minExportSignSecretLen = 16
+
 func NewServiceContext(c config.Config) *ServiceContext {
-	return &ServiceContext{
+	// 下载链接不需要登录，密钥为空或过短时链接可以被伪造，也不能复用签发 token 的密钥
+	if len(c.Export.SignSecret) < minExportSignSecretLen {
+		panic(fmt.Sprintf("Export.SignSecret must be at least %d bytes", minExportSignSecretLen))
+	}
+	if c.Export.SignSecret == c.JwtAuth.AccessSecret {
+		panic("Export.SignSecret must differ from JwtAuth.AccessSecret")
+	}
+
+	svc := &ServiceContext{
 		Config: c,
+		Redis:  redis.MustNewRedis(c.Redisx),
 
 		Im:                      imclient.NewIm(zrpc.MustNewClient(c.ImRpc)),
 		User:                    userclient.NewUser(zrpc.MustNewClient(c.UserRpc)),
 		Social:                  socialclient.NewSocial(zrpc.MustNewClient(c.SocialRpc)),
 		UserMessageDeletesModel: immodels.MustUserMessageDeletesModel(c.Mongo.Url, c.Mongo.Db),
 		ConversationsModel:      immodels.MustConversationsModel(c.Mongo.Url, c.Mongo.Db),
+		ConversationModel:       immodels.MustConversationModel(c.Mongo.Url, c.Mongo.Db),
+		ScheduledMessageModel:   immodels.MustScheduledMessageModel(c.Mongo.Url, c.Mongo.Db),
+		ReadCursorModel:         immodels.MustReadCursorModel(c.Mongo.Url, c.Mongo.Db),
+		RevokeAuditModel:        immodels.MustRevokeAuditModel(c.Mongo.Url, c.Mongo.Db),
+		ExportJobModel:          immodels.MustExportJobModel(c.Mongo.Url, c.Mongo.Db),
+		ModerationReviewModel:   immodels.MustModerationReviewModel(c.Mongo.Url, c.Mongo.Db),
+		AnnouncementModel:       immodels.MustAnnouncementModel(c.Mongo.Url, c.Mongo.Db),
+		BotModel:                immodels.MustBotModel(c.Mongo.Url, c.Mongo.Db),
+		DeadLetterModel:         immodels.MustDeadLetterModel(c.Mongo.Url, c.Mongo.Db),
+		MsgRevokeTransferClient: mqclient.NewMsgRevokeTransferClient(
+			mqx.NewProducer(c.Queue, c.MsgRevokeTransfer.Addrs, c.MsgRevokeTransfer.Topic)),
+		MsgChatTransferClient: mqclient.NewMsgChatTransferClient(
+			mqx.NewProducer(c.Queue, c.MsgChatTransfer.Addrs, c.MsgChatTransfer.Topic)),
 	}
+	svc.GroupMembers = membership.NewCache(socialclient.NewMembershipLoader(svc.Social),
+		membership.WithRedis(svc.Redis), membership.WithConf(c.GroupMembers))
+	svc.GroupMembers.Watch(c.Redisx)
+
+	return svc
 }
@@ -2,14 +2,45 @@
 package types
 
 type ChatLog struct {
-	Id             string `json:"id"`
-	ConversationId string `json:"conversationId"`
-	SendId         string `json:"sendId"`
-	RecvId         string `json:"recvId"`
-	MsgType        int32  `json:"msgType"`
-	MsgContent     string `json:"msgContent"`
-	ChatType       int32  `json:"chatType"`
-	SendTime       int64  `json:"SendTime"`
+	Id             string             `json:"id"`
+	ConversationId string             `json:"conversationId"`
+	SendId         string             `json:"sendId"`
+	RecvId         string             `json:"recvId"`
+	MsgType        int32              `json:"msgType"`
+	MsgContent     string             `json:"msgContent"`
+	ChatType       int32              `json:"chatType"`
+	SendTime       int64              `json:"SendTime"`
+	Seq            int64              `json:"seq"`
+	IsEdited       bool               `json:"isEdited"`
+	EditTime       int64              `json:"editTime"`
+	Quote          *ChatLogQuote      `json:"quote,omitempty"`
+	Reactions      []*ChatLogReaction `json:"reactions,omitempty"`
+	MergeForward   *MergeForwardCard  `json:"mergeForward,omitempty"`
+	ExpireAt       int64              `json:"expireAt,omitempty"`     // 阅后即焚的过期时间（毫秒）
+	DisappearTtl   int64              `json:"disappearTtl,omitempty"` // 阅后即焚的存活时长（秒）
+	LinkPreview    *LinkPreview       `json:"linkPreview,omitempty"`  // 消息中第一个链接的预览
+	MsgFrom        int32              `json:"msgFrom,omitempty"`      // 消息来源，1 表示机器人
+}
+
+type LinkPreview struct {
+	Url         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+	SiteName    string `json:"siteName,omitempty"`
+}
+
+type ChatLogQuote struct {
+	MsgId   string `json:"msgId"`
+	SendId  string `json:"sendId"`
+	MsgType int32  `json:"msgType"`
+	Snippet string `json:"snippet"`
+}
+
+type ChatLogReaction struct {
+	Emoji   string `json:"emoji"`
+	Count   int64  `json:"count"`
+	Reacted bool   `json:"reacted"`
 }
 
 type ChatLogReq struct {
@@ -17,10 +48,17 @@ type ChatLogReq struct {
 	StartSendTime  int64  `form:"startSendTime,omitempty"`
 	EndSendTime    int64  `form:"endSendTime,omitempty"`
 	Count          int64  `form:"count,omitempty"`
+	Direction      int32  `form:"direction,optional"` // 按游标翻页：1 向前（更早），2 向后（更新）；为 0 时按发送时间查询
+	Cursor         string `form:"cursor,optional"`
+	AroundMsgId    string `form:"aroundMsgId,optional"` // 定位到某条消息，返回该消息及其前后各 count 条消息
 }
 
 type ChatLogResp struct {
-	List []*ChatLog `json:"list"`
+	List          []*ChatLog `json:"list"`
+	HasMoreBefore bool       `json:"hasMoreBefore"`
+	HasMoreAfter  bool       `json:"hasMoreAfter"`
+	BeforeCursor  string     `json:"beforeCursor"`
+	AfterCursor   string     `json:"afterCursor"`
 }
 
 type Conversation struct {
@@ -32,9 +70,14 @@ type Conversation struct {
 	Read           int32  `json:"read"`
 	Total          int32  `json:"total"`
 	Unread         int32  `json:"unread"`
-	Name           string `json:"name"`        // 会话名称（好友昵称或群名称）
-	Avatar         string `json:"avatar"`      // 会话头像（好友头像或群头像）
-	MemberCount    int32  `json:"memberCount"` // 群成员数量（仅群聊时有效）
+	Name           string `json:"name"`          // 会话名称（好友昵称或群名称）
+	Avatar         string `json:"avatar"`        // 会话头像（好友头像或群头像）
+	MemberCount    int32  `json:"memberCount"`   // 群成员数量（仅群聊时有效）
+	MentionMsgId   string `json:"mentionMsgId"`  // 最早一条未读的@我的消息
+	DisappearMode  int32  `json:"disappearMode"` // 阅后即焚的计时方式，0 表示关闭
+	DisappearTtl   int64  `json:"disappearTtl"`  // 阅后即焚的存活时长（秒）
+	ReadSeq        int64  `json:"readSeq"`       // 已读游标，seq 为会话最新的消息序号
+	SlowMode       int64  `json:"slowMode"`      // 慢速模式的发言间隔（秒），0 表示关闭
 }
 
 type GetChatLogReadRecordsReq struct {
@@ -42,8 +85,73 @@ type GetChatLogReadRecordsReq struct {
 }
 
 type GetChatLogReadRecordsResp struct {
-	Reads   []string `json:"reads"`
-	UnReads []string `json:"unReads"`
+	Reads     []string `json:"reads"`
+	UnReads   []string `json:"unReads"`
+	Delivered []string `json:"delivered"` // 已送达未读
+	Pending   []string `json:"pending"`   // 未送达
+}
+
+type GetRepliesReq struct {
+	ConversationId string `form:"conversationId"`
+	MsgId          string `form:"msgId"`
+	Count          int64  `form:"count,optional"`
+}
+
+type GetRepliesResp struct {
+	List []*ChatLog `json:"list"`
+}
+
+type MergeForwardCard struct {
+	ConversationId string   `json:"conversationId"`
+	Title          string   `json:"title"`
+	Summary        []string `json:"summary"`
+	Count          int32    `json:"count"`
+}
+
+type PinnedMessage struct {
+	Msg     *ChatLog `json:"msg"`
+	PinBy   string   `json:"pinBy"`
+	PinTime int64    `json:"pinTime"`
+}
+
+type GetPinnedMessagesReq struct {
+	ConversationId string `form:"conversationId"`
+}
+
+type GetPinnedMessagesResp struct {
+	List []*PinnedMessage `json:"list"`
+}
+
+type SearchChatLogReq struct {
+	Keyword        string `form:"keyword"`
+	ConversationId string `form:"conversationId,optional"`
+	SendId         string `form:"sendId,optional"`
+	MType          int32  `form:"mType,default=-1"` // 消息类型，-1 表示不限
+	StartTime      int64  `form:"startTime,optional"`
+	EndTime        int64  `form:"endTime,optional"`
+	Cursor         string `form:"cursor,optional"`
+	Count          int64  `form:"count,optional"`
+}
+
+type SearchHit struct {
+	Msg     *ChatLog `json:"msg"`
+	Snippet string   `json:"snippet"` // 命中词语用 <em></em> 标记的内容摘要
+}
+
+type SearchChatLogResp struct {
+	List       []*SearchHit `json:"list"`
+	NextCursor string       `json:"nextCursor"`
+}
+
+type GetMergeForwardReq struct {
+	MsgId string `form:"msgId"`
+	Path  string `form:"path,optional"` // 展开嵌套的合并转发，逗号分隔的各层原消息ID
+}
+
+type GetMergeForwardResp struct {
+	ConversationId string     `json:"conversationId"`
+	Title          string     `json:"title"`
+	List           []*ChatLog `json:"list"`
 }
 
 type GetConversationsReq struct {
@@ -84,3 +192,309 @@ type ClearConversationReq struct {
 
 type ClearConversationResp struct {
 }
+
+type SetDisappearReq struct {
+	ConversationId string `json:"conversationId"`
+	Mode           int32  `json:"mode"`
+	Ttl            int64  `json:"ttl,optional"`
+}
+
+type SetDisappearResp struct {
+}
+
+type SetRevokePolicyReq struct {
+	ConversationId   string `json:"conversationId"`
+	SenderWindow     int64  `json:"senderWindow,optional"`
+	ManagerRevoke    bool   `json:"managerRevoke,optional"`
+	ManagerWindow    int64  `json:"managerWindow,optional"`
+	CreatorUnlimited bool   `json:"creatorUnlimited,optional"`
+	Reset            bool   `json:"reset,optional"`
+}
+
+type SetRevokePolicyResp struct {
+}
+
+type SetSlowModeReq struct {
+	ConversationId string `json:"conversationId"`
+	Interval       int64  `json:"interval,optional"`
+}
+
+type SetSlowModeResp struct {
+}
+
+type RevokeAudit struct {
+	MsgId        string `json:"msgId"`
+	OperatorId   string `json:"operatorId"`
+	OperatorRole int32  `json:"operatorRole"`
+	SendId       string `json:"sendId"`
+	MsgType      int32  `json:"msgType"`
+	MsgContent   string `json:"msgContent"`
+	SendTime     int64  `json:"sendTime"`
+	RevokeTime   int64  `json:"revokeTime"`
+}
+
+type GetRevokeAuditsReq struct {
+	ConversationId string `form:"conversationId"`
+	EndRevokeTime  int64  `form:"endRevokeTime,optional"`
+	Count          int64  `form:"count,optional"`
+}
+
+type GetRevokeAuditsResp struct {
+	List []*RevokeAudit `json:"list"`
+}
+
+type ScheduledMessage struct {
+	Id             string `json:"id"`
+	ConversationId string `json:"conversationId"`
+	ChatType       int32  `json:"chatType"`
+	RecvId         string `json:"recvId"`
+	MsgType        int32  `json:"msgType"`
+	MsgContent     string `json:"msgContent"`
+	SendAt         int64  `json:"sendAt"`
+	Status         int32  `json:"status"`
+}
+
+type CreateScheduledMessageReq struct {
+	ChatType   int32  `json:"chatType"`
+	RecvId     string `json:"recvId"`
+	MsgType    int32  `json:"msgType,optional"`
+	MsgContent string `json:"msgContent"`
+	SendAt     int64  `json:"sendAt"` // 计划发送时间戳（毫秒）
+}
+
+type CreateScheduledMessageResp struct {
+	Id string `json:"id"`
+}
+
+type ListScheduledMessagesReq struct {
+	All bool `form:"all,optional"` // 为 true 时返回全部状态，否则只返回等待发送的消息
+}
+
+type ListScheduledMessagesResp struct {
+	List []*ScheduledMessage `json:"list"`
+}
+
+type CancelScheduledMessageReq struct {
+	Id string `json:"id"`
+}
+
+type CancelScheduledMessageResp struct {
+}
+
+type ExportJob struct {
+	Id             string `json:"id"`
+	ConversationId string `json:"conversationId"`
+	Format         string `json:"format"`
+	Status         int32  `json:"status"` // 0 等待导出，1 导出中，2 完成，3 失败，4 已过期
+	MsgCount       int32  `json:"msgCount"`
+	Truncated      bool   `json:"truncated,omitempty"` // 消息数超过导出上限，只导出了最新的 msgCount 条
+	FileSize       int64  `json:"fileSize"`
+	CreateTime     int64  `json:"createTime"`
+	FinishTime     int64  `json:"finishTime"`
+	ExpireAt       int64  `json:"expireAt"`
+	Error          string `json:"error,omitempty"`
+	DownloadUrl    string `json:"downloadUrl,omitempty"` // 签名的下载链接，导出完成后有值，过期后需重新获取
+}
+
+type CreateExportReq struct {
+	ConversationId string `json:"conversationId"`
+	Format         string `json:"format,options=json|html|csv"` // json、html 或 csv
+}
+
+type CreateExportResp struct {
+	Id string `json:"id"`
+}
+
+type GetExportReq struct {
+	Id string `form:"id"`
+}
+
+type GetExportResp struct {
+	Job *ExportJob `json:"job"`
+}
+
+type ListExportsReq struct {
+}
+
+type ListExportsResp struct {
+	List []*ExportJob `json:"list"`
+}
+
+type DownloadExportReq struct {
+	Id      string `form:"id"`
+	Expires int64  `form:"expires"`
+	Sign    string `form:"sign"`
+}
+
+type ModerationReview struct {
+	Id             string   `json:"id"`
+	MsgId          string   `json:"msgId"`
+	ConversationId string   `json:"conversationId"`
+	ChatType       int32    `json:"chatType"`
+	SendId         string   `json:"sendId"`
+	MsgContent     string   `json:"msgContent"`
+	SendTime       int64    `json:"sendTime"`
+	Words          []string `json:"words"`
+	Status         int32    `json:"status"` // 0 待审核，1 已通过，2 已撤回
+	ReviewBy       string   `json:"reviewBy,omitempty"`
+	ReviewNote     string   `json:"reviewNote,omitempty"`
+	ReviewTime     int64    `json:"reviewTime,omitempty"`
+	CreateTime     int64    `json:"createTime"`
+}
+
+type ListModerationReviewsReq struct {
+	Status int32  `form:"status,default=0"` // 审核状态，-1 查询全部
+	Cursor string `form:"cursor,optional"`  // 分页游标，取上一页返回的 cursor
+	Count  int64  `form:"count,optional"`
+}
+
+type ListModerationReviewsResp struct {
+	List   []*ModerationReview `json:"list"`
+	Cursor string              `json:"cursor"`
+}
+
+type ResolveModerationReviewReq struct {
+	Id     string `json:"id"`
+	Action string `json:"action,options=approve|remove"` // approve 保留消息，remove 撤回消息
+	Note   string `json:"note,optional"`
+}
+
+type ResolveModerationReviewResp struct {
+}
+
+type Announcement struct {
+	Id         string `json:"id"`
+	MsgType    int32  `json:"msgType"`
+	MsgContent string `json:"msgContent"`
+	CreateBy   string `json:"createBy"`
+	Target     string `json:"target"`
+	Status     int32  `json:"status"` // 0 等待发送，1 发送中，2 发送完成，3 发送失败
+	SentCount  int64  `json:"sentCount"`
+	Error      string `json:"error,omitempty"`
+	CreateTime int64  `json:"createTime"`
+	FinishTime int64  `json:"finishTime"`
+}
+
+type CreateAnnouncementReq struct {
+	MsgType       int32    `json:"msgType,optional"`
+	MsgContent    string   `json:"msgContent"`
+	Target        string   `json:"target,options=all|users|group|registered"` // 接收范围：all 全部用户，users 指定用户，group 指定群的成员，registered 指定时间段内注册的用户
+	UserIds       []string `json:"userIds,optional"`
+	GroupId       string   `json:"groupId,optional"`
+	RegisterStart int64    `json:"registerStart,optional"` // 注册时间的范围（毫秒），左闭右开，0 表示不限
+	RegisterEnd   int64    `json:"registerEnd,optional"`
+}
+
+type CreateAnnouncementResp struct {
+	Id string `json:"id"`
+}
+
+type ListAnnouncementsReq struct {
+	Cursor string `form:"cursor,optional"` // 分页游标，取上一页返回的 cursor
+	Count  int64  `form:"count,optional"`
+}
+
+type ListAnnouncementsResp struct {
+	List   []*Announcement `json:"list"`
+	Cursor string          `json:"cursor"`
+}
+
+type Bot struct {
+	Id         string   `json:"id"`
+	Name       string   `json:"name"`
+	Avatar     string   `json:"avatar,omitempty"`
+	WebhookUrl string   `json:"webhookUrl,omitempty"`
+	Events     []string `json:"events,omitempty"` // 订阅的事件：message 所在群的全部消息，command 以 / 开头的命令消息
+	Secret     string   `json:"secret"`           // outgoing webhook 的签名密钥
+	Token      string   `json:"token"`            // incoming webhook 的 token，请求 /v1/im/bot/hook/{token} 向机器人所在的群发送消息
+	CreateTime int64    `json:"createTime"`
+}
+
+type CreateBotReq struct {
+	Name       string   `json:"name"`
+	Avatar     string   `json:"avatar,optional"`
+	WebhookUrl string   `json:"webhookUrl,optional"`
+	Events     []string `json:"events,optional"`
+}
+
+type CreateBotResp struct {
+	Bot *Bot `json:"bot"`
+}
+
+type ListBotsReq struct {
+}
+
+type ListBotsResp struct {
+	List []*Bot `json:"list"`
+}
+
+type UpdateBotWebhookReq struct {
+	BotId      string   `json:"botId"`
+	WebhookUrl string   `json:"webhookUrl,optional"`
+	Events     []string `json:"events,optional"`
+}
+
+type UpdateBotWebhookResp struct {
+}
+
+type ResetBotKeysReq struct {
+	BotId string `json:"botId"`
+}
+
+type ResetBotKeysResp struct {
+	Bot *Bot `json:"bot"`
+}
+
+type InviteBotReq struct {
+	BotId   string `json:"botId"`
+	GroupId string `json:"groupId"`
+}
+
+type InviteBotResp struct {
+}
+
+type BotHookReq struct {
+	Token    string   `path:"token"`
+	GroupId  string   `json:"groupId"`
+	MsgType  int32    `json:"msgType,optional"`
+	Content  string   `json:"content"`
+	Mentions []string `json:"mentions,optional"`
+}
+
+type BotHookResp struct {
+}
+
+type DeadLetter struct {
+	Id          string `json:"id"`
+	Topic       string `json:"topic"`
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Error       string `json:"error"`
+	Attempts    int32  `json:"attempts"`
+	Permanent   bool   `json:"permanent"`
+	FailTime    int64  `json:"failTime"`
+	Status      int32  `json:"status"` // 0 待处理，1 等待重放，2 已重放
+	ReplayBy    string `json:"replayBy,omitempty"`
+	ReplayTime  int64  `json:"replayTime,omitempty"`
+	ReplayCount int32  `json:"replayCount,omitempty"`
+	ReplayError string `json:"replayError,omitempty"`
+}
+
+type ListDeadLettersReq struct {
+	Topic  string `form:"topic,optional"`   // 原消息的 topic，为空时查询全部
+	Status int32  `form:"status,default=0"` // 死信状态，-1 查询全部
+	Cursor string `form:"cursor,optional"`  // 分页游标，取上一页返回的 cursor
+	Count  int64  `form:"count,optional"`
+}
+
+type ListDeadLettersResp struct {
+	List   []*DeadLetter `json:"list"`
+	Cursor string        `json:"cursor"`
+}
+
+type ReplayDeadLetterReq struct {
+	Id string `json:"id"`
+}
+
+type ReplayDeadLetterResp struct {
+}
@@ -0,0 +1,234 @@
+package immodels
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/mon"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+// 系统公告的状态
+const (
+	AnnouncementPending = iota // 等待发送
+	AnnouncementSending        // 已被某个 task 实例领取，发送中
+	AnnouncementDone           // 发送完成
+	AnnouncementFailed         // 多次重试后仍发送失败
+)
+
+// 系统公告的接收范围
+const (
+	// AnnouncementToAll 全部用户
+	AnnouncementToAll = "all"
+	// AnnouncementToUsers 指定的用户
+	AnnouncementToUsers = "users"
+	// AnnouncementToGroup 指定群的成员
+	AnnouncementToGroup = "group"
+	// AnnouncementToRegistered 在指定时间段内注册的用户
+	AnnouncementToRegistered = "registered"
+)
+
+// Announcement 系统公告，由 task 以系统用户的身份分批发送到每个接收者的系统会话中
+type Announcement struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	MsgType    constants.MType    `bson:"msgType"`
+	MsgContent string             `bson:"msgContent"`
+	CreateBy   string             `bson:"createBy"`
+
+	// Target 接收范围，UserIds、GroupId、RegisterStart 与 RegisterEnd 按范围取值
+	Target  string   `bson:"target"`
+	UserIds []string `bson:"userIds,omitempty"`
+	GroupId string   `bson:"groupId,omitempty"`
+	// RegisterStart 与 RegisterEnd 为注册时间的范围（毫秒），左闭右开，0 表示不限
+	RegisterStart int64 `bson:"registerStart,omitempty"`
+	RegisterEnd   int64 `bson:"registerEnd,omitempty"`
+
+	Status int `bson:"status"`
+	// Cursor 接收者按用户ID排序后已发送到的位置，中断后从这里继续
+	Cursor    string `bson:"cursor,omitempty"`
+	SentCount int64  `bson:"sentCount"`
+
+	// ClaimBy 领取该公告的 task 实例，ClaimAt 为领取或最近一次汇报进度的时间（毫秒）
+	ClaimBy string `bson:"claimBy,omitempty"`
+	ClaimAt int64  `bson:"claimAt,omitempty"`
+
+	RetryCount int    `bson:"retryCount"`
+	LastErr    string `bson:"lastErr,omitempty"`
+	FinishAt   int64  `bson:"finishAt,omitempty"`
+
+	UpdateAt time.Time `bson:"updateAt,omitempty"`
+	CreateAt time.Time `bson:"createAt,omitempty"`
+}
+
+var _ AnnouncementModel = (*defaultAnnouncementModel)(nil)
+
+type AnnouncementModel interface {
+	Insert(ctx context.Context, data *Announcement) error
+	FindOne(ctx context.Context, id string) (*Announcement, error)
+	List(ctx context.Context, beforeId string, limit int64) ([]*Announcement, error)
+	Claim(ctx context.Context, owner string, now, lease int64) (*Announcement, error)
+	Progress(ctx context.Context, id primitive.ObjectID, owner, cursor string, sent int64, now int64) error
+	MarkDone(ctx context.Context, id primitive.ObjectID, owner string) error
+	Release(ctx context.Context, id primitive.ObjectID, owner string, failed bool, lastErr string) error
+}
+
+type defaultAnnouncementModel struct {
+	conn *mon.Model
+}
+
+func NewAnnouncementModel(url, db string) AnnouncementModel {
+	conn := mon.MustNewModel(url, db, "announcement")
+	return &defaultAnnouncementModel{conn: conn}
+}
+
+func MustAnnouncementModel(url, db string) AnnouncementModel {
+	return NewAnnouncementModel(url, db)
+}
+
+func (m *defaultAnnouncementModel) Insert(ctx context.Context, data *Announcement) error {
+	data.ID = primitive.NewObjectID()
+	data.Status = AnnouncementPending
+	data.CreateAt = time.Now()
+	data.UpdateAt = time.Now()
+	_, err := m.conn.InsertOne(ctx, data)
+	return err
+}
+
+func (m *defaultAnnouncementModel) FindOne(ctx context.Context, id string) (*Announcement, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidObjectId
+	}
+
+	var data Announcement
+
+	err = m.conn.FindOne(ctx, &data, bson.M{"_id": oid})
+	switch err {
+	case nil:
+		return &data, nil
+	case mon.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// List 按创建时间倒序查询公告，beforeId 不为空时只查询更早的公告
+func (m *defaultAnnouncementModel) List(ctx context.Context, beforeId string, limit int64) ([]*Announcement, error) {
+	var data []*Announcement
+
+	filter := bson.M{}
+	if beforeId != "" {
+		oid, err := primitive.ObjectIDFromHex(beforeId)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		filter["_id"] = bson.M{"$lt": oid}
+	}
+	if limit <= 0 {
+		limit = DefaultChatLogLimit
+	}
+
+	err := m.conn.Find(ctx, &data, filter, options.Find().SetSort(bson.M{"_id": -1}).SetLimit(limit))
+	switch err {
+	case nil:
+		return data, nil
+	case mon.ErrNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// Claim 领取一个待发送的公告，同一个公告同一时间只会被一个实例领取
+// 已领取但超过租期 lease（毫秒）没有汇报进度的公告视为领取者异常，可被重新领取并从 Cursor 继续发送
+func (m *defaultAnnouncementModel) Claim(ctx context.Context, owner string, now, lease int64) (*Announcement, error) {
+	var data Announcement
+
+	err := m.conn.FindOneAndUpdate(ctx, &data, bson.M{
+		"$or": []bson.M{
+			{"status": AnnouncementPending},
+			{"status": AnnouncementSending, "claimAt": bson.M{"$lt": now - lease}},
+		},
+	}, bson.M{
+		"$set": bson.M{
+			"status":   AnnouncementSending,
+			"claimBy":  owner,
+			"claimAt":  now,
+			"updateAt": time.Now(),
+		},
+	}, options.FindOneAndUpdate().SetSort(bson.M{"_id": 1}).SetReturnDocument(options.After))
+	switch err {
+	case nil:
+		return &data, nil
+	case mon.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// Progress 记录发送进度并续租，公告已被其他实例领取时返回 ErrNotFound
+func (m *defaultAnnouncementModel) Progress(ctx context.Context, id primitive.ObjectID, owner, cursor string,
+	sent int64, now int64) error {
+	res, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id":     id,
+		"claimBy": owner,
+		"status":  AnnouncementSending,
+	}, bson.M{
+		"$set": bson.M{"cursor": cursor, "claimAt": now, "updateAt": time.Now()},
+		"$inc": bson.M{"sentCount": sent},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MarkDone 标记为发送完成，只有当前领取者可以标记
+func (m *defaultAnnouncementModel) MarkDone(ctx context.Context, id primitive.ObjectID, owner string) error {
+	res, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id":     id,
+		"claimBy": owner,
+		"status":  AnnouncementSending,
+	}, bson.M{
+		"$set": bson.M{
+			"status":   AnnouncementDone,
+			"finishAt": time.Now().UnixMilli(),
+			"updateAt": time.Now(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Release 发送失败后释放领取，已发送的进度保留，failed 为 true 时不再重试
+func (m *defaultAnnouncementModel) Release(ctx context.Context, id primitive.ObjectID, owner string, failed bool,
+	lastErr string) error {
+	status := AnnouncementPending
+	if failed {
+		status = AnnouncementFailed
+	}
+
+	_, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id":     id,
+		"claimBy": owner,
+		"status":  AnnouncementSending,
+	}, bson.M{
+		"$set":   bson.M{"status": status, "lastErr": lastErr, "updateAt": time.Now()},
+		"$unset": bson.M{"claimBy": "", "claimAt": ""},
+		"$inc":   bson.M{"retryCount": 1},
+	})
+	return err
+}
@@ -0,0 +1,161 @@
+package immodels
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/mon"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// 机器人订阅的事件
+const (
+	// BotEventMessage 所在群的全部消息
+	BotEventMessage = "message"
+	// BotEventCommand 所在群中以 / 开头的命令消息
+	BotEventCommand = "command"
+	// BotEventCardAction 机器人发送的卡片上的按钮被点击，无需订阅
+	BotEventCardAction = "card_action"
+)
+
+// Bot 机器人的 webhook 配置，ID 为机器人账号的用户ID
+//
+// 机器人通过 WebhookUrl 接收订阅的群消息，请求使用 Secret 签名；
+// 通过 Token 调用 incoming webhook 向所在的群发送消息
+type Bot struct {
+	ID         string   `bson:"_id"`
+	OwnerId    string   `bson:"ownerId"`
+	Name       string   `bson:"name"`
+	Avatar     string   `bson:"avatar,omitempty"`
+	WebhookUrl string   `bson:"webhookUrl,omitempty"`
+	Events     []string `bson:"events,omitempty"`
+	Secret     string   `bson:"secret"`
+	Token      string   `bson:"token"`
+
+	CreateAt time.Time `bson:"createAt,omitempty"`
+	UpdateAt time.Time `bson:"updateAt,omitempty"`
+}
+
+// Subscribed 是否订阅了事件
+func (b *Bot) Subscribed(event string) bool {
+	for _, e := range b.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+var _ BotModel = (*defaultBotModel)(nil)
+
+type BotModel interface {
+	Insert(ctx context.Context, data *Bot) error
+	FindOne(ctx context.Context, id string) (*Bot, error)
+	FindByToken(ctx context.Context, token string) (*Bot, error)
+	FindByIds(ctx context.Context, ids []string) ([]*Bot, error)
+	ListByOwner(ctx context.Context, ownerId string) ([]*Bot, error)
+	UpdateWebhook(ctx context.Context, id, webhookUrl string, events []string) error
+	ResetKeys(ctx context.Context, id, secret, token string) error
+}
+
+type defaultBotModel struct {
+	conn *mon.Model
+}
+
+func NewBotModel(url, db string) BotModel {
+	conn := mon.MustNewModel(url, db, "bot")
+	return &defaultBotModel{conn: conn}
+}
+
+func MustBotModel(url, db string) BotModel {
+	return NewBotModel(url, db)
+}
+
+func (m *defaultBotModel) Insert(ctx context.Context, data *Bot) error {
+	data.CreateAt = time.Now()
+	data.UpdateAt = time.Now()
+	_, err := m.conn.InsertOne(ctx, data)
+	return err
+}
+
+func (m *defaultBotModel) FindOne(ctx context.Context, id string) (*Bot, error) {
+	return m.findOne(ctx, bson.M{"_id": id})
+}
+
+func (m *defaultBotModel) FindByToken(ctx context.Context, token string) (*Bot, error) {
+	if token == "" {
+		return nil, ErrNotFound
+	}
+	return m.findOne(ctx, bson.M{"token": token})
+}
+
+func (m *defaultBotModel) findOne(ctx context.Context, filter bson.M) (*Bot, error) {
+	var data Bot
+
+	err := m.conn.FindOne(ctx, &data, filter)
+	switch err {
+	case nil:
+		return &data, nil
+	case mon.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindByIds 查询用户中的机器人，ids 中不是机器人的用户会被忽略
+func (m *defaultBotModel) FindByIds(ctx context.Context, ids []string) ([]*Bot, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var data []*Bot
+
+	err := m.conn.Find(ctx, &data, bson.M{"_id": bson.M{"$in": ids}})
+	switch err {
+	case nil:
+		return data, nil
+	case mon.ErrNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultBotModel) ListByOwner(ctx context.Context, ownerId string) ([]*Bot, error) {
+	var data []*Bot
+
+	err := m.conn.Find(ctx, &data, bson.M{"ownerId": ownerId}, options.Find().SetSort(bson.M{"createAt": -1}))
+	switch err {
+	case nil:
+		return data, nil
+	case mon.ErrNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultBotModel) UpdateWebhook(ctx context.Context, id, webhookUrl string, events []string) error {
+	_, err := m.conn.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{
+			"webhookUrl": webhookUrl,
+			"events":     events,
+			"updateAt":   time.Now(),
+		},
+	})
+	return err
+}
+
+// ResetKeys 重置签名密钥与 incoming webhook 的 token，旧的 token 立即失效
+func (m *defaultBotModel) ResetKeys(ctx context.Context, id, secret, token string) error {
+	_, err := m.conn.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{
+			"secret":   secret,
+			"token":    token,
+			"updateAt": time.Now(),
+		},
+	})
+	return err
+}
@@ -1,6 +1,13 @@
 package immodels
 
-import "github.com/zeromicro/go-zero/core/stores/mon"
+import (
+	"context"
+
+	"github.com/zeromicro/go-zero/core/stores/mon"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
 
 var _ ChatLogModel = (*customChatLogModel)(nil)
 
@@ -9,6 +16,19 @@ type (
 	// and implement the added methods in customChatLogModel.
 	ChatLogModel interface {
 		chatLogModel
+		UpdateEdit(ctx context.Context, id primitive.ObjectID, content string, editTime int64, prev *ChatLogEdit) error
+		ListByQuoteMsgId(ctx context.Context, conversationId, msgId string, limit int64) ([]*ChatLog, error)
+		UpdateReaction(ctx context.Context, id primitive.ObjectID, emoji, userId string, add bool, maxPerUser int) (*ChatLog, error)
+		StartExpire(ctx context.Context, id primitive.ObjectID, expireAt int64) error
+		TakeExpired(ctx context.Context, now int64) (*ChatLog, error)
+		UpdateReceipt(ctx context.Context, id primitive.ObjectID, deliveredRecords, readRecords []byte) error
+		FindOneBySeq(ctx context.Context, conversationId string, seq int64) (*ChatLog, error)
+		CountAfterSeq(ctx context.Context, conversationId, readerId string, seq, limit int64) (int64, error)
+		StartExpireBySeq(ctx context.Context, conversationId, readerId string, fromSeq, toSeq, now int64) error
+		ListByCursor(ctx context.Context, conversationId string, cursor *ChatLogCursor, before bool, limit int64) ([]*ChatLog, error)
+		ListAfter(ctx context.Context, field string, value int64, afterId primitive.ObjectID, limit int64) ([]*ChatLog, error)
+		SetLinkPreview(ctx context.Context, id primitive.ObjectID, content string, preview *ChatLogLinkPreview) error
+		UpdateCard(ctx context.Context, id primitive.ObjectID, prevContent, content string, state map[string]string) error
 	}
 
 	customChatLogModel struct {
@@ -27,3 +47,318 @@ func NewChatLogModel(url, db, collection string) ChatLogModel {
 func MustChatLogModel(url, db string) ChatLogModel {
 	return NewChatLogModel(url, db, "chat_log")
 }
+
+// UpdateEdit 更新消息内容，并把旧内容追加到编辑历史中
+// 已撤回的消息不会被更新
+func (m *customChatLogModel) UpdateEdit(ctx context.Context, id primitive.ObjectID, content string, editTime int64,
+	prev *ChatLogEdit) error {
+	res, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id":    id,
+		"status": bson.M{"$ne": 4},
+	}, bson.M{
+		"$set": bson.M{
+			"msgContent": content,
+			"isEdited":   true,
+			"editTime":   editTime,
+		},
+		// 链接预览属于旧内容，由 task 按新内容重新抓取
+		"$unset": bson.M{"linkPreview": ""},
+		"$push":  bson.M{"editHistory": prev},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListByQuoteMsgId 查询会话中引用了某条消息的全部回复，按发送时间正序
+func (m *customChatLogModel) ListByQuoteMsgId(ctx context.Context, conversationId, msgId string, limit int64) ([]*ChatLog, error) {
+	var data []*ChatLog
+
+	opt := options.FindOptions{
+		Limit: &DefaultChatLogLimit,
+		Sort: bson.M{
+			"sendTime": 1,
+		},
+	}
+	if limit > 0 {
+		opt.Limit = &limit
+	}
+
+	err := m.conn.Find(ctx, &data, bson.M{
+		"conversationId": conversationId,
+		"quote.msgId":    msgId,
+	}, &opt)
+	switch err {
+	case nil:
+		return data, nil
+	case mon.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// UpdateReaction 添加或取消用户对消息的表情回应，返回更新后的消息
+// 已撤回的消息不能回应；添加时用户在该消息上的表情数在同一次更新中校验，不超过 maxPerUser
+func (m *customChatLogModel) UpdateReaction(ctx context.Context, id primitive.ObjectID, emoji, userId string,
+	add bool, maxPerUser int) (*ChatLog, error) {
+	filter := bson.M{
+		"_id":    id,
+		"status": bson.M{"$ne": 4},
+	}
+	op := "$pull"
+	if add {
+		op = "$addToSet"
+		if maxPerUser > 0 {
+			// 已回应过该表情时不受限制，否则该用户回应过的表情数必须小于上限
+			filter["$or"] = bson.A{
+				bson.M{"reactions." + emoji: userId},
+				bson.M{"$expr": bson.M{"$lt": bson.A{
+					bson.M{"$size": bson.M{"$filter": bson.M{
+						"input": bson.M{"$objectToArray": bson.M{"$ifNull": bson.A{"$reactions", bson.M{}}}},
+						"cond":  bson.M{"$in": bson.A{userId, "$$this.v"}},
+					}}},
+					maxPerUser,
+				}}},
+			}
+		}
+	}
+
+	var data ChatLog
+	err := m.conn.FindOneAndUpdate(ctx, &data, filter, bson.M{
+		op: bson.M{"reactions." + emoji: userId},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+	switch err {
+	case nil:
+		return &data, nil
+	case mon.ErrNotFound:
+		if _, ok := filter["$or"]; !ok {
+			return nil, ErrNotFound
+		}
+		// 没有匹配时区分消息已撤回与超过上限
+		count, err := m.conn.CountDocuments(ctx, bson.M{"_id": id, "status": bson.M{"$ne": 4}})
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, ErrReactionLimit
+		}
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// StartExpire 首次阅读后开始计时，已经开始计时的消息不会被更新
+func (m *customChatLogModel) StartExpire(ctx context.Context, id primitive.ObjectID, expireAt int64) error {
+	_, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id":          id,
+		"disappearTtl": bson.M{"$gt": 0},
+		"expireAt":     bson.M{"$exists": false},
+	}, bson.M{
+		"$set": bson.M{"expireAt": expireAt},
+	})
+	return err
+}
+
+// TakeExpired 删除一条已过期的消息并返回，多个实例同时调用时同一条消息只会被一个实例取到
+func (m *customChatLogModel) TakeExpired(ctx context.Context, now int64) (*ChatLog, error) {
+	var data ChatLog
+
+	err := m.conn.FindOneAndDelete(ctx, &data, bson.M{
+		"expireAt": bson.M{"$gt": 0, "$lte": now},
+	}, options.FindOneAndDelete().SetSort(bson.M{"expireAt": 1}))
+	switch err {
+	case nil:
+		return &data, nil
+	case mon.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// UpdateReceipt 更新消息的送达与已读记录，readRecords 为 nil 时只更新送达
+// 消息状态只会前进：已读不会回退为已送达，已撤回的消息状态保持不变
+func (m *customChatLogModel) UpdateReceipt(ctx context.Context, id primitive.ObjectID, deliveredRecords,
+	readRecords []byte) error {
+	set := bson.M{"deliveredRecords": deliveredRecords}
+	status := ChatLogStatusDelivered
+	if readRecords != nil {
+		set["readRecords"] = readRecords
+		status = ChatLogStatusRead
+	}
+
+	_, err := m.conn.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": set,
+		"$max": bson.M{"status": status},
+	})
+	return err
+}
+
+// FindOneBySeq 根据会话内的序号查询消息
+func (m *customChatLogModel) FindOneBySeq(ctx context.Context, conversationId string, seq int64) (*ChatLog, error) {
+	var data ChatLog
+
+	err := m.conn.FindOne(ctx, &data, bson.M{
+		"conversationId": conversationId,
+		"seq":            seq,
+	})
+	switch err {
+	case nil:
+		return &data, nil
+	case mon.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// CountAfterSeq 统计会话中序号大于 seq 的、别人发送的消息数，最多统计 limit 条
+// 序号在入库失败等情况下会有空缺，未读数不能直接由序号相减得到
+func (m *customChatLogModel) CountAfterSeq(ctx context.Context, conversationId, readerId string, seq,
+	limit int64) (int64, error) {
+	return m.conn.CountDocuments(ctx, bson.M{
+		"conversationId": conversationId,
+		"seq":            bson.M{"$gt": seq},
+		"sendId":         bson.M{"$ne": readerId},
+	}, options.Count().SetLimit(limit))
+}
+
+// StartExpireBySeq 成员已读到 toSeq 时，为 (fromSeq, toSeq] 中别人发送的、首次阅读后计时的阅后即焚消息开始计时
+func (m *customChatLogModel) StartExpireBySeq(ctx context.Context, conversationId, readerId string, fromSeq, toSeq,
+	now int64) error {
+	_, err := m.conn.UpdateMany(ctx, bson.M{
+		"conversationId": conversationId,
+		"seq":            bson.M{"$gt": fromSeq, "$lte": toSeq},
+		"sendId":         bson.M{"$ne": readerId},
+		"disappearTtl":   bson.M{"$gt": 0},
+		"expireAt":       bson.M{"$exists": false},
+	}, []bson.M{{
+		"$set": bson.M{
+			"expireAt": bson.M{"$add": bson.A{now, bson.M{"$multiply": bson.A{"$disappearTtl", 1000}}}},
+		},
+	}})
+	return err
+}
+
+// ListByCursor 按 (seq, _id) 查询游标之前（before 为 true）或之后的 limit 条消息，不包含游标处的消息
+// cursor 为 nil 时从最新（before）或最早的消息开始；结果都按从新到旧排序
+func (m *customChatLogModel) ListByCursor(ctx context.Context, conversationId string, cursor *ChatLogCursor,
+	before bool, limit int64) ([]*ChatLog, error) {
+	var data []*ChatLog
+
+	op, order := "$gt", 1
+	if before {
+		op, order = "$lt", -1
+	}
+
+	filter := bson.M{"conversationId": conversationId}
+	if cursor != nil {
+		// 早期的消息没有 seq 字段，排在有序号的消息之前
+		noSeq := bson.M{"seq": bson.M{"$exists": false}}
+		switch {
+		case cursor.Seq == 0:
+			noSeq["_id"] = bson.M{op: cursor.ID}
+			filter["$or"] = bson.A{noSeq}
+			if !before {
+				filter["$or"] = bson.A{noSeq, bson.M{"seq": bson.M{"$gt": 0}}}
+			}
+		case before:
+			filter["$or"] = bson.A{
+				noSeq,
+				bson.M{"seq": bson.M{"$lt": cursor.Seq}},
+				bson.M{"seq": cursor.Seq, "_id": bson.M{"$lt": cursor.ID}},
+			}
+		default:
+			filter["$or"] = bson.A{
+				bson.M{"seq": bson.M{"$gt": cursor.Seq}},
+				bson.M{"seq": cursor.Seq, "_id": bson.M{"$gt": cursor.ID}},
+			}
+		}
+	}
+
+	if limit <= 0 {
+		limit = DefaultChatLogLimit
+	}
+	err := m.conn.Find(ctx, &data, filter, options.Find().
+		SetSort(bson.D{{Key: "seq", Value: order}, {Key: "_id", Value: order}}).
+		SetLimit(limit))
+	if err != nil && err != mon.ErrNotFound {
+		return nil, err
+	}
+
+	if !before {
+		for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+	return data, nil
+}
+
+// ListAfter 按 (field, _id) 正序查询排在 (value, afterId) 之后的消息，用于增量同步
+// field 为 sendTime 时同步新消息，为 editTime 时同步被编辑的消息
+func (m *customChatLogModel) ListAfter(ctx context.Context, field string, value int64, afterId primitive.ObjectID,
+	limit int64) ([]*ChatLog, error) {
+	var data []*ChatLog
+
+	err := m.conn.Find(ctx, &data, bson.M{
+		"$or": bson.A{
+			bson.M{field: bson.M{"$gt": value}},
+			bson.M{field: value, "_id": bson.M{"$gt": afterId}},
+		},
+	}, options.Find().SetSort(bson.D{{Key: field, Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit))
+	switch err {
+	case nil:
+		return data, nil
+	case mon.ErrNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// SetLinkPreview 保存链接预览，消息已撤回或内容已被编辑时不更新，返回 ErrNotFound
+func (m *customChatLogModel) SetLinkPreview(ctx context.Context, id primitive.ObjectID, content string,
+	preview *ChatLogLinkPreview) error {
+	res, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id":        id,
+		"msgContent": content,
+		"status":     bson.M{"$ne": 4},
+	}, bson.M{
+		"$set": bson.M{"linkPreview": preview},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UpdateCard 更新卡片内容与状态，prevContent 与当前内容不一致（并发更新）或消息已撤回时返回 ErrNotFound
+func (m *customChatLogModel) UpdateCard(ctx context.Context, id primitive.ObjectID, prevContent, content string,
+	state map[string]string) error {
+	res, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id":        id,
+		"msgContent": prevContent,
+		"status":     bson.M{"$ne": 4},
+	}, bson.M{
+		"$set": bson.M{
+			"msgContent": content,
+			"cardState":  state,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
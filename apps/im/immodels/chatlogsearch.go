@@ -0,0 +1,131 @@
+package immodels
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"imooc.com/easy-chat/pkg/search"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/mon"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ search.Indexer = (*chatLogTextIndex)(nil)
+
+// chatLogTextIndex 基于 Mongo 文本索引的消息搜索
+// 文本索引由 Mongo 在写入 chat_log 时维护，Index 与 Delete 不需要做任何事
+// Mongo 按空白与标点分词，连续的中文只能整段命中，需要按词搜索中文时使用 search.MemoryIndex
+type chatLogTextIndex struct {
+	conn *mon.Model
+}
+
+// NewChatLogTextIndex 创建消息搜索，并确保 chat_log 上存在 msgContent 的文本索引
+func NewChatLogTextIndex(url, db string) search.Indexer {
+	conn := mon.MustNewModel(url, db, "chat_log")
+
+	// 不指定语言，避免对中文做词干处理
+	_, err := conn.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "msgContent", Value: "text"}},
+		Options: options.Index().SetName("msgContent_text").SetDefaultLanguage("none"),
+	})
+	if err != nil {
+		logx.Errorf("create chat_log text index err %v", err)
+	}
+
+	return &chatLogTextIndex{conn: conn}
+}
+
+func (m *chatLogTextIndex) Index(ctx context.Context, docs ...*search.Document) error {
+	return nil
+}
+
+func (m *chatLogTextIndex) Delete(ctx context.Context, msgIds ...string) error {
+	return nil
+}
+
+func (m *chatLogTextIndex) Search(ctx context.Context, q *search.Query) (*search.Result, error) {
+	terms := search.Terms(q.Keyword)
+	if len(terms) == 0 || len(q.Conversations) == 0 {
+		return &search.Result{}, nil
+	}
+
+	// 每个词语用引号包裹，Mongo 会要求全部命中
+	phrases := make([]string, 0, len(terms))
+	for _, term := range terms {
+		phrases = append(phrases, strconv.Quote(term))
+	}
+
+	conversationIds := make([]string, 0, len(q.Conversations))
+	for conversationId := range q.Conversations {
+		conversationIds = append(conversationIds, conversationId)
+	}
+
+	filter := bson.M{
+		"$text":          bson.M{"$search": strings.Join(phrases, " ")},
+		"conversationId": bson.M{"$in": conversationIds},
+		"status":         bson.M{"$ne": 4},
+	}
+	if q.SendId != "" {
+		filter["sendId"] = q.SendId
+	}
+	if q.MType >= 0 {
+		filter["msgType"] = q.MType
+	}
+	sendTime := bson.M{}
+	if q.StartTime > 0 {
+		sendTime["$gte"] = q.StartTime
+	}
+	if q.EndTime > 0 {
+		sendTime["$lte"] = q.EndTime
+	}
+	if len(sendTime) > 0 {
+		filter["sendTime"] = sendTime
+	}
+	if q.Cursor != "" {
+		cursorTime, cursorId, err := search.DecodeCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		oid, err := primitive.ObjectIDFromHex(cursorId)
+		if err != nil {
+			return nil, search.ErrInvalidCursor
+		}
+		filter["$or"] = bson.A{
+			bson.M{"sendTime": bson.M{"$lt": cursorTime}},
+			bson.M{"sendTime": cursorTime, "_id": bson.M{"$lt": oid}},
+		}
+	}
+
+	opt := options.Find().
+		SetSort(bson.D{{Key: "sendTime", Value: -1}, {Key: "_id", Value: -1}}).
+		SetProjection(bson.M{"_id": 1, "conversationId": 1, "sendTime": 1})
+	if q.Limit > 0 {
+		// 多取一条判断是否还有下一页；被 ClearUpTo 过滤掉的消息由调用方补齐
+		opt.SetLimit(int64(q.Limit) + 1)
+	}
+
+	var data []*ChatLog
+	if err := m.conn.Find(ctx, &data, filter, opt); err != nil && err != mon.ErrNotFound {
+		return nil, err
+	}
+
+	res := &search.Result{}
+	if q.Limit > 0 && len(data) > q.Limit {
+		data = data[:q.Limit]
+		last := data[len(data)-1]
+		res.NextCursor = search.EncodeCursor(last.SendTime, last.ID.Hex())
+	}
+	res.Hits = make([]*search.Hit, 0, len(data))
+	for _, chatLog := range data {
+		if chatLog.SendTime <= q.Conversations[chatLog.ConversationId] {
+			continue
+		}
+		res.Hits = append(res.Hits, &search.Hit{MsgId: chatLog.ID.Hex(), SendTime: chatLog.SendTime})
+	}
+	return res, nil
+}
@@ -1,6 +1,9 @@
 package immodels
 
 import (
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"imooc.com/easy-chat/pkg/constants"
@@ -10,6 +13,15 @@ import (
 
 var DefaultChatLogLimit int64 = 100
 
+// UnreadCountLimit 未读数最多统计的消息条数，超过时按该值返回
+const UnreadCountLimit int64 = 999
+
+// 消息的回执状态，见 ChatLog.Status
+const (
+	ChatLogStatusDelivered = 2
+	ChatLogStatusRead      = 3
+)
+
 // ChatLog 聊天记录结构体，存储所有聊天消息的详细信息
 type ChatLog struct {
 	// ID MongoDB文档的唯一标识符
@@ -48,18 +60,287 @@ type ChatLog struct {
 	// SendTime 消息发送时间戳（Unix时间戳，毫秒）
 	SendTime int64 `bson:"sendTime"`
 
+	// Seq 消息在会话中的序号，由 Conversation.Seq 递增生成，用于已读游标
+	// 早期的消息没有序号，为 0
+	Seq int64 `bson:"seq,omitempty"`
+
 	// Status 消息状态
 	// 如：0-发送中, 1-已发送, 2-已送达, 3-已读, 4-已撤回, -1-发送失败
 	Status int `bson:"status"`
 
+	// RevokeBy 撤回消息的用户，Status 为 4 时有值
+	RevokeBy string `bson:"revokeBy,omitempty"`
+
 	// ReadRecords 消息已读记录
 	// 存储哪些用户已读此消息的信息（序列化后的字节数组）
-	// 主要用于群聊中追踪消息的阅读状态
+	// 仅用于没有 Seq 的早期消息，有 Seq 的消息通过 ReadCursor 判断是否已读
 	ReadRecords []byte `bson:"readRecords"`
 
+	// DeliveredRecords 消息送达记录，接收者设备确认收到推送后记录，格式与 ReadRecords 相同
+	// 已读的消息一定已送达
+	DeliveredRecords []byte `bson:"deliveredRecords,omitempty"`
+
+	// IsEdited 消息是否被编辑过
+	IsEdited bool `bson:"isEdited,omitempty"`
+
+	// EditTime 最后一次编辑的时间戳（毫秒）
+	EditTime int64 `bson:"editTime,omitempty"`
+
+	// EditHistory 编辑历史，按编辑先后保存每次编辑前的内容
+	EditHistory []*ChatLogEdit `bson:"editHistory,omitempty"`
+
+	// Quote 引用（回复）的消息
+	// 冗余保存被引用消息的摘要，原消息撤回或删除后引用仍可展示
+	Quote *ChatLogQuote `bson:"quote,omitempty"`
+
+	// Forward 逐条转发时记录消息来源
+	Forward *ChatLogForward `bson:"forward,omitempty"`
+
+	// MergeForward 合并转发的聊天记录，仅 MsgType 为 MergeForwardMType 时有值
+	MergeForward *ChatLogMergeForward `bson:"mergeForward,omitempty"`
+
+	// Mentions 被@的用户ID，@全体成员时为 constants.MentionAll
+	Mentions []string `bson:"mentions,omitempty"`
+
+	// DisappearTtl 阅后即焚的存活时长（秒），0 表示不会过期
+	DisappearTtl int64 `bson:"disappearTtl,omitempty"`
+
+	// ExpireAt 过期时间戳（毫秒），到期后由 task 删除；首次阅读后计时的消息在被阅读前为 0
+	ExpireAt int64 `bson:"expireAt,omitempty"`
+
+	// Reactions 表情回应，emoji -> 回应过的用户ID集合
+	Reactions map[string][]string `bson:"reactions,omitempty"`
+
+	// LinkPreview 消息中第一个链接的预览，由 task 异步抓取后补充
+	LinkPreview *ChatLogLinkPreview `bson:"linkPreview,omitempty"`
+
+	// CardState 卡片的服务端状态，仅 MsgType 为 CardMType 时有值，如投票中 userId -> 选项
+	CardState map[string]string `bson:"cardState,omitempty"`
+
 	// UpdateAt 记录最后更新时间
 	UpdateAt time.Time `bson:"updateAt,omitempty" json:"updateAt,omitempty"`
 
 	// CreateAt 记录创建时间
 	CreateAt time.Time `bson:"createAt,omitempty" json:"createAt,omitempty"`
 }
+
+// ChatLogCursor 聊天记录的翻页位置，消息按 (Seq, ID) 排序
+// 早期没有序号的消息 Seq 为 0，按 ID 排在有序号的消息之前
+type ChatLogCursor struct {
+	Seq int64
+	ID  primitive.ObjectID
+}
+
+func NewChatLogCursor(chatLog *ChatLog) *ChatLogCursor {
+	return &ChatLogCursor{Seq: chatLog.Seq, ID: chatLog.ID}
+}
+
+// String 编码为 seq_id 的形式返回给客户端
+func (c *ChatLogCursor) String() string {
+	return strconv.FormatInt(c.Seq, 10) + "_" + c.ID.Hex()
+}
+
+// ParseChatLogCursor 解析 ChatLogCursor.String 生成的游标
+func ParseChatLogCursor(s string) (*ChatLogCursor, error) {
+	seq, id, ok := strings.Cut(s, "_")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	var (
+		c   ChatLogCursor
+		err error
+	)
+	if c.Seq, err = strconv.ParseInt(seq, 10, 64); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// ChatLogEdit 消息编辑历史中的一个旧版本
+type ChatLogEdit struct {
+	// MsgContent 被替换前的消息内容
+	MsgContent string `bson:"msgContent"`
+
+	// EditTime 该版本被替换的时间戳（毫秒）
+	EditTime int64 `bson:"editTime"`
+}
+
+// ChatLogQuote 被引用消息的冗余信息
+type ChatLogQuote struct {
+	// MsgId 被引用消息的ID
+	MsgId string `bson:"msgId"`
+
+	// SendId 被引用消息的发送者
+	SendId string `bson:"sendId"`
+
+	// MsgType 被引用消息的类型
+	MsgType constants.MType `bson:"msgType"`
+
+	// Snippet 被引用消息的内容摘要
+	Snippet string `bson:"snippet"`
+}
+
+// QuoteSnippetLen 引用摘要保留的最大字符数
+const QuoteSnippetLen = 50
+
+// NewChatLogQuote 根据被引用的消息生成引用信息
+func NewChatLogQuote(chatLog *ChatLog) *ChatLogQuote {
+	snippet := []rune(chatLog.MsgContent)
+	if len(snippet) > QuoteSnippetLen {
+		snippet = append(snippet[:QuoteSnippetLen], []rune("...")...)
+	}
+
+	return &ChatLogQuote{
+		MsgId:   chatLog.ID.Hex(),
+		SendId:  chatLog.SendId,
+		MsgType: chatLog.MsgType,
+		Snippet: string(snippet),
+	}
+}
+
+// ChatLogForward 转发消息的来源
+type ChatLogForward struct {
+	// ConversationId 原消息所在的会话
+	ConversationId string `bson:"conversationId"`
+
+	// MsgId 原消息ID
+	MsgId string `bson:"msgId"`
+
+	// SendId 原消息的发送者
+	SendId string `bson:"sendId"`
+}
+
+// MaxMergeForwardCount 一次合并转发的最大消息数
+const MaxMergeForwardCount = 100
+
+// MergeForwardSummaryCount 合并转发卡片上展示的摘要条数
+const MergeForwardSummaryCount = 4
+
+// ChatLogLinkPreview 链接预览，取自页面的 Open Graph 信息或标题
+type ChatLogLinkPreview struct {
+	Url         string `bson:"url"`
+	Title       string `bson:"title"`
+	Description string `bson:"description,omitempty"`
+	Image       string `bson:"image,omitempty"`
+	SiteName    string `bson:"siteName,omitempty"`
+}
+
+// ChatLogMergeForward 合并转发的聊天记录
+// 转发时把原消息复制一份保存，原消息之后被撤回或删除不影响展开
+type ChatLogMergeForward struct {
+	// ConversationId 原消息所在的会话
+	ConversationId string `bson:"conversationId"`
+
+	// Title 卡片标题，如"群聊的聊天记录"
+	Title string `bson:"title"`
+
+	// Summary 卡片上展示的摘要
+	Summary []string `bson:"summary"`
+
+	// Items 原消息的副本，按发送时间正序
+	Items []*ChatLogForwardItem `bson:"items"`
+}
+
+// ChatLogForwardItem 合并转发中的一条原消息
+type ChatLogForwardItem struct {
+	MsgId      string          `bson:"msgId"`
+	SendId     string          `bson:"sendId"`
+	MsgType    constants.MType `bson:"msgType"`
+	MsgContent string          `bson:"msgContent"`
+	SendTime   int64           `bson:"sendTime"`
+
+	// MergeForward 被转发的消息本身也是合并转发时保留其内容
+	MergeForward *ChatLogMergeForward `bson:"mergeForward,omitempty"`
+}
+
+// NewChatLogMergeForward 根据原消息生成合并转发的内容，chatLogs 需按发送时间正序
+func NewChatLogMergeForward(conversationId, title string, chatLogs []*ChatLog) *ChatLogMergeForward {
+	res := &ChatLogMergeForward{
+		ConversationId: conversationId,
+		Title:          title,
+		Items:          make([]*ChatLogForwardItem, 0, len(chatLogs)),
+	}
+	for _, chatLog := range chatLogs {
+		res.Items = append(res.Items, &ChatLogForwardItem{
+			MsgId:        chatLog.ID.Hex(),
+			SendId:       chatLog.SendId,
+			MsgType:      chatLog.MsgType,
+			MsgContent:   chatLog.MsgContent,
+			SendTime:     chatLog.SendTime,
+			MergeForward: chatLog.MergeForward,
+		})
+		if len(res.Summary) < MergeForwardSummaryCount {
+			res.Summary = append(res.Summary, chatLog.SendId+": "+NewChatLogQuote(chatLog).Snippet)
+		}
+	}
+	return res
+}
+
+// IsExpired 消息是否已过期
+func (m *ChatLog) IsExpired(now int64) bool {
+	return m.ExpireAt > 0 && m.ExpireAt <= now
+}
+
+// ReactionCount 单个表情的聚合结果
+type ReactionCount struct {
+	Emoji   string
+	Count   int
+	Reacted bool
+}
+
+// ReactionCounts 按表情聚合回应人数，userId 不为空时标记该用户是否回应过
+// 结果按人数倒序，人数相同按表情排序，保证输出稳定
+func (m *ChatLog) ReactionCounts(userId string) []*ReactionCount {
+	res := make([]*ReactionCount, 0, len(m.Reactions))
+	for emoji, uids := range m.Reactions {
+		if len(uids) == 0 {
+			continue
+		}
+		rc := &ReactionCount{Emoji: emoji, Count: len(uids)}
+		if userId != "" {
+			for _, uid := range uids {
+				if uid == userId {
+					rc.Reacted = true
+					break
+				}
+			}
+		}
+		res = append(res, rc)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].Emoji < res[j].Emoji
+	})
+	return res
+}
+
+// ReactionCountMap 按表情聚合回应人数，用于推送
+func (m *ChatLog) ReactionCountMap() map[string]int {
+	res := make(map[string]int, len(m.Reactions))
+	for emoji, uids := range m.Reactions {
+		if len(uids) > 0 {
+			res[emoji] = len(uids)
+		}
+	}
+	return res
+}
+
+// UserReactions 返回用户在该消息上回应过的表情
+func (m *ChatLog) UserReactions(userId string) []string {
+	var res []string
+	for emoji, uids := range m.Reactions {
+		for _, uid := range uids {
+			if uid == userId {
+				res = append(res, emoji)
+				break
+			}
+		}
+	}
+	return res
+}
@@ -3,9 +3,13 @@ package immodels
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/zeromicro/go-zero/core/stores/mon"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var _ ConversationModel = (*customConversationModel)(nil)
@@ -16,6 +20,16 @@ type (
 	ConversationModel interface {
 		conversationModel
 		FindOneByConversationIdAndTargetId(ctx context.Context, conversationId, targetId string) (*Conversation, error)
+		UpdateMsgIfLatest(ctx context.Context, chatLog *ChatLog) error
+		UpdateMsgIfNewer(ctx context.Context, chatLog *ChatLog) error
+		AddPin(ctx context.Context, conversationId string, pin *ConversationPin, maxPins int) (bool, error)
+		RemovePin(ctx context.Context, conversationId, msgId string) (bool, error)
+		UpdateDisappear(ctx context.Context, conversationId string, disappear *ConversationDisappear) error
+		ClearMsgIfLatest(ctx context.Context, conversationId string, msgId primitive.ObjectID) error
+		IncrSeq(ctx context.Context, conversationId string) (int64, error)
+		UpdateRevokePolicy(ctx context.Context, conversationId string, policy *ConversationRevokePolicy) error
+		UpdateSlowMode(ctx context.Context, conversationId string, slowMode *ConversationSlowMode) error
+		InsertIfNotExists(ctx context.Context, data *Conversation) error
 	}
 
 	customConversationModel struct {
@@ -52,3 +66,168 @@ func (m *customConversationModel) FindOneByConversationIdAndTargetId(ctx context
 		return nil, err
 	}
 }
+
+// UpdateMsgIfLatest 当 chatLog 是会话的最后一条消息时，刷新会话中的消息摘要
+// 与 UpdateMsg 不同，它不会增加会话的消息总数
+func (m *customConversationModel) UpdateMsgIfLatest(ctx context.Context, chatLog *ChatLog) error {
+	_, err := m.conn.UpdateOne(ctx,
+		bson.M{
+			"conversationId": chatLog.ConversationId,
+			"msg._id":        chatLog.ID,
+		},
+		bson.M{"$set": bson.M{"msg": chatLog}},
+	)
+	return err
+}
+
+// UpdateMsgIfNewer 当 chatLog 的序号大于会话中最后一条消息时，更新消息摘要并增加消息总数
+// 重试与重放的消息不会重复计数，也不会覆盖之后的新消息
+func (m *customConversationModel) UpdateMsgIfNewer(ctx context.Context, chatLog *ChatLog) error {
+	_, err := m.conn.UpdateOne(ctx,
+		bson.M{
+			"conversationId": chatLog.ConversationId,
+			"$or": bson.A{
+				bson.M{"msg.seq": bson.M{"$lt": chatLog.Seq}},
+				bson.M{"msg.seq": bson.M{"$exists": false}},
+			},
+		},
+		bson.M{
+			"$inc": bson.M{"total": 1},
+			"$set": bson.M{"msg": chatLog},
+		},
+	)
+	return err
+}
+
+// AddPin 置顶消息，已置顶或置顶数量达到 maxPins 时不会添加，返回是否添加成功
+func (m *customConversationModel) AddPin(ctx context.Context, conversationId string, pin *ConversationPin,
+	maxPins int) (bool, error) {
+	filter := bson.M{
+		"conversationId": conversationId,
+		"pins.msgId":     bson.M{"$ne": pin.MsgId},
+	}
+	if maxPins > 0 {
+		// pins 数组中不存在第 maxPins 个元素，即数量未达上限
+		filter[fmt.Sprintf("pins.%d", maxPins-1)] = bson.M{"$exists": false}
+	}
+
+	res, err := m.conn.UpdateMany(ctx, filter, bson.M{
+		"$push": bson.M{"pins": pin},
+	})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// RemovePin 取消置顶，返回消息之前是否处于置顶状态
+func (m *customConversationModel) RemovePin(ctx context.Context, conversationId, msgId string) (bool, error) {
+	res, err := m.conn.UpdateMany(ctx, bson.M{
+		"conversationId": conversationId,
+		"pins.msgId":     msgId,
+	}, bson.M{
+		"$pull": bson.M{"pins": bson.M{"msgId": msgId}},
+	})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// UpdateDisappear 更新会话的阅后即焚设置，disappear 为 nil 表示关闭
+func (m *customConversationModel) UpdateDisappear(ctx context.Context, conversationId string,
+	disappear *ConversationDisappear) error {
+	update := bson.M{"$set": bson.M{"disappear": disappear}}
+	if disappear == nil {
+		update = bson.M{"$unset": bson.M{"disappear": ""}}
+	}
+
+	res, err := m.conn.UpdateMany(ctx, bson.M{"conversationId": conversationId}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ClearMsgIfLatest 当 msgId 是会话的最后一条消息时，清除会话中的消息摘要
+func (m *customConversationModel) ClearMsgIfLatest(ctx context.Context, conversationId string, msgId primitive.ObjectID) error {
+	_, err := m.conn.UpdateMany(ctx, bson.M{
+		"conversationId": conversationId,
+		"msg._id":        msgId,
+	}, bson.M{
+		"$unset": bson.M{"msg": ""},
+	})
+	return err
+}
+
+// IncrSeq 递增会话的消息序号并返回新的序号，作为新消息的 ChatLog.Seq
+func (m *customConversationModel) IncrSeq(ctx context.Context, conversationId string) (int64, error) {
+	var data Conversation
+
+	err := m.conn.FindOneAndUpdate(ctx, &data, bson.M{"conversationId": conversationId}, bson.M{
+		"$inc": bson.M{"seq": 1},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+	switch {
+	case err == nil:
+		return data.Seq, nil
+	case errors.Is(err, mon.ErrNotFound):
+		return 0, ErrNotFound
+	default:
+		return 0, err
+	}
+}
+
+// UpdateRevokePolicy 更新群聊的撤回策略，policy 为 nil 表示恢复默认策略
+func (m *customConversationModel) UpdateRevokePolicy(ctx context.Context, conversationId string,
+	policy *ConversationRevokePolicy) error {
+	update := bson.M{"$set": bson.M{"revokePolicy": policy}}
+	if policy == nil {
+		update = bson.M{"$unset": bson.M{"revokePolicy": ""}}
+	}
+
+	res, err := m.conn.UpdateMany(ctx, bson.M{"conversationId": conversationId}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UpdateSlowMode 更新群聊的慢速模式，slowMode 为 nil 表示关闭
+func (m *customConversationModel) UpdateSlowMode(ctx context.Context, conversationId string,
+	slowMode *ConversationSlowMode) error {
+	update := bson.M{"$set": bson.M{"slowMode": slowMode}}
+	if slowMode == nil {
+		update = bson.M{"$unset": bson.M{"slowMode": ""}}
+	}
+
+	res, err := m.conn.UpdateMany(ctx, bson.M{"conversationId": conversationId}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// InsertIfNotExists 会话不存在时创建，已存在时不修改
+func (m *customConversationModel) InsertIfNotExists(ctx context.Context, data *Conversation) error {
+	_, err := m.conn.UpdateOne(ctx, bson.M{"conversationId": data.ConversationId}, bson.M{
+		"$setOnInsert": bson.M{
+			"conversationId": data.ConversationId,
+			"chatType":       data.ChatType,
+			"targetId":       data.TargetId,
+			"isShow":         data.IsShow,
+			"seq":            int64(0),
+			"createAt":       time.Now(),
+			"updateAt":       time.Now(),
+		},
+	}, options.Update().SetUpsert(true))
+	return err
+}
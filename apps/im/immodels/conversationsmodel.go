@@ -1,6 +1,13 @@
 package immodels
 
-import "github.com/zeromicro/go-zero/core/stores/mon"
+import (
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/mon"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
 
 var _ ConversationsModel = (*customConversationsModel)(nil)
 
@@ -9,6 +16,9 @@ type (
 	// and implement the added methods in customConversationsModel.
 	ConversationsModel interface {
 		conversationsModel
+		SetMention(ctx context.Context, userIds []string, conversationId string, mention *ConversationMention) error
+		ClearMention(ctx context.Context, userId, conversationId string, readSendTime int64) error
+		AddIfNotExists(ctx context.Context, userId string, conversation *Conversation) error
 	}
 
 	customConversationsModel struct {
@@ -27,3 +37,58 @@ func NewConversationsModel(url, db, collection string) ConversationsModel {
 func MustConversationsModel(url, db string) ConversationsModel {
 	return NewConversationsModel(url, db, "conversations")
 }
+
+// SetMention 为被@的用户记录"有人@我"标记
+// 只保留最早一条未读的@，已有标记的用户不会被覆盖；没有该会话的用户不处理
+func (m *customConversationsModel) SetMention(ctx context.Context, userIds []string, conversationId string,
+	mention *ConversationMention) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+
+	key := "conversationList." + conversationId
+	_, err := m.conn.UpdateMany(ctx, bson.M{
+		"userId":         bson.M{"$in": userIds},
+		key:              bson.M{"$exists": true},
+		key + ".mention": bson.M{"$exists": false},
+	}, bson.M{
+		"$set": bson.M{key + ".mention": mention},
+	})
+	return err
+}
+
+// ClearMention 用户已读到 readSendTime 时，清除不晚于该时间的@标记
+func (m *customConversationsModel) ClearMention(ctx context.Context, userId, conversationId string, readSendTime int64) error {
+	key := "conversationList." + conversationId + ".mention"
+	_, err := m.conn.UpdateOne(ctx, bson.M{
+		"userId":          userId,
+		key + ".sendTime": bson.M{"$lte": readSendTime},
+	}, bson.M{
+		"$unset": bson.M{key: ""},
+	})
+	return err
+}
+
+// AddIfNotExists 把会话加入用户的会话列表，已存在时不修改；用户还没有会话列表时创建
+func (m *customConversationsModel) AddIfNotExists(ctx context.Context, userId string, conversation *Conversation) error {
+	key := "conversationList." + conversation.ConversationId
+	res, err := m.conn.UpdateOne(ctx, bson.M{
+		"userId": userId,
+		key:      bson.M{"$exists": false},
+	}, bson.M{
+		"$set": bson.M{key: conversation, "updateAt": time.Now()},
+	})
+	if err != nil || res.MatchedCount > 0 {
+		return err
+	}
+
+	_, err = m.conn.UpdateOne(ctx, bson.M{"userId": userId}, bson.M{
+		"$setOnInsert": bson.M{
+			"userId":           userId,
+			"conversationList": bson.M{conversation.ConversationId: conversation},
+			"createAt":         time.Now(),
+			"updateAt":         time.Now(),
+		},
+	}, options.Update().SetUpsert(true))
+	return err
+}
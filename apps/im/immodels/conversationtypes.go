@@ -39,9 +39,104 @@ type Conversation struct {
 	// 清空会话时间戳，用户已删除此时间之前的所有消息
 	ClearUpTo int64 `bson:"clearUpTo,omitempty"`
 
+	// 阅后即焚设置，仅保存在会话(conversation)中
+	Disappear *ConversationDisappear `bson:"disappear,omitempty"`
+
+	// 群聊的撤回策略，为空时使用默认策略，仅保存在会话(conversation)中
+	RevokePolicy *ConversationRevokePolicy `bson:"revokePolicy,omitempty"`
+
+	// 群聊的慢速模式，为空表示关闭，仅保存在会话(conversation)中
+	SlowMode *ConversationSlowMode `bson:"slowMode,omitempty"`
+
+	// 置顶消息，按置顶时间正序，仅保存在会话(conversation)中
+	Pins []*ConversationPin `bson:"pins,omitempty"`
+
+	// 最早一条未读的@我的消息，读到该消息之后清除
+	Mention *ConversationMention `bson:"mention,omitempty"`
+
 	// 会话最后更新时间，每当有新消息时更新
 	UpdateAt time.Time `bson:"updateAt,omitempty" json:"updateAt,omitempty"`
 
 	// 会话创建时间，首次建立会话时设置
 	CreateAt time.Time `bson:"createAt,omitempty" json:"createAt,omitempty"`
 }
+
+// ConversationMention 会话中"有人@我"的标记
+type ConversationMention struct {
+	// MsgId @我的消息ID，用于客户端跳转
+	MsgId string `bson:"msgId"`
+
+	// SendId @我的用户ID
+	SendId string `bson:"sendId"`
+
+	// SendTime 消息发送时间戳（毫秒），已读到该时间之后清除标记
+	SendTime int64 `bson:"sendTime"`
+}
+
+// ConversationPin 会话中的一条置顶消息
+type ConversationPin struct {
+	// MsgId 置顶的消息ID
+	MsgId string `bson:"msgId"`
+
+	// PinBy 置顶操作人
+	PinBy string `bson:"pinBy"`
+
+	// PinTime 置顶时间戳（毫秒）
+	PinTime int64 `bson:"pinTime"`
+}
+
+// ConversationDisappear 会话的阅后即焚设置，只对设置之后发送的消息生效
+type ConversationDisappear struct {
+	// Mode 计时方式
+	Mode constants.DisappearMode `bson:"mode"`
+
+	// Ttl 消息存活时长（秒）
+	Ttl int64 `bson:"ttl"`
+
+	// SetBy 设置人
+	SetBy string `bson:"setBy"`
+
+	// SetTime 设置时间戳（毫秒）
+	SetTime int64 `bson:"setTime"`
+}
+
+// ExpireAt 根据会话设置计算消息的过期时间，首次阅读后计时的消息返回 0
+func (d *ConversationDisappear) ExpireAt(sendTime int64) int64 {
+	if d == nil || d.Mode != constants.DisappearAfterSend {
+		return 0
+	}
+	return sendTime + d.Ttl*1000
+}
+
+// ConversationRevokePolicy 群聊的撤回策略，时间窗口为 0 时使用默认值
+type ConversationRevokePolicy struct {
+	// SenderWindow 发送者撤回自己消息的时间窗口（秒）
+	SenderWindow int64 `bson:"senderWindow"`
+
+	// ManagerRevoke 群主和管理员是否可以撤回他人的消息
+	ManagerRevoke bool `bson:"managerRevoke"`
+
+	// ManagerWindow 群主和管理员撤回他人消息的时间窗口（秒）
+	ManagerWindow int64 `bson:"managerWindow"`
+
+	// CreatorUnlimited 群主撤回消息是否不受时间限制
+	CreatorUnlimited bool `bson:"creatorUnlimited"`
+
+	// SetBy 设置人
+	SetBy string `bson:"setBy"`
+
+	// SetTime 设置时间戳（毫秒）
+	SetTime int64 `bson:"setTime"`
+}
+
+// ConversationSlowMode 群聊的慢速模式，普通成员两次发言之间至少间隔 Interval 秒，群主和管理员不受限制
+type ConversationSlowMode struct {
+	// Interval 发言间隔（秒）
+	Interval int64 `bson:"interval"`
+
+	// SetBy 设置人
+	SetBy string `bson:"setBy"`
+
+	// SetTime 设置时间戳（毫秒）
+	SetTime int64 `bson:"setTime"`
+}
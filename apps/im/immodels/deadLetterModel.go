@@ -0,0 +1,193 @@
+package immodels
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/mon"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// 死信状态
+const (
+	DeadLetterPending = iota
+	// DeadLetterReplaying 管理员已请求重放，等待 task 投递到原 topic
+	DeadLetterReplaying
+	// DeadLetterReplayed 已投递到原 topic
+	DeadLetterReplayed
+)
+
+// DeadLetter task 消费重试后仍然失败的消息，与死信 topic 中的消息一一对应，供管理员查看与重放
+type DeadLetter struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Topic     string             `bson:"topic"`
+	Key       string             `bson:"key"`
+	Value     string             `bson:"value"`
+	Error     string             `bson:"error"`
+	Attempts  int                `bson:"attempts"`
+	Permanent bool               `bson:"permanent"`
+	FailTime  int64              `bson:"failTime"`
+
+	Status      int    `bson:"status"`
+	ReplayBy    string `bson:"replayBy,omitempty"`
+	ReplayTime  int64  `bson:"replayTime,omitempty"`
+	ReplayCount int    `bson:"replayCount,omitempty"`
+	ReplayError string `bson:"replayError,omitempty"`
+
+	CreateAt time.Time `bson:"createAt,omitempty"`
+	UpdateAt time.Time `bson:"updateAt,omitempty"`
+}
+
+var _ DeadLetterModel = (*defaultDeadLetterModel)(nil)
+
+type DeadLetterModel interface {
+	Insert(ctx context.Context, data *DeadLetter) error
+	FindOne(ctx context.Context, id string) (*DeadLetter, error)
+	List(ctx context.Context, topic string, status int, beforeId string, limit int64) ([]*DeadLetter, error)
+	Replay(ctx context.Context, id, replayBy string) error
+	TakeReplay(ctx context.Context) (*DeadLetter, error)
+	ReplayFailed(ctx context.Context, id primitive.ObjectID, replayErr string) error
+}
+
+type defaultDeadLetterModel struct {
+	conn *mon.Model
+}
+
+func NewDeadLetterModel(url, db string) DeadLetterModel {
+	conn := mon.MustNewModel(url, db, "dead_letter")
+	return &defaultDeadLetterModel{conn: conn}
+}
+
+func MustDeadLetterModel(url, db string) DeadLetterModel {
+	return NewDeadLetterModel(url, db)
+}
+
+func (m *defaultDeadLetterModel) Insert(ctx context.Context, data *DeadLetter) error {
+	data.ID = primitive.NewObjectID()
+	data.Status = DeadLetterPending
+	data.CreateAt = time.Now()
+	data.UpdateAt = time.Now()
+	_, err := m.conn.InsertOne(ctx, data)
+	return err
+}
+
+func (m *defaultDeadLetterModel) FindOne(ctx context.Context, id string) (*DeadLetter, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidObjectId
+	}
+
+	var data DeadLetter
+
+	err = m.conn.FindOne(ctx, &data, bson.M{"_id": oid})
+	switch err {
+	case nil:
+		return &data, nil
+	case mon.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// List 按失败时间倒序查询死信；topic 为空时查询全部 topic，status 小于 0 时查询全部状态，
+// beforeId 不为空时只查询更早的记录
+func (m *defaultDeadLetterModel) List(ctx context.Context, topic string, status int, beforeId string,
+	limit int64) ([]*DeadLetter, error) {
+	var data []*DeadLetter
+
+	filter := bson.M{}
+	if topic != "" {
+		filter["topic"] = topic
+	}
+	if status >= 0 {
+		filter["status"] = status
+	}
+	if beforeId != "" {
+		oid, err := primitive.ObjectIDFromHex(beforeId)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		filter["_id"] = bson.M{"$lt": oid}
+	}
+	if limit <= 0 {
+		limit = DefaultChatLogLimit
+	}
+
+	err := m.conn.Find(ctx, &data, filter, options.Find().SetSort(bson.M{"_id": -1}).SetLimit(limit))
+	switch err {
+	case nil:
+		return data, nil
+	case mon.ErrNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// Replay 请求重放死信，已重放过的死信可以再次重放；正在等待重放时返回 ErrNotFound
+func (m *defaultDeadLetterModel) Replay(ctx context.Context, id, replayBy string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidObjectId
+	}
+
+	res, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id":    oid,
+		"status": bson.M{"$ne": DeadLetterReplaying},
+	}, bson.M{
+		"$set": bson.M{
+			"status":   DeadLetterReplaying,
+			"replayBy": replayBy,
+			"updateAt": time.Now(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// TakeReplay 领取一条等待重放的死信并标记为已重放，多个 task 实例不会重复领取；没有时返回 ErrNotFound
+func (m *defaultDeadLetterModel) TakeReplay(ctx context.Context) (*DeadLetter, error) {
+	var data DeadLetter
+
+	err := m.conn.FindOneAndUpdate(ctx, &data, bson.M{
+		"status": DeadLetterReplaying,
+	}, bson.M{
+		"$set": bson.M{
+			"status":     DeadLetterReplayed,
+			"replayTime": time.Now().UnixMilli(),
+			"updateAt":   time.Now(),
+		},
+		"$inc":   bson.M{"replayCount": 1},
+		"$unset": bson.M{"replayError": ""},
+	}, options.FindOneAndUpdate().SetSort(bson.M{"_id": 1}).SetReturnDocument(options.After))
+	switch err {
+	case nil:
+		return &data, nil
+	case mon.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// ReplayFailed 重放时投递失败，恢复为待处理并记录原因，由管理员决定是否再次重放
+func (m *defaultDeadLetterModel) ReplayFailed(ctx context.Context, id primitive.ObjectID, replayErr string) error {
+	_, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id": id,
+	}, bson.M{
+		"$set": bson.M{
+			"status":      DeadLetterPending,
+			"replayError": replayErr,
+			"updateAt":    time.Now(),
+		},
+	})
+	return err
+}
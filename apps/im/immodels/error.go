@@ -9,4 +9,6 @@ import (
 var (
 	ErrNotFound        = mon.ErrNotFound
 	ErrInvalidObjectId = errors.New("invalid objectId")
+	ErrInvalidCursor   = errors.New("invalid cursor")
+	ErrReactionLimit   = errors.New("reaction limit exceeded")
 )
@@ -0,0 +1,324 @@
+package immodels
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/mon"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+var (
+	ErrExportActive      = errors.New("export job already active")
+	ErrExportRateLimited = errors.New("export rate limited")
+)
+
+// 导出任务的状态
+const (
+	ExportPending = iota // 等待导出
+	ExportRunning        // 已被某个 task 实例领取，导出中
+	ExportDone           // 导出完成，可以下载
+	ExportFailed         // 多次重试后仍导出失败
+	ExportExpired        // 归档已过期并被删除
+)
+
+// 导出的格式
+const (
+	ExportFormatJSON = "json"
+	ExportFormatHTML = "html"
+	ExportFormatCSV  = "csv"
+)
+
+// ExportJob 会话聊天记录的导出任务，由 task 异步生成 zip 归档
+type ExportJob struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	UserId         string             `bson:"userId"`
+	ConversationId string             `bson:"conversationId"`
+	ChatType       constants.ChatType `bson:"chatType"`
+	Format         string             `bson:"format"`
+	Status         int                `bson:"status"`
+
+	// ClaimBy 领取该任务的 task 实例，ClaimAt 为领取时间（毫秒），超过租期未完成可被重新领取
+	ClaimBy string `bson:"claimBy,omitempty"`
+	ClaimAt int64  `bson:"claimAt,omitempty"`
+
+	RetryCount int    `bson:"retryCount"`
+	LastErr    string `bson:"lastErr,omitempty"`
+
+	// FileName 归档在导出目录中的文件名，导出完成后有值
+	FileName string `bson:"fileName,omitempty"`
+	FileSize int64  `bson:"fileSize,omitempty"`
+	MsgCount int    `bson:"msgCount,omitempty"`
+	// Truncated 消息数超过导出上限，归档只包含最新的 MsgCount 条
+	Truncated bool `bson:"truncated,omitempty"`
+	// FinishAt 导出完成时间，ExpireAt 归档过期时间（毫秒）
+	FinishAt int64 `bson:"finishAt,omitempty"`
+	ExpireAt int64 `bson:"expireAt,omitempty"`
+
+	UpdateAt time.Time `bson:"updateAt,omitempty"`
+	CreateAt time.Time `bson:"createAt,omitempty"`
+}
+
+// ExportResult 导出完成后的归档信息
+type ExportResult struct {
+	FileName  string
+	FileSize  int64
+	MsgCount  int
+	Truncated bool
+	// ExpireAt 归档过期时间（毫秒）
+	ExpireAt int64
+}
+
+var _ ExportJobModel = (*defaultExportJobModel)(nil)
+
+type ExportJobModel interface {
+	Create(ctx context.Context, data *ExportJob, limit int64, window time.Duration) error
+	FindOne(ctx context.Context, id string) (*ExportJob, error)
+	ListByUserId(ctx context.Context, userId string, limit int64) ([]*ExportJob, error)
+	Claim(ctx context.Context, owner string, now, lease int64) (*ExportJob, error)
+	MarkDone(ctx context.Context, id primitive.ObjectID, owner string, result *ExportResult) error
+	Release(ctx context.Context, id primitive.ObjectID, owner string, failed bool, lastErr string) error
+	TakeExpired(ctx context.Context, now int64) (*ExportJob, error)
+}
+
+type defaultExportJobModel struct {
+	conn *mon.Model
+	// quota 记录每个用户最近创建的任务，用于限制导出频率
+	quota *mon.Model
+}
+
+// exportQuota 用户在频率限制窗口内创建的任务，_id 为用户ID
+type exportQuota struct {
+	UserId  string              `bson:"_id"`
+	Entries []*exportQuotaEntry `bson:"entries"`
+}
+
+type exportQuotaEntry struct {
+	JobId    primitive.ObjectID `bson:"jobId"`
+	CreateAt int64              `bson:"createAt"`
+}
+
+func NewExportJobModel(url, db string) ExportJobModel {
+	conn := mon.MustNewModel(url, db, "export_job")
+	// 每个用户在同一会话中只能有一个等待或导出中的任务
+	_, err := conn.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "userId", Value: 1}, {Key: "conversationId", Value: 1}},
+		Options: options.Index().SetName("userId_conversationId_active").SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": bson.M{"$lte": ExportRunning}}),
+	})
+	if err != nil {
+		logx.Errorf("create export_job active index err %v", err)
+	}
+
+	return &defaultExportJobModel{
+		conn:  conn,
+		quota: mon.MustNewModel(url, db, "export_quota"),
+	}
+}
+
+func MustExportJobModel(url, db string) ExportJobModel {
+	return NewExportJobModel(url, db)
+}
+
+// Create 创建导出任务，用户在 window 内最多创建 limit 个任务，且每个会话同时只能有一个未完成的任务
+// 两项校验都由数据库原子完成，并发请求不会超过限制：
+// 先在频率限制中占用名额，再插入任务，插入因已有未完成的任务失败时归还名额
+func (m *defaultExportJobModel) Create(ctx context.Context, data *ExportJob, limit int64,
+	window time.Duration) error {
+	data.ID = primitive.NewObjectID()
+	data.Status = ExportPending
+	data.CreateAt = time.Now()
+	data.UpdateAt = time.Now()
+
+	if err := m.takeQuota(ctx, data.UserId, data.ID, limit, window); err != nil {
+		return err
+	}
+
+	_, err := m.conn.InsertOne(ctx, data)
+	if err == nil {
+		return nil
+	}
+	if _, pullErr := m.quota.UpdateOne(ctx, bson.M{"_id": data.UserId}, bson.M{
+		"$pull": bson.M{"entries": bson.M{"jobId": data.ID}},
+	}); pullErr != nil {
+		logx.Errorf("export_quota release err %v, userId %v", pullErr, data.UserId)
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrExportActive
+	}
+	return err
+}
+
+// takeQuota 清除窗口之外的记录，窗口内的记录少于 limit 时追加 jobId，在同一次更新中完成
+func (m *defaultExportJobModel) takeQuota(ctx context.Context, userId string, jobId primitive.ObjectID,
+	limit int64, window time.Duration) error {
+	now := time.Now().UnixMilli()
+	recent := bson.M{"$filter": bson.M{
+		"input": bson.M{"$ifNull": bson.A{"$entries", bson.A{}}},
+		"cond":  bson.M{"$gt": bson.A{"$$this.createAt", now - window.Milliseconds()}},
+	}}
+
+	update := bson.A{
+		bson.M{"$set": bson.M{"entries": recent}},
+		bson.M{"$set": bson.M{"entries": bson.M{"$cond": bson.A{
+			bson.M{"$lt": bson.A{bson.M{"$size": "$entries"}, limit}},
+			bson.M{"$concatArrays": bson.A{"$entries", bson.A{bson.M{"jobId": jobId, "createAt": now}}}},
+			"$entries",
+		}}}},
+	}
+	opt := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var quota exportQuota
+	err := m.quota.FindOneAndUpdate(ctx, &quota, bson.M{"_id": userId}, update, opt)
+	if mongo.IsDuplicateKeyError(err) {
+		// 首次导出的并发请求同时插入记录，失败的一方重试时按更新处理
+		err = m.quota.FindOneAndUpdate(ctx, &quota, bson.M{"_id": userId}, update, opt)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range quota.Entries {
+		if entry.JobId == jobId {
+			return nil
+		}
+	}
+	return ErrExportRateLimited
+}
+
+func (m *defaultExportJobModel) FindOne(ctx context.Context, id string) (*ExportJob, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidObjectId
+	}
+
+	var data ExportJob
+
+	err = m.conn.FindOne(ctx, &data, bson.M{"_id": oid})
+	switch err {
+	case nil:
+		return &data, nil
+	case mon.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// ListByUserId 查询用户的导出任务，按创建时间倒序
+func (m *defaultExportJobModel) ListByUserId(ctx context.Context, userId string, limit int64) ([]*ExportJob, error) {
+	var data []*ExportJob
+
+	err := m.conn.Find(ctx, &data, bson.M{"userId": userId},
+		options.Find().SetSort(bson.M{"_id": -1}).SetLimit(limit))
+	switch err {
+	case nil:
+		return data, nil
+	case mon.ErrNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// Claim 领取一个待导出的任务，同一个任务同一时间只会被一个实例领取
+// 已领取但超过租期 lease（毫秒）仍未完成的任务视为领取者异常，可被重新领取
+func (m *defaultExportJobModel) Claim(ctx context.Context, owner string, now, lease int64) (*ExportJob, error) {
+	var data ExportJob
+
+	err := m.conn.FindOneAndUpdate(ctx, &data, bson.M{
+		"$or": []bson.M{
+			{"status": ExportPending},
+			{"status": ExportRunning, "claimAt": bson.M{"$lt": now - lease}},
+		},
+	}, bson.M{
+		"$set": bson.M{
+			"status":   ExportRunning,
+			"claimBy":  owner,
+			"claimAt":  now,
+			"updateAt": time.Now(),
+		},
+	}, options.FindOneAndUpdate().SetSort(bson.M{"_id": 1}).SetReturnDocument(options.After))
+	switch err {
+	case nil:
+		return &data, nil
+	case mon.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// MarkDone 标记为导出完成，只有当前领取者可以标记
+func (m *defaultExportJobModel) MarkDone(ctx context.Context, id primitive.ObjectID, owner string,
+	result *ExportResult) error {
+	res, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id":     id,
+		"claimBy": owner,
+		"status":  ExportRunning,
+	}, bson.M{
+		"$set": bson.M{
+			"status":    ExportDone,
+			"fileName":  result.FileName,
+			"fileSize":  result.FileSize,
+			"msgCount":  result.MsgCount,
+			"truncated": result.Truncated,
+			"finishAt":  time.Now().UnixMilli(),
+			"expireAt":  result.ExpireAt,
+			"updateAt":  time.Now(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Release 导出失败后释放领取，failed 为 true 时不再重试
+func (m *defaultExportJobModel) Release(ctx context.Context, id primitive.ObjectID, owner string, failed bool,
+	lastErr string) error {
+	status := ExportPending
+	if failed {
+		status = ExportFailed
+	}
+
+	_, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id":     id,
+		"claimBy": owner,
+		"status":  ExportRunning,
+	}, bson.M{
+		"$set":   bson.M{"status": status, "lastErr": lastErr, "updateAt": time.Now()},
+		"$unset": bson.M{"claimBy": "", "claimAt": ""},
+		"$inc":   bson.M{"retryCount": 1},
+	})
+	return err
+}
+
+// TakeExpired 把一个归档已过期的任务标记为过期并返回，调用方负责删除归档文件
+func (m *defaultExportJobModel) TakeExpired(ctx context.Context, now int64) (*ExportJob, error) {
+	var data ExportJob
+
+	err := m.conn.FindOneAndUpdate(ctx, &data, bson.M{
+		"status":   ExportDone,
+		"expireAt": bson.M{"$lte": now},
+	}, bson.M{
+		"$set": bson.M{"status": ExportExpired, "updateAt": time.Now()},
+	})
+	switch err {
+	case nil:
+		return &data, nil
+	case mon.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
@@ -0,0 +1,153 @@
+package immodels
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/mon"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+// 审核状态
+const (
+	ReviewPending = iota
+	// ReviewApproved 审核通过，消息保留
+	ReviewApproved
+	// ReviewRemoved 审核不通过，消息已撤回
+	ReviewRemoved
+)
+
+// ModerationReview 命中需审核敏感词的消息，消息照常发送，由管理员审核后决定是否撤回
+type ModerationReview struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	MsgId          string             `bson:"msgId"`
+	ConversationId string             `bson:"conversationId"`
+	ChatType       constants.ChatType `bson:"chatType"`
+	SendId         string             `bson:"sendId"`
+	RecvId         string             `bson:"recvId"`
+	MsgContent     string             `bson:"msgContent"`
+	SendTime       int64              `bson:"sendTime"`
+	// Words 命中的敏感词
+	Words []string `bson:"words"`
+
+	Status     int    `bson:"status"`
+	ReviewBy   string `bson:"reviewBy,omitempty"`
+	ReviewNote string `bson:"reviewNote,omitempty"`
+	ReviewTime int64  `bson:"reviewTime,omitempty"`
+
+	CreateAt time.Time `bson:"createAt,omitempty"`
+	UpdateAt time.Time `bson:"updateAt,omitempty"`
+}
+
+var _ ModerationReviewModel = (*defaultModerationReviewModel)(nil)
+
+type ModerationReviewModel interface {
+	Insert(ctx context.Context, data *ModerationReview) error
+	FindOne(ctx context.Context, id string) (*ModerationReview, error)
+	List(ctx context.Context, status int, beforeId string, limit int64) ([]*ModerationReview, error)
+	Resolve(ctx context.Context, id string, status int, reviewBy, note string) error
+}
+
+type defaultModerationReviewModel struct {
+	conn *mon.Model
+}
+
+func NewModerationReviewModel(url, db string) ModerationReviewModel {
+	conn := mon.MustNewModel(url, db, "moderation_review")
+	return &defaultModerationReviewModel{conn: conn}
+}
+
+func MustModerationReviewModel(url, db string) ModerationReviewModel {
+	return NewModerationReviewModel(url, db)
+}
+
+func (m *defaultModerationReviewModel) Insert(ctx context.Context, data *ModerationReview) error {
+	data.ID = primitive.NewObjectID()
+	data.Status = ReviewPending
+	data.CreateAt = time.Now()
+	data.UpdateAt = time.Now()
+	_, err := m.conn.InsertOne(ctx, data)
+	return err
+}
+
+func (m *defaultModerationReviewModel) FindOne(ctx context.Context, id string) (*ModerationReview, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidObjectId
+	}
+
+	var data ModerationReview
+
+	err = m.conn.FindOne(ctx, &data, bson.M{"_id": oid})
+	switch err {
+	case nil:
+		return &data, nil
+	case mon.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// List 按创建时间倒序查询审核记录；status 小于 0 时查询全部状态，beforeId 不为空时只查询更早的记录
+func (m *defaultModerationReviewModel) List(ctx context.Context, status int, beforeId string,
+	limit int64) ([]*ModerationReview, error) {
+	var data []*ModerationReview
+
+	filter := bson.M{}
+	if status >= 0 {
+		filter["status"] = status
+	}
+	if beforeId != "" {
+		oid, err := primitive.ObjectIDFromHex(beforeId)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		filter["_id"] = bson.M{"$lt": oid}
+	}
+	if limit <= 0 {
+		limit = DefaultChatLogLimit
+	}
+
+	err := m.conn.Find(ctx, &data, filter, options.Find().SetSort(bson.M{"_id": -1}).SetLimit(limit))
+	switch err {
+	case nil:
+		return data, nil
+	case mon.ErrNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// Resolve 处理待审核的记录，记录已被处理时返回 ErrNotFound
+func (m *defaultModerationReviewModel) Resolve(ctx context.Context, id string, status int, reviewBy,
+	note string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidObjectId
+	}
+
+	res, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id":    oid,
+		"status": ReviewPending,
+	}, bson.M{
+		"$set": bson.M{
+			"status":     status,
+			"reviewBy":   reviewBy,
+			"reviewNote": note,
+			"reviewTime": time.Now().UnixMilli(),
+			"updateAt":   time.Now(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
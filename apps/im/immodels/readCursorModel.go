@@ -0,0 +1,133 @@
+package immodels
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/mon"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReadCursor 成员在会话中的已读游标，seq 不大于 ReadSeq 的消息都已读
+// 每个成员在每个会话中只有一条记录，标记已读只需更新一次
+type ReadCursor struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	ConversationId string             `bson:"conversationId"`
+	UserId         string             `bson:"userId"`
+
+	// ReadSeq 已读到的消息序号，见 ChatLog.Seq
+	ReadSeq int64 `bson:"readSeq"`
+
+	UpdateAt time.Time `bson:"updateAt,omitempty"`
+	CreateAt time.Time `bson:"createAt,omitempty"`
+}
+
+var _ ReadCursorModel = (*defaultReadCursorModel)(nil)
+
+type ReadCursorModel interface {
+	Advance(ctx context.Context, conversationId, userId string, readSeq int64) (int64, error)
+	ListByConversationId(ctx context.Context, conversationId string) ([]*ReadCursor, error)
+	ListByUserId(ctx context.Context, userId string) ([]*ReadCursor, error)
+}
+
+type defaultReadCursorModel struct {
+	conn *mon.Model
+}
+
+func NewReadCursorModel(url, db string) ReadCursorModel {
+	conn := mon.MustNewModel(url, db, "read_cursor")
+	// 每个成员在每个会话中只有一条游标，并发的首次标记已读不会重复创建
+	_, err := conn.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "conversationId", Value: 1}, {Key: "userId", Value: 1}},
+		Options: options.Index().SetName("conversationId_userId").SetUnique(true),
+	})
+	if err != nil {
+		logx.Errorf("create read_cursor unique index err %v", err)
+	}
+	return &defaultReadCursorModel{conn: conn}
+}
+
+func MustReadCursorModel(url, db string) ReadCursorModel {
+	return NewReadCursorModel(url, db)
+}
+
+// Advance 把成员的已读游标推进到 readSeq，游标只会前进不会后退
+// 返回推进前的游标，首次标记已读时为 0；返回值不小于 readSeq 说明没有新的已读消息
+func (m *defaultReadCursorModel) Advance(ctx context.Context, conversationId, userId string,
+	readSeq int64) (int64, error) {
+	prev, err := m.advance(ctx, conversationId, userId, readSeq)
+	if mongo.IsDuplicateKeyError(err) {
+		// 并发的首次标记已读，另一方已经创建了游标，再次更新即可
+		prev, err = m.advance(ctx, conversationId, userId, readSeq)
+	}
+	return prev, err
+}
+
+func (m *defaultReadCursorModel) advance(ctx context.Context, conversationId, userId string,
+	readSeq int64) (int64, error) {
+	var prev ReadCursor
+
+	now := time.Now()
+	err := m.conn.FindOneAndUpdate(ctx, &prev, bson.M{
+		"conversationId": conversationId,
+		"userId":         userId,
+	}, []bson.M{{
+		"$set": bson.M{
+			"readSeq":  bson.M{"$max": bson.A{"$readSeq", readSeq}},
+			"updateAt": now,
+			"createAt": bson.M{"$ifNull": bson.A{"$createAt", now}},
+		},
+	}}, options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before))
+	switch err {
+	case nil:
+		return prev.ReadSeq, nil
+	case mon.ErrNotFound:
+		// 新建的游标
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
+
+// ListByConversationId 查询会话中全部成员的已读游标
+func (m *defaultReadCursorModel) ListByConversationId(ctx context.Context, conversationId string) ([]*ReadCursor, error) {
+	var data []*ReadCursor
+
+	err := m.conn.Find(ctx, &data, bson.M{"conversationId": conversationId})
+	switch err {
+	case nil:
+		return data, nil
+	case mon.ErrNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// ListByUserId 查询用户在所有会话中的已读游标
+func (m *defaultReadCursorModel) ListByUserId(ctx context.Context, userId string) ([]*ReadCursor, error) {
+	var data []*ReadCursor
+
+	err := m.conn.Find(ctx, &data, bson.M{"userId": userId})
+	switch err {
+	case nil:
+		return data, nil
+	case mon.ErrNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// ReadCursors 把已读游标转换为 userId -> readSeq
+func ReadCursors(cursors []*ReadCursor) map[string]int64 {
+	res := make(map[string]int64, len(cursors))
+	for _, cursor := range cursors {
+		res[cursor.UserId] = cursor.ReadSeq
+	}
+	return res
+}
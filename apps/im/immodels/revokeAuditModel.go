@@ -0,0 +1,105 @@
+package immodels
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/mon"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+// RevokeAudit 撤回审计记录，撤回他人的消息时记录，保存被撤回消息的副本
+type RevokeAudit struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	ConversationId string             `bson:"conversationId"`
+	ChatType       constants.ChatType `bson:"chatType"`
+	MsgId          string             `bson:"msgId"`
+
+	// OperatorId 执行撤回的用户，OperatorRole 为其在群中的角色
+	OperatorId   string                   `bson:"operatorId"`
+	OperatorRole constants.GroupRoleLevel `bson:"operatorRole"`
+
+	// 被撤回消息的发送者与内容
+	SendId     string          `bson:"sendId"`
+	MsgType    constants.MType `bson:"msgType"`
+	MsgContent string          `bson:"msgContent"`
+	SendTime   int64           `bson:"sendTime"`
+
+	// RevokeTime 撤回时间戳（毫秒）
+	RevokeTime int64 `bson:"revokeTime"`
+
+	CreateAt time.Time `bson:"createAt,omitempty"`
+}
+
+var _ RevokeAuditModel = (*defaultRevokeAuditModel)(nil)
+
+type RevokeAuditModel interface {
+	InsertIfNotExists(ctx context.Context, data *RevokeAudit) error
+	ListByConversationId(ctx context.Context, conversationId string, endRevokeTime, limit int64) ([]*RevokeAudit, error)
+}
+
+type defaultRevokeAuditModel struct {
+	conn *mon.Model
+}
+
+func NewRevokeAuditModel(url, db string) RevokeAuditModel {
+	conn := mon.MustNewModel(url, db, "revoke_audit")
+	// 同一操作者撤回同一条消息只记录一次
+	_, err := conn.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "msgId", Value: 1}, {Key: "operatorId", Value: 1}},
+		Options: options.Index().SetName("msgId_operatorId").SetUnique(true),
+	})
+	if err != nil {
+		logx.Errorf("create revoke_audit unique index err %v", err)
+	}
+	return &defaultRevokeAuditModel{conn: conn}
+}
+
+func MustRevokeAuditModel(url, db string) RevokeAuditModel {
+	return NewRevokeAuditModel(url, db)
+}
+
+// InsertIfNotExists 记录撤回审计，按 (msgId, operatorId) 幂等，消费重试时不会重复记录
+func (m *defaultRevokeAuditModel) InsertIfNotExists(ctx context.Context, data *RevokeAudit) error {
+	data.ID = primitive.NewObjectID()
+	data.CreateAt = time.Now()
+	_, err := m.conn.UpdateOne(ctx, bson.M{
+		"msgId":      data.MsgId,
+		"operatorId": data.OperatorId,
+	}, bson.M{
+		"$setOnInsert": data,
+	}, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// ListByConversationId 查询会话的撤回审计记录，按撤回时间倒序；endRevokeTime 大于 0 时只查询更早的记录
+func (m *defaultRevokeAuditModel) ListByConversationId(ctx context.Context, conversationId string, endRevokeTime,
+	limit int64) ([]*RevokeAudit, error) {
+	var data []*RevokeAudit
+
+	filter := bson.M{"conversationId": conversationId}
+	if endRevokeTime > 0 {
+		filter["revokeTime"] = bson.M{"$lt": endRevokeTime}
+	}
+	if limit <= 0 {
+		limit = DefaultChatLogLimit
+	}
+
+	err := m.conn.Find(ctx, &data, filter, options.Find().SetSort(bson.M{"revokeTime": -1}).SetLimit(limit))
+	switch err {
+	case nil:
+		return data, nil
+	case mon.ErrNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
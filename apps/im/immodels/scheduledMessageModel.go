@@ -0,0 +1,183 @@
+package immodels
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/mon"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+// 定时消息的状态
+const (
+	ScheduledPending  = iota // 等待发送
+	ScheduledSending         // 已被某个 task 实例领取，发送中
+	ScheduledSent            // 已投递到消息队列
+	ScheduledCanceled        // 已取消
+	ScheduledFailed          // 多次重试后仍发送失败
+)
+
+// ScheduledMessage 定时发送的消息
+type ScheduledMessage struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	ConversationId string             `bson:"conversationId"`
+	ChatType       constants.ChatType `bson:"chatType"`
+	SendId         string             `bson:"sendId"`
+	RecvId         string             `bson:"recvId"`
+	MsgType        constants.MType    `bson:"msgType"`
+	MsgContent     string             `bson:"msgContent"`
+
+	// SendAt 计划发送时间戳（毫秒）
+	SendAt int64 `bson:"sendAt"`
+	Status int   `bson:"status"`
+
+	// ClaimBy 领取该消息的 task 实例，ClaimAt 为领取时间（毫秒），超过租期未完成可被重新领取
+	ClaimBy string `bson:"claimBy,omitempty"`
+	ClaimAt int64  `bson:"claimAt,omitempty"`
+
+	RetryCount int    `bson:"retryCount"`
+	LastErr    string `bson:"lastErr,omitempty"`
+
+	UpdateAt time.Time `bson:"updateAt,omitempty"`
+	CreateAt time.Time `bson:"createAt,omitempty"`
+}
+
+var _ ScheduledMessageModel = (*defaultScheduledMessageModel)(nil)
+
+type ScheduledMessageModel interface {
+	Insert(ctx context.Context, data *ScheduledMessage) error
+	ListBySendId(ctx context.Context, sendId string, status []int) ([]*ScheduledMessage, error)
+	Cancel(ctx context.Context, id, sendId string) error
+	Claim(ctx context.Context, owner string, now, lease int64) (*ScheduledMessage, error)
+	MarkSent(ctx context.Context, id primitive.ObjectID, owner string) error
+	Release(ctx context.Context, id primitive.ObjectID, owner string, failed bool, lastErr string) error
+}
+
+type defaultScheduledMessageModel struct {
+	conn *mon.Model
+}
+
+func NewScheduledMessageModel(url, db string) ScheduledMessageModel {
+	conn := mon.MustNewModel(url, db, "scheduled_message")
+	return &defaultScheduledMessageModel{conn: conn}
+}
+
+func MustScheduledMessageModel(url, db string) ScheduledMessageModel {
+	return NewScheduledMessageModel(url, db)
+}
+
+func (m *defaultScheduledMessageModel) Insert(ctx context.Context, data *ScheduledMessage) error {
+	data.ID = primitive.NewObjectID()
+	data.Status = ScheduledPending
+	data.CreateAt = time.Now()
+	data.UpdateAt = time.Now()
+	_, err := m.conn.InsertOne(ctx, data)
+	return err
+}
+
+// ListBySendId 查询用户的定时消息，按计划发送时间正序；status 为空时查询全部状态
+func (m *defaultScheduledMessageModel) ListBySendId(ctx context.Context, sendId string, status []int) ([]*ScheduledMessage, error) {
+	var data []*ScheduledMessage
+
+	filter := bson.M{"sendId": sendId}
+	if len(status) > 0 {
+		filter["status"] = bson.M{"$in": status}
+	}
+
+	err := m.conn.Find(ctx, &data, filter, options.Find().SetSort(bson.M{"sendAt": 1}))
+	switch err {
+	case nil:
+		return data, nil
+	case mon.ErrNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// Cancel 取消定时消息，只能取消自己的、尚未开始发送的消息
+func (m *defaultScheduledMessageModel) Cancel(ctx context.Context, id, sendId string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidObjectId
+	}
+
+	res, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id":    oid,
+		"sendId": sendId,
+		"status": ScheduledPending,
+	}, bson.M{
+		"$set": bson.M{"status": ScheduledCanceled, "updateAt": time.Now()},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Claim 领取一条到期的定时消息，同一条消息同一时间只会被一个实例领取
+// 已领取但超过租期 lease（毫秒）仍未完成的消息视为领取者异常，可被重新领取
+func (m *defaultScheduledMessageModel) Claim(ctx context.Context, owner string, now, lease int64) (*ScheduledMessage, error) {
+	var data ScheduledMessage
+
+	err := m.conn.FindOneAndUpdate(ctx, &data, bson.M{
+		"sendAt": bson.M{"$lte": now},
+		"$or": []bson.M{
+			{"status": ScheduledPending},
+			{"status": ScheduledSending, "claimAt": bson.M{"$lt": now - lease}},
+		},
+	}, bson.M{
+		"$set": bson.M{
+			"status":   ScheduledSending,
+			"claimBy":  owner,
+			"claimAt":  now,
+			"updateAt": time.Now(),
+		},
+	}, options.FindOneAndUpdate().SetSort(bson.M{"sendAt": 1}).SetReturnDocument(options.After))
+	switch err {
+	case nil:
+		return &data, nil
+	case mon.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// MarkSent 标记为已发送，只有当前领取者可以标记
+func (m *defaultScheduledMessageModel) MarkSent(ctx context.Context, id primitive.ObjectID, owner string) error {
+	_, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id":     id,
+		"claimBy": owner,
+		"status":  ScheduledSending,
+	}, bson.M{
+		"$set": bson.M{"status": ScheduledSent, "updateAt": time.Now()},
+	})
+	return err
+}
+
+// Release 发送失败后释放领取，failed 为 true 时不再重试
+func (m *defaultScheduledMessageModel) Release(ctx context.Context, id primitive.ObjectID, owner string, failed bool,
+	lastErr string) error {
+	status := ScheduledPending
+	if failed {
+		status = ScheduledFailed
+	}
+
+	_, err := m.conn.UpdateOne(ctx, bson.M{
+		"_id":     id,
+		"claimBy": owner,
+		"status":  ScheduledSending,
+	}, bson.M{
+		"$set":   bson.M{"status": status, "lastErr": lastErr, "updateAt": time.Now()},
+		"$unset": bson.M{"claimBy": "", "claimAt": ""},
+		"$inc":   bson.M{"retryCount": 1},
+	})
+	return err
+}
@@ -66,6 +66,10 @@ func main() {
 
 func Run(c config.Config) {
 	ctx := svc.NewServiceContext(c)
+	if ctx.SearchSyncer != nil {
+		go ctx.SearchSyncer.Start()
+		defer ctx.SearchSyncer.Stop()
+	}
 
 	s := zrpc.MustNewServer(c.RpcServerConf, func(srv *grpc.Server) {
 		grpcServer = srv
@@ -22,18 +22,29 @@ const (
 )
 
 type ChatLog struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id"`
-	ConversationId string                 `protobuf:"bytes,2,opt,name=conversationId,proto3" json:"conversationId"`
-	SendId         string                 `protobuf:"bytes,3,opt,name=sendId,proto3" json:"sendId"`
-	RecvId         string                 `protobuf:"bytes,4,opt,name=recvId,proto3" json:"recvId"`
-	MsgType        int32                  `protobuf:"varint,5,opt,name=msgType,proto3" json:"msgType"`
-	MsgContent     string                 `protobuf:"bytes,6,opt,name=msgContent,proto3" json:"msgContent"`
-	ChatType       int32                  `protobuf:"varint,7,opt,name=chatType,proto3" json:"chatType"`
-	SendTime       int64                  `protobuf:"varint,8,opt,name=SendTime,proto3" json:"SendTime"`
-	ReadRecords    []byte                 `protobuf:"bytes,9,opt,name=readRecords,proto3" json:"readRecords"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id"`
+	ConversationId   string                 `protobuf:"bytes,2,opt,name=conversationId,proto3" json:"conversationId"`
+	SendId           string                 `protobuf:"bytes,3,opt,name=sendId,proto3" json:"sendId"`
+	RecvId           string                 `protobuf:"bytes,4,opt,name=recvId,proto3" json:"recvId"`
+	MsgType          int32                  `protobuf:"varint,5,opt,name=msgType,proto3" json:"msgType"`
+	MsgContent       string                 `protobuf:"bytes,6,opt,name=msgContent,proto3" json:"msgContent"`
+	ChatType         int32                  `protobuf:"varint,7,opt,name=chatType,proto3" json:"chatType"`
+	SendTime         int64                  `protobuf:"varint,8,opt,name=SendTime,proto3" json:"SendTime"`
+	ReadRecords      []byte                 `protobuf:"bytes,9,opt,name=readRecords,proto3" json:"readRecords"`
+	IsEdited         bool                   `protobuf:"varint,10,opt,name=isEdited,proto3" json:"isEdited"`
+	EditTime         int64                  `protobuf:"varint,11,opt,name=editTime,proto3" json:"editTime"`
+	Quote            *ChatLogQuote          `protobuf:"bytes,12,opt,name=quote,proto3" json:"quote"`
+	Reactions        []*ChatLogReaction     `protobuf:"bytes,13,rep,name=reactions,proto3" json:"reactions"`
+	MergeForward     *ChatLogMergeForward   `protobuf:"bytes,14,opt,name=mergeForward,proto3" json:"mergeForward"`
+	ExpireAt         int64                  `protobuf:"varint,15,opt,name=expireAt,proto3" json:"expireAt"`                // 阅后即焚的过期时间（毫秒），0 表示未开始计时或不会过期
+	DisappearTtl     int64                  `protobuf:"varint,16,opt,name=disappearTtl,proto3" json:"disappearTtl"`        // 阅后即焚的存活时长（秒）
+	DeliveredRecords []byte                 `protobuf:"bytes,17,opt,name=deliveredRecords,proto3" json:"deliveredRecords"` // 送达记录，格式与 readRecords 相同
+	Seq              int64                  `protobuf:"varint,18,opt,name=seq,proto3" json:"seq"`                          // 消息在会话中的序号，早期消息为 0
+	LinkPreview      *ChatLogLinkPreview    `protobuf:"bytes,19,opt,name=linkPreview,proto3" json:"linkPreview"`           // 消息中第一个链接的预览，异步抓取，可能为空
+	MsgFrom          int32                  `protobuf:"varint,20,opt,name=msgFrom,proto3" json:"msgFrom"`                  // 消息来源，1 表示机器人
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *ChatLog) Reset() {
@@ -129,42 +140,108 @@ func (x *ChatLog) GetReadRecords() []byte {
 	return nil
 }
 
-type Conversation struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ConversationId string                 `protobuf:"bytes,1,opt,name=conversationId,proto3" json:"conversationId"`
-	ChatType       int32                  `protobuf:"varint,2,opt,name=chatType,proto3" json:"chatType"`
-	TargetId       string                 `protobuf:"bytes,3,opt,name=targetId,proto3" json:"targetId"`
-	IsShow         bool                   `protobuf:"varint,4,opt,name=isShow,proto3" json:"isShow"`
-	Seq            int64                  `protobuf:"varint,5,opt,name=seq,proto3" json:"seq"`
-	// 总消息数
-	Total int32 `protobuf:"varint,6,opt,name=total,proto3" json:"total"`
-	// 未读消息数
-	ToRead int32    `protobuf:"varint,7,opt,name=toRead,proto3" json:"toRead"`
-	Msg    *ChatLog `protobuf:"bytes,8,opt,name=msg,proto3" json:"msg"`
-	// 已读消息
-	Read int32 `protobuf:"varint,9,opt,name=Read,proto3" json:"Read"`
-	// 扩展字段 - 用于前端显示
-	Name          string `protobuf:"bytes,10,opt,name=name,proto3" json:"name"`                // 会话名称（好友昵称或群名称）
-	Avatar        string `protobuf:"bytes,11,opt,name=avatar,proto3" json:"avatar"`            // 会话头像（好友头像或群头像）
-	MemberCount   int32  `protobuf:"varint,12,opt,name=memberCount,proto3" json:"memberCount"` // 群成员数量（仅群聊时有效）
+func (x *ChatLog) GetIsEdited() bool {
+	if x != nil {
+		return x.IsEdited
+	}
+	return false
+}
+
+func (x *ChatLog) GetEditTime() int64 {
+	if x != nil {
+		return x.EditTime
+	}
+	return 0
+}
+
+func (x *ChatLog) GetQuote() *ChatLogQuote {
+	if x != nil {
+		return x.Quote
+	}
+	return nil
+}
+
+func (x *ChatLog) GetReactions() []*ChatLogReaction {
+	if x != nil {
+		return x.Reactions
+	}
+	return nil
+}
+
+func (x *ChatLog) GetMergeForward() *ChatLogMergeForward {
+	if x != nil {
+		return x.MergeForward
+	}
+	return nil
+}
+
+func (x *ChatLog) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+func (x *ChatLog) GetDisappearTtl() int64 {
+	if x != nil {
+		return x.DisappearTtl
+	}
+	return 0
+}
+
+func (x *ChatLog) GetDeliveredRecords() []byte {
+	if x != nil {
+		return x.DeliveredRecords
+	}
+	return nil
+}
+
+func (x *ChatLog) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ChatLog) GetLinkPreview() *ChatLogLinkPreview {
+	if x != nil {
+		return x.LinkPreview
+	}
+	return nil
+}
+
+func (x *ChatLog) GetMsgFrom() int32 {
+	if x != nil {
+		return x.MsgFrom
+	}
+	return 0
+}
+
+// 被引用消息的冗余信息
+type ChatLogQuote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MsgId         string                 `protobuf:"bytes,1,opt,name=msgId,proto3" json:"msgId"`
+	SendId        string                 `protobuf:"bytes,2,opt,name=sendId,proto3" json:"sendId"`
+	MsgType       int32                  `protobuf:"varint,3,opt,name=msgType,proto3" json:"msgType"`
+	Snippet       string                 `protobuf:"bytes,4,opt,name=snippet,proto3" json:"snippet"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Conversation) Reset() {
-	*x = Conversation{}
+func (x *ChatLogQuote) Reset() {
+	*x = ChatLogQuote{}
 	mi := &file_im_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Conversation) String() string {
+func (x *ChatLogQuote) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Conversation) ProtoMessage() {}
+func (*ChatLogQuote) ProtoMessage() {}
 
-func (x *Conversation) ProtoReflect() protoreflect.Message {
+func (x *ChatLogQuote) ProtoReflect() protoreflect.Message {
 	mi := &file_im_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -176,117 +253,984 @@ func (x *Conversation) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Conversation.ProtoReflect.Descriptor instead.
-func (*Conversation) Descriptor() ([]byte, []int) {
+// Deprecated: Use ChatLogQuote.ProtoReflect.Descriptor instead.
+func (*ChatLogQuote) Descriptor() ([]byte, []int) {
 	return file_im_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *Conversation) GetConversationId() string {
+func (x *ChatLogQuote) GetMsgId() string {
 	if x != nil {
-		return x.ConversationId
+		return x.MsgId
 	}
 	return ""
 }
 
-func (x *Conversation) GetChatType() int32 {
+func (x *ChatLogQuote) GetSendId() string {
 	if x != nil {
-		return x.ChatType
+		return x.SendId
+	}
+	return ""
+}
+
+func (x *ChatLogQuote) GetMsgType() int32 {
+	if x != nil {
+		return x.MsgType
 	}
 	return 0
 }
 
-func (x *Conversation) GetTargetId() string {
+func (x *ChatLogQuote) GetSnippet() string {
 	if x != nil {
-		return x.TargetId
+		return x.Snippet
 	}
 	return ""
 }
 
-func (x *Conversation) GetIsShow() bool {
+// 合并转发卡片，完整记录通过 GetMergeForward 展开
+type ChatLogMergeForward struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversationId,proto3" json:"conversationId"`
+	Title          string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title"`
+	Summary        []string               `protobuf:"bytes,3,rep,name=summary,proto3" json:"summary"`
+	Count          int32                  `protobuf:"varint,4,opt,name=count,proto3" json:"count"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ChatLogMergeForward) Reset() {
+	*x = ChatLogMergeForward{}
+	mi := &file_im_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatLogMergeForward) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatLogMergeForward) ProtoMessage() {}
+
+func (x *ChatLogMergeForward) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[2]
 	if x != nil {
-		return x.IsShow
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-func (x *Conversation) GetSeq() int64 {
+// Deprecated: Use ChatLogMergeForward.ProtoReflect.Descriptor instead.
+func (*ChatLogMergeForward) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ChatLogMergeForward) GetConversationId() string {
 	if x != nil {
-		return x.Seq
+		return x.ConversationId
 	}
-	return 0
+	return ""
 }
 
-func (x *Conversation) GetTotal() int32 {
+func (x *ChatLogMergeForward) GetTitle() string {
 	if x != nil {
-		return x.Total
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ChatLogMergeForward) GetSummary() []string {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *ChatLogMergeForward) GetCount() int32 {
+	if x != nil {
+		return x.Count
 	}
 	return 0
 }
 
-func (x *Conversation) GetToRead() int32 {
+// 链接预览
+type ChatLogLinkPreview struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description"`
+	Image         string                 `protobuf:"bytes,4,opt,name=image,proto3" json:"image"`
+	SiteName      string                 `protobuf:"bytes,5,opt,name=siteName,proto3" json:"siteName"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatLogLinkPreview) Reset() {
+	*x = ChatLogLinkPreview{}
+	mi := &file_im_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatLogLinkPreview) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatLogLinkPreview) ProtoMessage() {}
+
+func (x *ChatLogLinkPreview) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[3]
 	if x != nil {
-		return x.ToRead
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatLogLinkPreview.ProtoReflect.Descriptor instead.
+func (*ChatLogLinkPreview) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ChatLogLinkPreview) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ChatLogLinkPreview) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ChatLogLinkPreview) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ChatLogLinkPreview) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *ChatLogLinkPreview) GetSiteName() string {
+	if x != nil {
+		return x.SiteName
+	}
+	return ""
+}
+
+// 表情回应的聚合结果
+type ChatLogReaction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Emoji         string                 `protobuf:"bytes,1,opt,name=emoji,proto3" json:"emoji"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count"`
+	Reacted       bool                   `protobuf:"varint,3,opt,name=reacted,proto3" json:"reacted"` // 当前用户是否回应过
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatLogReaction) Reset() {
+	*x = ChatLogReaction{}
+	mi := &file_im_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatLogReaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatLogReaction) ProtoMessage() {}
+
+func (x *ChatLogReaction) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatLogReaction.ProtoReflect.Descriptor instead.
+func (*ChatLogReaction) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ChatLogReaction) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *ChatLogReaction) GetCount() int64 {
+	if x != nil {
+		return x.Count
 	}
 	return 0
 }
 
-func (x *Conversation) GetMsg() *ChatLog {
+func (x *ChatLogReaction) GetReacted() bool {
 	if x != nil {
-		return x.Msg
+		return x.Reacted
+	}
+	return false
+}
+
+type Conversation struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversationId,proto3" json:"conversationId"`
+	ChatType       int32                  `protobuf:"varint,2,opt,name=chatType,proto3" json:"chatType"`
+	TargetId       string                 `protobuf:"bytes,3,opt,name=targetId,proto3" json:"targetId"`
+	IsShow         bool                   `protobuf:"varint,4,opt,name=isShow,proto3" json:"isShow"`
+	Seq            int64                  `protobuf:"varint,5,opt,name=seq,proto3" json:"seq"`
+	// 总消息数
+	Total int32 `protobuf:"varint,6,opt,name=total,proto3" json:"total"`
+	// 未读消息数
+	ToRead int32    `protobuf:"varint,7,opt,name=toRead,proto3" json:"toRead"`
+	Msg    *ChatLog `protobuf:"bytes,8,opt,name=msg,proto3" json:"msg"`
+	// 已读消息
+	Read int32 `protobuf:"varint,9,opt,name=Read,proto3" json:"Read"`
+	// 扩展字段 - 用于前端显示
+	Name          string `protobuf:"bytes,10,opt,name=name,proto3" json:"name"`                    // 会话名称（好友昵称或群名称）
+	Avatar        string `protobuf:"bytes,11,opt,name=avatar,proto3" json:"avatar"`                // 会话头像（好友头像或群头像）
+	MemberCount   int32  `protobuf:"varint,12,opt,name=memberCount,proto3" json:"memberCount"`     // 群成员数量（仅群聊时有效）
+	MentionMsgId  string `protobuf:"bytes,13,opt,name=mentionMsgId,proto3" json:"mentionMsgId"`    // 最早一条未读的@我的消息，为空表示没有
+	DisappearMode int32  `protobuf:"varint,14,opt,name=disappearMode,proto3" json:"disappearMode"` // 阅后即焚的计时方式，0 表示关闭
+	DisappearTtl  int64  `protobuf:"varint,15,opt,name=disappearTtl,proto3" json:"disappearTtl"`   // 阅后即焚的存活时长（秒）
+	ReadSeq       int64  `protobuf:"varint,16,opt,name=readSeq,proto3" json:"readSeq"`             // 当前用户的已读游标，seq 为会话最新的消息序号
+	SlowMode      int64  `protobuf:"varint,17,opt,name=slowMode,proto3" json:"slowMode"`           // 慢速模式的发言间隔（秒），0 表示关闭
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Conversation) Reset() {
+	*x = Conversation{}
+	mi := &file_im_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Conversation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Conversation) ProtoMessage() {}
+
+func (x *Conversation) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Conversation.ProtoReflect.Descriptor instead.
+func (*Conversation) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Conversation) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *Conversation) GetChatType() int32 {
+	if x != nil {
+		return x.ChatType
+	}
+	return 0
+}
+
+func (x *Conversation) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *Conversation) GetIsShow() bool {
+	if x != nil {
+		return x.IsShow
+	}
+	return false
+}
+
+func (x *Conversation) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Conversation) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Conversation) GetToRead() int32 {
+	if x != nil {
+		return x.ToRead
+	}
+	return 0
+}
+
+func (x *Conversation) GetMsg() *ChatLog {
+	if x != nil {
+		return x.Msg
+	}
+	return nil
+}
+
+func (x *Conversation) GetRead() int32 {
+	if x != nil {
+		return x.Read
+	}
+	return 0
+}
+
+func (x *Conversation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Conversation) GetAvatar() string {
+	if x != nil {
+		return x.Avatar
+	}
+	return ""
+}
+
+func (x *Conversation) GetMemberCount() int32 {
+	if x != nil {
+		return x.MemberCount
+	}
+	return 0
+}
+
+func (x *Conversation) GetMentionMsgId() string {
+	if x != nil {
+		return x.MentionMsgId
+	}
+	return ""
+}
+
+func (x *Conversation) GetDisappearMode() int32 {
+	if x != nil {
+		return x.DisappearMode
+	}
+	return 0
+}
+
+func (x *Conversation) GetDisappearTtl() int64 {
+	if x != nil {
+		return x.DisappearTtl
+	}
+	return 0
+}
+
+func (x *Conversation) GetReadSeq() int64 {
+	if x != nil {
+		return x.ReadSeq
+	}
+	return 0
+}
+
+func (x *Conversation) GetSlowMode() int64 {
+	if x != nil {
+		return x.SlowMode
+	}
+	return 0
+}
+
+type GetConversationsReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConversationsReq) Reset() {
+	*x = GetConversationsReq{}
+	mi := &file_im_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConversationsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConversationsReq) ProtoMessage() {}
+
+func (x *GetConversationsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConversationsReq.ProtoReflect.Descriptor instead.
+func (*GetConversationsReq) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetConversationsReq) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetConversationsResp struct {
+	state            protoimpl.MessageState   `protogen:"open.v1"`
+	ConversationList map[string]*Conversation `protobuf:"bytes,2,rep,name=conversationList,proto3" json:"conversationList" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetConversationsResp) Reset() {
+	*x = GetConversationsResp{}
+	mi := &file_im_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConversationsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConversationsResp) ProtoMessage() {}
+
+func (x *GetConversationsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConversationsResp.ProtoReflect.Descriptor instead.
+func (*GetConversationsResp) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetConversationsResp) GetConversationList() map[string]*Conversation {
+	if x != nil {
+		return x.ConversationList
+	}
+	return nil
+}
+
+type PutConversationsReq struct {
+	state            protoimpl.MessageState   `protogen:"open.v1"`
+	Id               string                   `protobuf:"bytes,1,opt,name=id,proto3" json:"id"`
+	UserId           string                   `protobuf:"bytes,2,opt,name=userId,proto3" json:"userId"`
+	ConversationList map[string]*Conversation `protobuf:"bytes,3,rep,name=conversationList,proto3" json:"conversationList" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PutConversationsReq) Reset() {
+	*x = PutConversationsReq{}
+	mi := &file_im_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutConversationsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutConversationsReq) ProtoMessage() {}
+
+func (x *PutConversationsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutConversationsReq.ProtoReflect.Descriptor instead.
+func (*PutConversationsReq) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PutConversationsReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PutConversationsReq) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PutConversationsReq) GetConversationList() map[string]*Conversation {
+	if x != nil {
+		return x.ConversationList
+	}
+	return nil
+}
+
+type PutConversationsResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutConversationsResp) Reset() {
+	*x = PutConversationsResp{}
+	mi := &file_im_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutConversationsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutConversationsResp) ProtoMessage() {}
+
+func (x *PutConversationsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutConversationsResp.ProtoReflect.Descriptor instead.
+func (*PutConversationsResp) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{9}
+}
+
+type GetChatLogReq struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversationId,proto3" json:"conversationId"`
+	StartSendTime  int64                  `protobuf:"varint,2,opt,name=startSendTime,proto3" json:"startSendTime"`
+	EndSendTime    int64                  `protobuf:"varint,3,opt,name=endSendTime,proto3" json:"endSendTime"`
+	Count          int64                  `protobuf:"varint,4,opt,name=count,proto3" json:"count"`
+	MsgId          string                 `protobuf:"bytes,5,opt,name=msgId,proto3" json:"msgId"`
+	UserId         string                 `protobuf:"bytes,6,opt,name=userId,proto3" json:"userId"`
+	// 按游标翻页，见 constants.ChatLogDirection；为 0 时按 startSendTime/endSendTime 查询
+	Direction int32 `protobuf:"varint,7,opt,name=direction,proto3" json:"direction"`
+	// 上一页返回的 beforeCursor 或 afterCursor，为空时从最新（向前）或最早（向后）的消息开始
+	Cursor string `protobuf:"bytes,8,opt,name=cursor,proto3" json:"cursor"`
+	// 定位到某条消息，返回该消息及其前后各 count 条消息
+	AroundMsgId   string `protobuf:"bytes,9,opt,name=aroundMsgId,proto3" json:"aroundMsgId"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChatLogReq) Reset() {
+	*x = GetChatLogReq{}
+	mi := &file_im_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatLogReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatLogReq) ProtoMessage() {}
+
+func (x *GetChatLogReq) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatLogReq.ProtoReflect.Descriptor instead.
+func (*GetChatLogReq) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetChatLogReq) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *GetChatLogReq) GetStartSendTime() int64 {
+	if x != nil {
+		return x.StartSendTime
+	}
+	return 0
+}
+
+func (x *GetChatLogReq) GetEndSendTime() int64 {
+	if x != nil {
+		return x.EndSendTime
+	}
+	return 0
+}
+
+func (x *GetChatLogReq) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *GetChatLogReq) GetMsgId() string {
+	if x != nil {
+		return x.MsgId
+	}
+	return ""
+}
+
+func (x *GetChatLogReq) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetChatLogReq) GetDirection() int32 {
+	if x != nil {
+		return x.Direction
+	}
+	return 0
+}
+
+func (x *GetChatLogReq) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetChatLogReq) GetAroundMsgId() string {
+	if x != nil {
+		return x.AroundMsgId
+	}
+	return ""
+}
+
+type GetChatLogResp struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 按从新到旧排序
+	List []*ChatLog `protobuf:"bytes,1,rep,name=List,proto3" json:"List"`
+	// 按游标翻页与定位消息时返回
+	HasMoreBefore bool   `protobuf:"varint,2,opt,name=hasMoreBefore,proto3" json:"hasMoreBefore"`
+	HasMoreAfter  bool   `protobuf:"varint,3,opt,name=hasMoreAfter,proto3" json:"hasMoreAfter"`
+	BeforeCursor  string `protobuf:"bytes,4,opt,name=beforeCursor,proto3" json:"beforeCursor"`
+	AfterCursor   string `protobuf:"bytes,5,opt,name=afterCursor,proto3" json:"afterCursor"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChatLogResp) Reset() {
+	*x = GetChatLogResp{}
+	mi := &file_im_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatLogResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatLogResp) ProtoMessage() {}
+
+func (x *GetChatLogResp) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatLogResp.ProtoReflect.Descriptor instead.
+func (*GetChatLogResp) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetChatLogResp) GetList() []*ChatLog {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+func (x *GetChatLogResp) GetHasMoreBefore() bool {
+	if x != nil {
+		return x.HasMoreBefore
+	}
+	return false
+}
+
+func (x *GetChatLogResp) GetHasMoreAfter() bool {
+	if x != nil {
+		return x.HasMoreAfter
+	}
+	return false
+}
+
+func (x *GetChatLogResp) GetBeforeCursor() string {
+	if x != nil {
+		return x.BeforeCursor
+	}
+	return ""
+}
+
+func (x *GetChatLogResp) GetAfterCursor() string {
+	if x != nil {
+		return x.AfterCursor
+	}
+	return ""
+}
+
+type GetRepliesReq struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversationId,proto3" json:"conversationId"`
+	MsgId          string                 `protobuf:"bytes,2,opt,name=msgId,proto3" json:"msgId"`
+	Count          int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetRepliesReq) Reset() {
+	*x = GetRepliesReq{}
+	mi := &file_im_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRepliesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRepliesReq) ProtoMessage() {}
+
+func (x *GetRepliesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRepliesReq.ProtoReflect.Descriptor instead.
+func (*GetRepliesReq) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetRepliesReq) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *GetRepliesReq) GetMsgId() string {
+	if x != nil {
+		return x.MsgId
+	}
+	return ""
+}
+
+func (x *GetRepliesReq) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GetRepliesResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	List          []*ChatLog             `protobuf:"bytes,1,rep,name=List,proto3" json:"List"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRepliesResp) Reset() {
+	*x = GetRepliesResp{}
+	mi := &file_im_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRepliesResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRepliesResp) ProtoMessage() {}
+
+func (x *GetRepliesResp) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRepliesResp.ProtoReflect.Descriptor instead.
+func (*GetRepliesResp) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetRepliesResp) GetList() []*ChatLog {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+type GetMergeForwardReq struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId"`
+	MsgId  string                 `protobuf:"bytes,2,opt,name=msgId,proto3" json:"msgId"`
+	// 展开嵌套的合并转发，依次为各层卡片在上一层中的原消息ID
+	Path          []string `protobuf:"bytes,3,rep,name=path,proto3" json:"path"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMergeForwardReq) Reset() {
+	*x = GetMergeForwardReq{}
+	mi := &file_im_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMergeForwardReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMergeForwardReq) ProtoMessage() {}
+
+func (x *GetMergeForwardReq) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *Conversation) GetRead() int32 {
-	if x != nil {
-		return x.Read
-	}
-	return 0
+// Deprecated: Use GetMergeForwardReq.ProtoReflect.Descriptor instead.
+func (*GetMergeForwardReq) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *Conversation) GetName() string {
+func (x *GetMergeForwardReq) GetUserId() string {
 	if x != nil {
-		return x.Name
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *Conversation) GetAvatar() string {
+func (x *GetMergeForwardReq) GetMsgId() string {
 	if x != nil {
-		return x.Avatar
+		return x.MsgId
 	}
 	return ""
 }
 
-func (x *Conversation) GetMemberCount() int32 {
+func (x *GetMergeForwardReq) GetPath() []string {
 	if x != nil {
-		return x.MemberCount
+		return x.Path
 	}
-	return 0
+	return nil
 }
 
-type GetConversationsReq struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type GetMergeForwardResp struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversationId,proto3" json:"conversationId"`
+	Title          string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title"`
+	List           []*ChatLog             `protobuf:"bytes,3,rep,name=List,proto3" json:"List"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *GetConversationsReq) Reset() {
-	*x = GetConversationsReq{}
-	mi := &file_im_proto_msgTypes[2]
+func (x *GetMergeForwardResp) Reset() {
+	*x = GetMergeForwardResp{}
+	mi := &file_im_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetConversationsReq) String() string {
+func (x *GetMergeForwardResp) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetConversationsReq) ProtoMessage() {}
+func (*GetMergeForwardResp) ProtoMessage() {}
 
-func (x *GetConversationsReq) ProtoReflect() protoreflect.Message {
-	mi := &file_im_proto_msgTypes[2]
+func (x *GetMergeForwardResp) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -297,40 +1241,56 @@ func (x *GetConversationsReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetConversationsReq.ProtoReflect.Descriptor instead.
-func (*GetConversationsReq) Descriptor() ([]byte, []int) {
-	return file_im_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use GetMergeForwardResp.ProtoReflect.Descriptor instead.
+func (*GetMergeForwardResp) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *GetConversationsReq) GetUserId() string {
+func (x *GetMergeForwardResp) GetConversationId() string {
 	if x != nil {
-		return x.UserId
+		return x.ConversationId
 	}
 	return ""
 }
 
-type GetConversationsResp struct {
-	state            protoimpl.MessageState   `protogen:"open.v1"`
-	ConversationList map[string]*Conversation `protobuf:"bytes,2,rep,name=conversationList,proto3" json:"conversationList" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *GetMergeForwardResp) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
 }
 
-func (x *GetConversationsResp) Reset() {
-	*x = GetConversationsResp{}
-	mi := &file_im_proto_msgTypes[3]
+func (x *GetMergeForwardResp) GetList() []*ChatLog {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+type PinnedMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Msg           *ChatLog               `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg"`
+	PinBy         string                 `protobuf:"bytes,2,opt,name=pinBy,proto3" json:"pinBy"`
+	PinTime       int64                  `protobuf:"varint,3,opt,name=pinTime,proto3" json:"pinTime"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinnedMessage) Reset() {
+	*x = PinnedMessage{}
+	mi := &file_im_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetConversationsResp) String() string {
+func (x *PinnedMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetConversationsResp) ProtoMessage() {}
+func (*PinnedMessage) ProtoMessage() {}
 
-func (x *GetConversationsResp) ProtoReflect() protoreflect.Message {
-	mi := &file_im_proto_msgTypes[3]
+func (x *PinnedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -341,42 +1301,55 @@ func (x *GetConversationsResp) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetConversationsResp.ProtoReflect.Descriptor instead.
-func (*GetConversationsResp) Descriptor() ([]byte, []int) {
-	return file_im_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use PinnedMessage.ProtoReflect.Descriptor instead.
+func (*PinnedMessage) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *GetConversationsResp) GetConversationList() map[string]*Conversation {
+func (x *PinnedMessage) GetMsg() *ChatLog {
 	if x != nil {
-		return x.ConversationList
+		return x.Msg
 	}
 	return nil
 }
 
-type PutConversationsReq struct {
-	state            protoimpl.MessageState   `protogen:"open.v1"`
-	Id               string                   `protobuf:"bytes,1,opt,name=id,proto3" json:"id"`
-	UserId           string                   `protobuf:"bytes,2,opt,name=userId,proto3" json:"userId"`
-	ConversationList map[string]*Conversation `protobuf:"bytes,3,rep,name=conversationList,proto3" json:"conversationList" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *PinnedMessage) GetPinBy() string {
+	if x != nil {
+		return x.PinBy
+	}
+	return ""
 }
 
-func (x *PutConversationsReq) Reset() {
-	*x = PutConversationsReq{}
-	mi := &file_im_proto_msgTypes[4]
+func (x *PinnedMessage) GetPinTime() int64 {
+	if x != nil {
+		return x.PinTime
+	}
+	return 0
+}
+
+type GetPinnedMessagesReq struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId"`
+	ConversationId string                 `protobuf:"bytes,2,opt,name=conversationId,proto3" json:"conversationId"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetPinnedMessagesReq) Reset() {
+	*x = GetPinnedMessagesReq{}
+	mi := &file_im_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PutConversationsReq) String() string {
+func (x *GetPinnedMessagesReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PutConversationsReq) ProtoMessage() {}
+func (*GetPinnedMessagesReq) ProtoMessage() {}
 
-func (x *PutConversationsReq) ProtoReflect() protoreflect.Message {
-	mi := &file_im_proto_msgTypes[4]
+func (x *GetPinnedMessagesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -387,53 +1360,47 @@ func (x *PutConversationsReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PutConversationsReq.ProtoReflect.Descriptor instead.
-func (*PutConversationsReq) Descriptor() ([]byte, []int) {
-	return file_im_proto_rawDescGZIP(), []int{4}
-}
-
-func (x *PutConversationsReq) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
+// Deprecated: Use GetPinnedMessagesReq.ProtoReflect.Descriptor instead.
+func (*GetPinnedMessagesReq) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *PutConversationsReq) GetUserId() string {
+func (x *GetPinnedMessagesReq) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *PutConversationsReq) GetConversationList() map[string]*Conversation {
+func (x *GetPinnedMessagesReq) GetConversationId() string {
 	if x != nil {
-		return x.ConversationList
+		return x.ConversationId
 	}
-	return nil
+	return ""
 }
 
-type PutConversationsResp struct {
+type GetPinnedMessagesResp struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	List          []*PinnedMessage       `protobuf:"bytes,1,rep,name=List,proto3" json:"List"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PutConversationsResp) Reset() {
-	*x = PutConversationsResp{}
-	mi := &file_im_proto_msgTypes[5]
+func (x *GetPinnedMessagesResp) Reset() {
+	*x = GetPinnedMessagesResp{}
+	mi := &file_im_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PutConversationsResp) String() string {
+func (x *GetPinnedMessagesResp) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PutConversationsResp) ProtoMessage() {}
+func (*GetPinnedMessagesResp) ProtoMessage() {}
 
-func (x *PutConversationsResp) ProtoReflect() protoreflect.Message {
-	mi := &file_im_proto_msgTypes[5]
+func (x *GetPinnedMessagesResp) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -444,37 +1411,52 @@ func (x *PutConversationsResp) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PutConversationsResp.ProtoReflect.Descriptor instead.
-func (*PutConversationsResp) Descriptor() ([]byte, []int) {
-	return file_im_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use GetPinnedMessagesResp.ProtoReflect.Descriptor instead.
+func (*GetPinnedMessagesResp) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{18}
 }
 
-type GetChatLogReq struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ConversationId string                 `protobuf:"bytes,1,opt,name=conversationId,proto3" json:"conversationId"`
-	StartSendTime  int64                  `protobuf:"varint,2,opt,name=startSendTime,proto3" json:"startSendTime"`
-	EndSendTime    int64                  `protobuf:"varint,3,opt,name=endSendTime,proto3" json:"endSendTime"`
-	Count          int64                  `protobuf:"varint,4,opt,name=count,proto3" json:"count"`
-	MsgId          string                 `protobuf:"bytes,5,opt,name=msgId,proto3" json:"msgId"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *GetPinnedMessagesResp) GetList() []*PinnedMessage {
+	if x != nil {
+		return x.List
+	}
+	return nil
 }
 
-func (x *GetChatLogReq) Reset() {
-	*x = GetChatLogReq{}
-	mi := &file_im_proto_msgTypes[6]
+type SearchChatLogReq struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserId  string                 `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId"`
+	Keyword string                 `protobuf:"bytes,2,opt,name=keyword,proto3" json:"keyword"`
+	// 用户有权搜索的会话，由调用方根据会话成员关系计算
+	ConversationIds []string `protobuf:"bytes,3,rep,name=conversationIds,proto3" json:"conversationIds"`
+	// 以下为可选的过滤条件
+	ConversationId string `protobuf:"bytes,4,opt,name=conversationId,proto3" json:"conversationId"`
+	SendId         string `protobuf:"bytes,5,opt,name=sendId,proto3" json:"sendId"`
+	// 小于 0 表示不限消息类型
+	MType         int32  `protobuf:"varint,6,opt,name=mType,proto3" json:"mType"`
+	StartTime     int64  `protobuf:"varint,7,opt,name=startTime,proto3" json:"startTime"`
+	EndTime       int64  `protobuf:"varint,8,opt,name=endTime,proto3" json:"endTime"`
+	Cursor        string `protobuf:"bytes,9,opt,name=cursor,proto3" json:"cursor"`
+	Count         int64  `protobuf:"varint,10,opt,name=count,proto3" json:"count"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchChatLogReq) Reset() {
+	*x = SearchChatLogReq{}
+	mi := &file_im_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetChatLogReq) String() string {
+func (x *SearchChatLogReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetChatLogReq) ProtoMessage() {}
+func (*SearchChatLogReq) ProtoMessage() {}
 
-func (x *GetChatLogReq) ProtoReflect() protoreflect.Message {
-	mi := &file_im_proto_msgTypes[6]
+func (x *SearchChatLogReq) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -485,68 +1467,158 @@ func (x *GetChatLogReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetChatLogReq.ProtoReflect.Descriptor instead.
-func (*GetChatLogReq) Descriptor() ([]byte, []int) {
-	return file_im_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use SearchChatLogReq.ProtoReflect.Descriptor instead.
+func (*SearchChatLogReq) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *GetChatLogReq) GetConversationId() string {
+func (x *SearchChatLogReq) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SearchChatLogReq) GetKeyword() string {
+	if x != nil {
+		return x.Keyword
+	}
+	return ""
+}
+
+func (x *SearchChatLogReq) GetConversationIds() []string {
+	if x != nil {
+		return x.ConversationIds
+	}
+	return nil
+}
+
+func (x *SearchChatLogReq) GetConversationId() string {
 	if x != nil {
 		return x.ConversationId
 	}
 	return ""
 }
 
-func (x *GetChatLogReq) GetStartSendTime() int64 {
+func (x *SearchChatLogReq) GetSendId() string {
 	if x != nil {
-		return x.StartSendTime
+		return x.SendId
+	}
+	return ""
+}
+
+func (x *SearchChatLogReq) GetMType() int32 {
+	if x != nil {
+		return x.MType
 	}
 	return 0
 }
 
-func (x *GetChatLogReq) GetEndSendTime() int64 {
+func (x *SearchChatLogReq) GetStartTime() int64 {
 	if x != nil {
-		return x.EndSendTime
+		return x.StartTime
 	}
 	return 0
 }
 
-func (x *GetChatLogReq) GetCount() int64 {
+func (x *SearchChatLogReq) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *SearchChatLogReq) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *SearchChatLogReq) GetCount() int64 {
 	if x != nil {
 		return x.Count
 	}
 	return 0
 }
 
-func (x *GetChatLogReq) GetMsgId() string {
+type SearchHit struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Msg   *ChatLog               `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg"`
+	// 命中词语高亮后的内容摘要
+	Snippet       string `protobuf:"bytes,2,opt,name=snippet,proto3" json:"snippet"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchHit) Reset() {
+	*x = SearchHit{}
+	mi := &file_im_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchHit) ProtoMessage() {}
+
+func (x *SearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[20]
 	if x != nil {
-		return x.MsgId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchHit.ProtoReflect.Descriptor instead.
+func (*SearchHit) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SearchHit) GetMsg() *ChatLog {
+	if x != nil {
+		return x.Msg
+	}
+	return nil
+}
+
+func (x *SearchHit) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
 	}
 	return ""
 }
 
-type GetChatLogResp struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	List          []*ChatLog             `protobuf:"bytes,1,rep,name=List,proto3" json:"List"`
+type SearchChatLogResp struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	List  []*SearchHit           `protobuf:"bytes,1,rep,name=List,proto3" json:"List"`
+	// 下一页的游标，为空表示没有更多结果
+	NextCursor    string `protobuf:"bytes,2,opt,name=nextCursor,proto3" json:"nextCursor"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetChatLogResp) Reset() {
-	*x = GetChatLogResp{}
-	mi := &file_im_proto_msgTypes[7]
+func (x *SearchChatLogResp) Reset() {
+	*x = SearchChatLogResp{}
+	mi := &file_im_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetChatLogResp) String() string {
+func (x *SearchChatLogResp) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetChatLogResp) ProtoMessage() {}
+func (*SearchChatLogResp) ProtoMessage() {}
 
-func (x *GetChatLogResp) ProtoReflect() protoreflect.Message {
-	mi := &file_im_proto_msgTypes[7]
+func (x *SearchChatLogResp) ProtoReflect() protoreflect.Message {
+	mi := &file_im_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -557,18 +1629,25 @@ func (x *GetChatLogResp) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetChatLogResp.ProtoReflect.Descriptor instead.
-func (*GetChatLogResp) Descriptor() ([]byte, []int) {
-	return file_im_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use SearchChatLogResp.ProtoReflect.Descriptor instead.
+func (*SearchChatLogResp) Descriptor() ([]byte, []int) {
+	return file_im_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *GetChatLogResp) GetList() []*ChatLog {
+func (x *SearchChatLogResp) GetList() []*SearchHit {
 	if x != nil {
 		return x.List
 	}
 	return nil
 }
 
+func (x *SearchChatLogResp) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 type SetUpUserConversationReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SendId        string                 `protobuf:"bytes,1,opt,name=SendId,proto3" json:"SendId"`
@@ -580,7 +1659,7 @@ type SetUpUserConversationReq struct {
 
 func (x *SetUpUserConversationReq) Reset() {
 	*x = SetUpUserConversationReq{}
-	mi := &file_im_proto_msgTypes[8]
+	mi := &file_im_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -592,7 +1671,7 @@ func (x *SetUpUserConversationReq) String() string {
 func (*SetUpUserConversationReq) ProtoMessage() {}
 
 func (x *SetUpUserConversationReq) ProtoReflect() protoreflect.Message {
-	mi := &file_im_proto_msgTypes[8]
+	mi := &file_im_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -605,7 +1684,7 @@ func (x *SetUpUserConversationReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetUpUserConversationReq.ProtoReflect.Descriptor instead.
 func (*SetUpUserConversationReq) Descriptor() ([]byte, []int) {
-	return file_im_proto_rawDescGZIP(), []int{8}
+	return file_im_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *SetUpUserConversationReq) GetSendId() string {
@@ -637,7 +1716,7 @@ type SetUpUserConversationResp struct {
 
 func (x *SetUpUserConversationResp) Reset() {
 	*x = SetUpUserConversationResp{}
-	mi := &file_im_proto_msgTypes[9]
+	mi := &file_im_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -649,7 +1728,7 @@ func (x *SetUpUserConversationResp) String() string {
 func (*SetUpUserConversationResp) ProtoMessage() {}
 
 func (x *SetUpUserConversationResp) ProtoReflect() protoreflect.Message {
-	mi := &file_im_proto_msgTypes[9]
+	mi := &file_im_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -662,7 +1741,7 @@ func (x *SetUpUserConversationResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetUpUserConversationResp.ProtoReflect.Descriptor instead.
 func (*SetUpUserConversationResp) Descriptor() ([]byte, []int) {
-	return file_im_proto_rawDescGZIP(), []int{9}
+	return file_im_proto_rawDescGZIP(), []int{23}
 }
 
 type CreateGroupConversationReq struct {
@@ -675,7 +1754,7 @@ type CreateGroupConversationReq struct {
 
 func (x *CreateGroupConversationReq) Reset() {
 	*x = CreateGroupConversationReq{}
-	mi := &file_im_proto_msgTypes[10]
+	mi := &file_im_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -687,7 +1766,7 @@ func (x *CreateGroupConversationReq) String() string {
 func (*CreateGroupConversationReq) ProtoMessage() {}
 
 func (x *CreateGroupConversationReq) ProtoReflect() protoreflect.Message {
-	mi := &file_im_proto_msgTypes[10]
+	mi := &file_im_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -700,7 +1779,7 @@ func (x *CreateGroupConversationReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateGroupConversationReq.ProtoReflect.Descriptor instead.
 func (*CreateGroupConversationReq) Descriptor() ([]byte, []int) {
-	return file_im_proto_rawDescGZIP(), []int{10}
+	return file_im_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *CreateGroupConversationReq) GetGroupId() string {
@@ -725,7 +1804,7 @@ type CreateGroupConversationResp struct {
 
 func (x *CreateGroupConversationResp) Reset() {
 	*x = CreateGroupConversationResp{}
-	mi := &file_im_proto_msgTypes[11]
+	mi := &file_im_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -737,7 +1816,7 @@ func (x *CreateGroupConversationResp) String() string {
 func (*CreateGroupConversationResp) ProtoMessage() {}
 
 func (x *CreateGroupConversationResp) ProtoReflect() protoreflect.Message {
-	mi := &file_im_proto_msgTypes[11]
+	mi := &file_im_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -750,14 +1829,14 @@ func (x *CreateGroupConversationResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateGroupConversationResp.ProtoReflect.Descriptor instead.
 func (*CreateGroupConversationResp) Descriptor() ([]byte, []int) {
-	return file_im_proto_rawDescGZIP(), []int{11}
+	return file_im_proto_rawDescGZIP(), []int{25}
 }
 
 var File_im_proto protoreflect.FileDescriptor
 
 const file_im_proto_rawDesc = "" +
 	"\n" +
-	"\bim.proto\x12\x02im\"\x85\x02\n" +
+	"\bim.proto\x12\x02im\"\xa7\x05\n" +
 	"\aChatLog\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12&\n" +
 	"\x0econversationId\x18\x02 \x01(\tR\x0econversationId\x12\x16\n" +
@@ -769,7 +1848,39 @@ const file_im_proto_rawDesc = "" +
 	"msgContent\x12\x1a\n" +
 	"\bchatType\x18\a \x01(\x05R\bchatType\x12\x1a\n" +
 	"\bSendTime\x18\b \x01(\x03R\bSendTime\x12 \n" +
-	"\vreadRecords\x18\t \x01(\fR\vreadRecords\"\xc7\x02\n" +
+	"\vreadRecords\x18\t \x01(\fR\vreadRecords\x12\x1a\n" +
+	"\bisEdited\x18\n" +
+	" \x01(\bR\bisEdited\x12\x1a\n" +
+	"\beditTime\x18\v \x01(\x03R\beditTime\x12&\n" +
+	"\x05quote\x18\f \x01(\v2\x10.im.ChatLogQuoteR\x05quote\x121\n" +
+	"\treactions\x18\r \x03(\v2\x13.im.ChatLogReactionR\treactions\x12;\n" +
+	"\fmergeForward\x18\x0e \x01(\v2\x17.im.ChatLogMergeForwardR\fmergeForward\x12\x1a\n" +
+	"\bexpireAt\x18\x0f \x01(\x03R\bexpireAt\x12\"\n" +
+	"\fdisappearTtl\x18\x10 \x01(\x03R\fdisappearTtl\x12*\n" +
+	"\x10deliveredRecords\x18\x11 \x01(\fR\x10deliveredRecords\x12\x10\n" +
+	"\x03seq\x18\x12 \x01(\x03R\x03seq\x128\n" +
+	"\vlinkPreview\x18\x13 \x01(\v2\x16.im.ChatLogLinkPreviewR\vlinkPreview\x12\x18\n" +
+	"\amsgFrom\x18\x14 \x01(\x05R\amsgFrom\"p\n" +
+	"\fChatLogQuote\x12\x14\n" +
+	"\x05msgId\x18\x01 \x01(\tR\x05msgId\x12\x16\n" +
+	"\x06sendId\x18\x02 \x01(\tR\x06sendId\x12\x18\n" +
+	"\amsgType\x18\x03 \x01(\x05R\amsgType\x12\x18\n" +
+	"\asnippet\x18\x04 \x01(\tR\asnippet\"\x83\x01\n" +
+	"\x13ChatLogMergeForward\x12&\n" +
+	"\x0econversationId\x18\x01 \x01(\tR\x0econversationId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
+	"\asummary\x18\x03 \x03(\tR\asummary\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x05R\x05count\"\x90\x01\n" +
+	"\x12ChatLogLinkPreview\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05image\x18\x04 \x01(\tR\x05image\x12\x1a\n" +
+	"\bsiteName\x18\x05 \x01(\tR\bsiteName\"W\n" +
+	"\x0fChatLogReaction\x12\x14\n" +
+	"\x05emoji\x18\x01 \x01(\tR\x05emoji\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x18\n" +
+	"\areacted\x18\x03 \x01(\bR\areacted\"\xeb\x03\n" +
 	"\fConversation\x12&\n" +
 	"\x0econversationId\x18\x01 \x01(\tR\x0econversationId\x12\x1a\n" +
 	"\bchatType\x18\x02 \x01(\x05R\bchatType\x12\x1a\n" +
@@ -783,7 +1894,12 @@ const file_im_proto_rawDesc = "" +
 	"\x04name\x18\n" +
 	" \x01(\tR\x04name\x12\x16\n" +
 	"\x06avatar\x18\v \x01(\tR\x06avatar\x12 \n" +
-	"\vmemberCount\x18\f \x01(\x05R\vmemberCount\"-\n" +
+	"\vmemberCount\x18\f \x01(\x05R\vmemberCount\x12\"\n" +
+	"\fmentionMsgId\x18\r \x01(\tR\fmentionMsgId\x12$\n" +
+	"\rdisappearMode\x18\x0e \x01(\x05R\rdisappearMode\x12\"\n" +
+	"\fdisappearTtl\x18\x0f \x01(\x03R\fdisappearTtl\x12\x18\n" +
+	"\areadSeq\x18\x10 \x01(\x03R\areadSeq\x12\x1a\n" +
+	"\bslowMode\x18\x11 \x01(\x03R\bslowMode\"-\n" +
 	"\x13GetConversationsReq\x12\x16\n" +
 	"\x06userId\x18\x01 \x01(\tR\x06userId\"\xc9\x01\n" +
 	"\x14GetConversationsResp\x12Z\n" +
@@ -798,15 +1914,66 @@ const file_im_proto_rawDesc = "" +
 	"\x15ConversationListEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12&\n" +
 	"\x05value\x18\x02 \x01(\v2\x10.im.ConversationR\x05value:\x028\x01\"\x16\n" +
-	"\x14PutConversationsResp\"\xab\x01\n" +
+	"\x14PutConversationsResp\"\x9b\x02\n" +
 	"\rGetChatLogReq\x12&\n" +
 	"\x0econversationId\x18\x01 \x01(\tR\x0econversationId\x12$\n" +
 	"\rstartSendTime\x18\x02 \x01(\x03R\rstartSendTime\x12 \n" +
 	"\vendSendTime\x18\x03 \x01(\x03R\vendSendTime\x12\x14\n" +
 	"\x05count\x18\x04 \x01(\x03R\x05count\x12\x14\n" +
-	"\x05msgId\x18\x05 \x01(\tR\x05msgId\"1\n" +
+	"\x05msgId\x18\x05 \x01(\tR\x05msgId\x12\x16\n" +
+	"\x06userId\x18\x06 \x01(\tR\x06userId\x12\x1c\n" +
+	"\tdirection\x18\a \x01(\x05R\tdirection\x12\x16\n" +
+	"\x06cursor\x18\b \x01(\tR\x06cursor\x12 \n" +
+	"\varoundMsgId\x18\t \x01(\tR\varoundMsgId\"\xc1\x01\n" +
 	"\x0eGetChatLogResp\x12\x1f\n" +
-	"\x04List\x18\x01 \x03(\v2\v.im.ChatLogR\x04List\"f\n" +
+	"\x04List\x18\x01 \x03(\v2\v.im.ChatLogR\x04List\x12$\n" +
+	"\rhasMoreBefore\x18\x02 \x01(\bR\rhasMoreBefore\x12\"\n" +
+	"\fhasMoreAfter\x18\x03 \x01(\bR\fhasMoreAfter\x12\"\n" +
+	"\fbeforeCursor\x18\x04 \x01(\tR\fbeforeCursor\x12 \n" +
+	"\vafterCursor\x18\x05 \x01(\tR\vafterCursor\"c\n" +
+	"\rGetRepliesReq\x12&\n" +
+	"\x0econversationId\x18\x01 \x01(\tR\x0econversationId\x12\x14\n" +
+	"\x05msgId\x18\x02 \x01(\tR\x05msgId\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05count\"1\n" +
+	"\x0eGetRepliesResp\x12\x1f\n" +
+	"\x04List\x18\x01 \x03(\v2\v.im.ChatLogR\x04List\"V\n" +
+	"\x12GetMergeForwardReq\x12\x16\n" +
+	"\x06userId\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05msgId\x18\x02 \x01(\tR\x05msgId\x12\x12\n" +
+	"\x04path\x18\x03 \x03(\tR\x04path\"t\n" +
+	"\x13GetMergeForwardResp\x12&\n" +
+	"\x0econversationId\x18\x01 \x01(\tR\x0econversationId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1f\n" +
+	"\x04List\x18\x03 \x03(\v2\v.im.ChatLogR\x04List\"^\n" +
+	"\rPinnedMessage\x12\x1d\n" +
+	"\x03msg\x18\x01 \x01(\v2\v.im.ChatLogR\x03msg\x12\x14\n" +
+	"\x05pinBy\x18\x02 \x01(\tR\x05pinBy\x12\x18\n" +
+	"\apinTime\x18\x03 \x01(\x03R\apinTime\"V\n" +
+	"\x14GetPinnedMessagesReq\x12\x16\n" +
+	"\x06userId\x18\x01 \x01(\tR\x06userId\x12&\n" +
+	"\x0econversationId\x18\x02 \x01(\tR\x0econversationId\">\n" +
+	"\x15GetPinnedMessagesResp\x12%\n" +
+	"\x04List\x18\x01 \x03(\v2\x11.im.PinnedMessageR\x04List\"\xaa\x02\n" +
+	"\x10SearchChatLogReq\x12\x16\n" +
+	"\x06userId\x18\x01 \x01(\tR\x06userId\x12\x18\n" +
+	"\akeyword\x18\x02 \x01(\tR\akeyword\x12(\n" +
+	"\x0fconversationIds\x18\x03 \x03(\tR\x0fconversationIds\x12&\n" +
+	"\x0econversationId\x18\x04 \x01(\tR\x0econversationId\x12\x16\n" +
+	"\x06sendId\x18\x05 \x01(\tR\x06sendId\x12\x14\n" +
+	"\x05mType\x18\x06 \x01(\x05R\x05mType\x12\x1c\n" +
+	"\tstartTime\x18\a \x01(\x03R\tstartTime\x12\x18\n" +
+	"\aendTime\x18\b \x01(\x03R\aendTime\x12\x16\n" +
+	"\x06cursor\x18\t \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05count\x18\n" +
+	" \x01(\x03R\x05count\"D\n" +
+	"\tSearchHit\x12\x1d\n" +
+	"\x03msg\x18\x01 \x01(\v2\v.im.ChatLogR\x03msg\x12\x18\n" +
+	"\asnippet\x18\x02 \x01(\tR\asnippet\"V\n" +
+	"\x11SearchChatLogResp\x12!\n" +
+	"\x04List\x18\x01 \x03(\v2\r.im.SearchHitR\x04List\x12\x1e\n" +
+	"\n" +
+	"nextCursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"f\n" +
 	"\x18SetUpUserConversationReq\x12\x16\n" +
 	"\x06SendId\x18\x01 \x01(\tR\x06SendId\x12\x16\n" +
 	"\x06recvId\x18\x02 \x01(\tR\x06recvId\x12\x1a\n" +
@@ -815,10 +1982,15 @@ const file_im_proto_rawDesc = "" +
 	"\x1aCreateGroupConversationReq\x12\x18\n" +
 	"\aGroupId\x18\x01 \x01(\tR\aGroupId\x12\x1a\n" +
 	"\bCreateId\x18\x02 \x01(\tR\bCreateId\"\x1d\n" +
-	"\x1bCreateGroupConversationResp2\xf9\x02\n" +
+	"\x1bCreateGroupConversationResp2\xfa\x04\n" +
 	"\x02Im\x123\n" +
 	"\n" +
-	"GetChatLog\x12\x11.im.GetChatLogReq\x1a\x12.im.GetChatLogResp\x12T\n" +
+	"GetChatLog\x12\x11.im.GetChatLogReq\x1a\x12.im.GetChatLogResp\x123\n" +
+	"\n" +
+	"GetReplies\x12\x11.im.GetRepliesReq\x1a\x12.im.GetRepliesResp\x12B\n" +
+	"\x0fGetMergeForward\x12\x16.im.GetMergeForwardReq\x1a\x17.im.GetMergeForwardResp\x12H\n" +
+	"\x11GetPinnedMessages\x12\x18.im.GetPinnedMessagesReq\x1a\x19.im.GetPinnedMessagesResp\x12<\n" +
+	"\rSearchChatLog\x12\x14.im.SearchChatLogReq\x1a\x15.im.SearchChatLogResp\x12T\n" +
 	"\x15SetUpUserConversation\x12\x1c.im.SetUpUserConversationReq\x1a\x1d.im.SetUpUserConversationResp\x12E\n" +
 	"\x10GetConversations\x12\x17.im.GetConversationsReq\x1a\x18.im.GetConversationsResp\x12E\n" +
 	"\x10PutConversations\x12\x17.im.PutConversationsReq\x1a\x18.im.PutConversationsResp\x12Z\n" +
@@ -836,45 +2008,77 @@ func file_im_proto_rawDescGZIP() []byte {
 	return file_im_proto_rawDescData
 }
 
-var file_im_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_im_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_im_proto_goTypes = []any{
 	(*ChatLog)(nil),                     // 0: im.ChatLog
-	(*Conversation)(nil),                // 1: im.Conversation
-	(*GetConversationsReq)(nil),         // 2: im.GetConversationsReq
-	(*GetConversationsResp)(nil),        // 3: im.GetConversationsResp
-	(*PutConversationsReq)(nil),         // 4: im.PutConversationsReq
-	(*PutConversationsResp)(nil),        // 5: im.PutConversationsResp
-	(*GetChatLogReq)(nil),               // 6: im.GetChatLogReq
-	(*GetChatLogResp)(nil),              // 7: im.GetChatLogResp
-	(*SetUpUserConversationReq)(nil),    // 8: im.SetUpUserConversationReq
-	(*SetUpUserConversationResp)(nil),   // 9: im.SetUpUserConversationResp
-	(*CreateGroupConversationReq)(nil),  // 10: im.CreateGroupConversationReq
-	(*CreateGroupConversationResp)(nil), // 11: im.CreateGroupConversationResp
-	nil,                                 // 12: im.GetConversationsResp.ConversationListEntry
-	nil,                                 // 13: im.PutConversationsReq.ConversationListEntry
+	(*ChatLogQuote)(nil),                // 1: im.ChatLogQuote
+	(*ChatLogMergeForward)(nil),         // 2: im.ChatLogMergeForward
+	(*ChatLogLinkPreview)(nil),          // 3: im.ChatLogLinkPreview
+	(*ChatLogReaction)(nil),             // 4: im.ChatLogReaction
+	(*Conversation)(nil),                // 5: im.Conversation
+	(*GetConversationsReq)(nil),         // 6: im.GetConversationsReq
+	(*GetConversationsResp)(nil),        // 7: im.GetConversationsResp
+	(*PutConversationsReq)(nil),         // 8: im.PutConversationsReq
+	(*PutConversationsResp)(nil),        // 9: im.PutConversationsResp
+	(*GetChatLogReq)(nil),               // 10: im.GetChatLogReq
+	(*GetChatLogResp)(nil),              // 11: im.GetChatLogResp
+	(*GetRepliesReq)(nil),               // 12: im.GetRepliesReq
+	(*GetRepliesResp)(nil),              // 13: im.GetRepliesResp
+	(*GetMergeForwardReq)(nil),          // 14: im.GetMergeForwardReq
+	(*GetMergeForwardResp)(nil),         // 15: im.GetMergeForwardResp
+	(*PinnedMessage)(nil),               // 16: im.PinnedMessage
+	(*GetPinnedMessagesReq)(nil),        // 17: im.GetPinnedMessagesReq
+	(*GetPinnedMessagesResp)(nil),       // 18: im.GetPinnedMessagesResp
+	(*SearchChatLogReq)(nil),            // 19: im.SearchChatLogReq
+	(*SearchHit)(nil),                   // 20: im.SearchHit
+	(*SearchChatLogResp)(nil),           // 21: im.SearchChatLogResp
+	(*SetUpUserConversationReq)(nil),    // 22: im.SetUpUserConversationReq
+	(*SetUpUserConversationResp)(nil),   // 23: im.SetUpUserConversationResp
+	(*CreateGroupConversationReq)(nil),  // 24: im.CreateGroupConversationReq
+	(*CreateGroupConversationResp)(nil), // 25: im.CreateGroupConversationResp
+	nil,                                 // 26: im.GetConversationsResp.ConversationListEntry
+	nil,                                 // 27: im.PutConversationsReq.ConversationListEntry
 }
 var file_im_proto_depIdxs = []int32{
-	0,  // 0: im.Conversation.msg:type_name -> im.ChatLog
-	12, // 1: im.GetConversationsResp.conversationList:type_name -> im.GetConversationsResp.ConversationListEntry
-	13, // 2: im.PutConversationsReq.conversationList:type_name -> im.PutConversationsReq.ConversationListEntry
-	0,  // 3: im.GetChatLogResp.List:type_name -> im.ChatLog
-	1,  // 4: im.GetConversationsResp.ConversationListEntry.value:type_name -> im.Conversation
-	1,  // 5: im.PutConversationsReq.ConversationListEntry.value:type_name -> im.Conversation
-	6,  // 6: im.Im.GetChatLog:input_type -> im.GetChatLogReq
-	8,  // 7: im.Im.SetUpUserConversation:input_type -> im.SetUpUserConversationReq
-	2,  // 8: im.Im.GetConversations:input_type -> im.GetConversationsReq
-	4,  // 9: im.Im.PutConversations:input_type -> im.PutConversationsReq
-	10, // 10: im.Im.CreateGroupConversation:input_type -> im.CreateGroupConversationReq
-	7,  // 11: im.Im.GetChatLog:output_type -> im.GetChatLogResp
-	9,  // 12: im.Im.SetUpUserConversation:output_type -> im.SetUpUserConversationResp
-	3,  // 13: im.Im.GetConversations:output_type -> im.GetConversationsResp
-	5,  // 14: im.Im.PutConversations:output_type -> im.PutConversationsResp
-	11, // 15: im.Im.CreateGroupConversation:output_type -> im.CreateGroupConversationResp
-	11, // [11:16] is the sub-list for method output_type
-	6,  // [6:11] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	1,  // 0: im.ChatLog.quote:type_name -> im.ChatLogQuote
+	4,  // 1: im.ChatLog.reactions:type_name -> im.ChatLogReaction
+	2,  // 2: im.ChatLog.mergeForward:type_name -> im.ChatLogMergeForward
+	3,  // 3: im.ChatLog.linkPreview:type_name -> im.ChatLogLinkPreview
+	0,  // 4: im.Conversation.msg:type_name -> im.ChatLog
+	26, // 5: im.GetConversationsResp.conversationList:type_name -> im.GetConversationsResp.ConversationListEntry
+	27, // 6: im.PutConversationsReq.conversationList:type_name -> im.PutConversationsReq.ConversationListEntry
+	0,  // 7: im.GetChatLogResp.List:type_name -> im.ChatLog
+	0,  // 8: im.GetRepliesResp.List:type_name -> im.ChatLog
+	0,  // 9: im.GetMergeForwardResp.List:type_name -> im.ChatLog
+	0,  // 10: im.PinnedMessage.msg:type_name -> im.ChatLog
+	16, // 11: im.GetPinnedMessagesResp.List:type_name -> im.PinnedMessage
+	0,  // 12: im.SearchHit.msg:type_name -> im.ChatLog
+	20, // 13: im.SearchChatLogResp.List:type_name -> im.SearchHit
+	5,  // 14: im.GetConversationsResp.ConversationListEntry.value:type_name -> im.Conversation
+	5,  // 15: im.PutConversationsReq.ConversationListEntry.value:type_name -> im.Conversation
+	10, // 16: im.Im.GetChatLog:input_type -> im.GetChatLogReq
+	12, // 17: im.Im.GetReplies:input_type -> im.GetRepliesReq
+	14, // 18: im.Im.GetMergeForward:input_type -> im.GetMergeForwardReq
+	17, // 19: im.Im.GetPinnedMessages:input_type -> im.GetPinnedMessagesReq
+	19, // 20: im.Im.SearchChatLog:input_type -> im.SearchChatLogReq
+	22, // 21: im.Im.SetUpUserConversation:input_type -> im.SetUpUserConversationReq
+	6,  // 22: im.Im.GetConversations:input_type -> im.GetConversationsReq
+	8,  // 23: im.Im.PutConversations:input_type -> im.PutConversationsReq
+	24, // 24: im.Im.CreateGroupConversation:input_type -> im.CreateGroupConversationReq
+	11, // 25: im.Im.GetChatLog:output_type -> im.GetChatLogResp
+	13, // 26: im.Im.GetReplies:output_type -> im.GetRepliesResp
+	15, // 27: im.Im.GetMergeForward:output_type -> im.GetMergeForwardResp
+	18, // 28: im.Im.GetPinnedMessages:output_type -> im.GetPinnedMessagesResp
+	21, // 29: im.Im.SearchChatLog:output_type -> im.SearchChatLogResp
+	23, // 30: im.Im.SetUpUserConversation:output_type -> im.SetUpUserConversationResp
+	7,  // 31: im.Im.GetConversations:output_type -> im.GetConversationsResp
+	9,  // 32: im.Im.PutConversations:output_type -> im.PutConversationsResp
+	25, // 33: im.Im.CreateGroupConversation:output_type -> im.CreateGroupConversationResp
+	25, // [25:34] is the sub-list for method output_type
+	16, // [16:25] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_im_proto_init() }
@@ -888,7 +2092,7 @@ func file_im_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_im_proto_rawDesc), len(file_im_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   14,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
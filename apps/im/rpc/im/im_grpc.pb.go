@@ -20,6 +20,10 @@ const _ = grpc.SupportPackageIsVersion7
 
 const (
 	Im_GetChatLog_FullMethodName              = "/im.Im/GetChatLog"
+	Im_GetReplies_FullMethodName              = "/im.Im/GetReplies"
+	Im_GetMergeForward_FullMethodName         = "/im.Im/GetMergeForward"
+	Im_GetPinnedMessages_FullMethodName       = "/im.Im/GetPinnedMessages"
+	Im_SearchChatLog_FullMethodName           = "/im.Im/SearchChatLog"
 	Im_SetUpUserConversation_FullMethodName   = "/im.Im/SetUpUserConversation"
 	Im_GetConversations_FullMethodName        = "/im.Im/GetConversations"
 	Im_PutConversations_FullMethodName        = "/im.Im/PutConversations"
@@ -32,6 +36,14 @@ const (
 type ImClient interface {
 	// 获取会话记录
 	GetChatLog(ctx context.Context, in *GetChatLogReq, opts ...grpc.CallOption) (*GetChatLogResp, error)
+	// 获取引用了某条消息的全部回复
+	GetReplies(ctx context.Context, in *GetRepliesReq, opts ...grpc.CallOption) (*GetRepliesResp, error)
+	// 展开合并转发的聊天记录
+	GetMergeForward(ctx context.Context, in *GetMergeForwardReq, opts ...grpc.CallOption) (*GetMergeForwardResp, error)
+	// 获取会话的置顶消息
+	GetPinnedMessages(ctx context.Context, in *GetPinnedMessagesReq, opts ...grpc.CallOption) (*GetPinnedMessagesResp, error)
+	// 在用户的会话中搜索聊天记录
+	SearchChatLog(ctx context.Context, in *SearchChatLogReq, opts ...grpc.CallOption) (*SearchChatLogResp, error)
 	// 建立会话: 群聊, 私聊
 	SetUpUserConversation(ctx context.Context, in *SetUpUserConversationReq, opts ...grpc.CallOption) (*SetUpUserConversationResp, error)
 	// 获取会话
@@ -58,6 +70,42 @@ func (c *imClient) GetChatLog(ctx context.Context, in *GetChatLogReq, opts ...gr
 	return out, nil
 }
 
+func (c *imClient) GetReplies(ctx context.Context, in *GetRepliesReq, opts ...grpc.CallOption) (*GetRepliesResp, error) {
+	out := new(GetRepliesResp)
+	err := c.cc.Invoke(ctx, Im_GetReplies_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imClient) GetMergeForward(ctx context.Context, in *GetMergeForwardReq, opts ...grpc.CallOption) (*GetMergeForwardResp, error) {
+	out := new(GetMergeForwardResp)
+	err := c.cc.Invoke(ctx, Im_GetMergeForward_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imClient) GetPinnedMessages(ctx context.Context, in *GetPinnedMessagesReq, opts ...grpc.CallOption) (*GetPinnedMessagesResp, error) {
+	out := new(GetPinnedMessagesResp)
+	err := c.cc.Invoke(ctx, Im_GetPinnedMessages_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *imClient) SearchChatLog(ctx context.Context, in *SearchChatLogReq, opts ...grpc.CallOption) (*SearchChatLogResp, error) {
+	out := new(SearchChatLogResp)
+	err := c.cc.Invoke(ctx, Im_SearchChatLog_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *imClient) SetUpUserConversation(ctx context.Context, in *SetUpUserConversationReq, opts ...grpc.CallOption) (*SetUpUserConversationResp, error) {
 	out := new(SetUpUserConversationResp)
 	err := c.cc.Invoke(ctx, Im_SetUpUserConversation_FullMethodName, in, out, opts...)
@@ -100,6 +148,14 @@ func (c *imClient) CreateGroupConversation(ctx context.Context, in *CreateGroupC
 type ImServer interface {
 	// 获取会话记录
 	GetChatLog(context.Context, *GetChatLogReq) (*GetChatLogResp, error)
+	// 获取引用了某条消息的全部回复
+	GetReplies(context.Context, *GetRepliesReq) (*GetRepliesResp, error)
+	// 展开合并转发的聊天记录
+	GetMergeForward(context.Context, *GetMergeForwardReq) (*GetMergeForwardResp, error)
+	// 获取会话的置顶消息
+	GetPinnedMessages(context.Context, *GetPinnedMessagesReq) (*GetPinnedMessagesResp, error)
+	// 在用户的会话中搜索聊天记录
+	SearchChatLog(context.Context, *SearchChatLogReq) (*SearchChatLogResp, error)
 	// 建立会话: 群聊, 私聊
 	SetUpUserConversation(context.Context, *SetUpUserConversationReq) (*SetUpUserConversationResp, error)
 	// 获取会话
@@ -117,6 +173,18 @@ type UnimplementedImServer struct {
 func (UnimplementedImServer) GetChatLog(context.Context, *GetChatLogReq) (*GetChatLogResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetChatLog not implemented")
 }
+func (UnimplementedImServer) GetReplies(context.Context, *GetRepliesReq) (*GetRepliesResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReplies not implemented")
+}
+func (UnimplementedImServer) GetMergeForward(context.Context, *GetMergeForwardReq) (*GetMergeForwardResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMergeForward not implemented")
+}
+func (UnimplementedImServer) GetPinnedMessages(context.Context, *GetPinnedMessagesReq) (*GetPinnedMessagesResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPinnedMessages not implemented")
+}
+func (UnimplementedImServer) SearchChatLog(context.Context, *SearchChatLogReq) (*SearchChatLogResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchChatLog not implemented")
+}
 func (UnimplementedImServer) SetUpUserConversation(context.Context, *SetUpUserConversationReq) (*SetUpUserConversationResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetUpUserConversation not implemented")
 }
@@ -160,6 +228,78 @@ func _Im_GetChatLog_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Im_GetReplies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRepliesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImServer).GetReplies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Im_GetReplies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImServer).GetReplies(ctx, req.(*GetRepliesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Im_GetMergeForward_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMergeForwardReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImServer).GetMergeForward(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Im_GetMergeForward_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImServer).GetMergeForward(ctx, req.(*GetMergeForwardReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Im_GetPinnedMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPinnedMessagesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImServer).GetPinnedMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Im_GetPinnedMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImServer).GetPinnedMessages(ctx, req.(*GetPinnedMessagesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Im_SearchChatLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchChatLogReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImServer).SearchChatLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Im_SearchChatLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImServer).SearchChatLog(ctx, req.(*SearchChatLogReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Im_SetUpUserConversation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SetUpUserConversationReq)
 	if err := dec(in); err != nil {
@@ -243,6 +383,22 @@ var Im_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetChatLog",
 			Handler:    _Im_GetChatLog_Handler,
 		},
+		{
+			MethodName: "GetReplies",
+			Handler:    _Im_GetReplies_Handler,
+		},
+		{
+			MethodName: "GetMergeForward",
+			Handler:    _Im_GetMergeForward_Handler,
+		},
+		{
+			MethodName: "GetPinnedMessages",
+			Handler:    _Im_GetPinnedMessages_Handler,
+		},
+		{
+			MethodName: "SearchChatLog",
+			Handler:    _Im_SearchChatLog_Handler,
+		},
 		{
 			MethodName: "SetUpUserConversation",
 			Handler:    _Im_SetUpUserConversation_Handler,
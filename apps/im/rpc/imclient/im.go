@@ -14,6 +14,10 @@ import (
 
 type (
 	ChatLog                     = im.ChatLog
+	ChatLogLinkPreview          = im.ChatLogLinkPreview
+	ChatLogMergeForward         = im.ChatLogMergeForward
+	ChatLogQuote                = im.ChatLogQuote
+	ChatLogReaction             = im.ChatLogReaction
 	Conversation                = im.Conversation
 	CreateGroupConversationReq  = im.CreateGroupConversationReq
 	CreateGroupConversationResp = im.CreateGroupConversationResp
@@ -21,14 +25,32 @@ type (
 	GetChatLogResp              = im.GetChatLogResp
 	GetConversationsReq         = im.GetConversationsReq
 	GetConversationsResp        = im.GetConversationsResp
+	GetMergeForwardReq          = im.GetMergeForwardReq
+	GetMergeForwardResp         = im.GetMergeForwardResp
+	GetPinnedMessagesReq        = im.GetPinnedMessagesReq
+	GetPinnedMessagesResp       = im.GetPinnedMessagesResp
+	GetRepliesReq               = im.GetRepliesReq
+	GetRepliesResp              = im.GetRepliesResp
+	PinnedMessage               = im.PinnedMessage
 	PutConversationsReq         = im.PutConversationsReq
 	PutConversationsResp        = im.PutConversationsResp
+	SearchChatLogReq            = im.SearchChatLogReq
+	SearchChatLogResp           = im.SearchChatLogResp
+	SearchHit                   = im.SearchHit
 	SetUpUserConversationReq    = im.SetUpUserConversationReq
 	SetUpUserConversationResp   = im.SetUpUserConversationResp
 
 	Im interface {
 		// 获取会话记录
 		GetChatLog(ctx context.Context, in *GetChatLogReq, opts ...grpc.CallOption) (*GetChatLogResp, error)
+		// 获取引用了某条消息的全部回复
+		GetReplies(ctx context.Context, in *GetRepliesReq, opts ...grpc.CallOption) (*GetRepliesResp, error)
+		// 展开合并转发的聊天记录
+		GetMergeForward(ctx context.Context, in *GetMergeForwardReq, opts ...grpc.CallOption) (*GetMergeForwardResp, error)
+		// 获取会话的置顶消息
+		GetPinnedMessages(ctx context.Context, in *GetPinnedMessagesReq, opts ...grpc.CallOption) (*GetPinnedMessagesResp, error)
+		// 在用户的会话中搜索聊天记录
+		SearchChatLog(ctx context.Context, in *SearchChatLogReq, opts ...grpc.CallOption) (*SearchChatLogResp, error)
 		// 建立会话: 群聊, 私聊
 		SetUpUserConversation(ctx context.Context, in *SetUpUserConversationReq, opts ...grpc.CallOption) (*SetUpUserConversationResp, error)
 		// 获取会话
@@ -55,6 +77,30 @@ func (m *defaultIm) GetChatLog(ctx context.Context, in *GetChatLogReq, opts ...g
 	return client.GetChatLog(ctx, in, opts...)
 }
 
+// 获取引用了某条消息的全部回复
+func (m *defaultIm) GetReplies(ctx context.Context, in *GetRepliesReq, opts ...grpc.CallOption) (*GetRepliesResp, error) {
+	client := im.NewImClient(m.cli.Conn())
+	return client.GetReplies(ctx, in, opts...)
+}
+
+// 展开合并转发的聊天记录
+func (m *defaultIm) GetMergeForward(ctx context.Context, in *GetMergeForwardReq, opts ...grpc.CallOption) (*GetMergeForwardResp, error) {
+	client := im.NewImClient(m.cli.Conn())
+	return client.GetMergeForward(ctx, in, opts...)
+}
+
+// 获取会话的置顶消息
+func (m *defaultIm) GetPinnedMessages(ctx context.Context, in *GetPinnedMessagesReq, opts ...grpc.CallOption) (*GetPinnedMessagesResp, error) {
+	client := im.NewImClient(m.cli.Conn())
+	return client.GetPinnedMessages(ctx, in, opts...)
+}
+
+// 在用户的会话中搜索聊天记录
+func (m *defaultIm) SearchChatLog(ctx context.Context, in *SearchChatLogReq, opts ...grpc.CallOption) (*SearchChatLogResp, error) {
+	client := im.NewImClient(m.cli.Conn())
+	return client.SearchChatLog(ctx, in, opts...)
+}
+
 // 建立会话: 群聊, 私聊
 func (m *defaultIm) SetUpUserConversation(ctx context.Context, in *SetUpUserConversationReq, opts ...grpc.CallOption) (*SetUpUserConversationResp, error) {
 	client := im.NewImClient(m.cli.Conn())
@@ -9,4 +9,16 @@ type Config struct {
 		Url string
 		Db  string
 	}
+
+	// Search 聊天记录搜索
+	Search struct {
+		// Engine mongo 使用 Mongo 文本索引，memory 使用进程内倒排索引
+		Engine string `json:",default=mongo,options=mongo|memory"`
+		// memory 模式下从 chat_log 同步消息的间隔，单位秒
+		SyncInterval int64 `json:",optional"`
+		// memory 模式下每次同步的最大消息数
+		BatchSize int64 `json:",optional"`
+		// memory 模式下每次同步回看的时间，单位秒
+		SyncLag int64 `json:",optional"`
+	} `json:",optional"`
 }
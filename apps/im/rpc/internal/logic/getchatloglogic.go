@@ -2,9 +2,11 @@ package logic
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/constants"
 	"imooc.com/easy-chat/pkg/ctxdata"
 	"imooc.com/easy-chat/pkg/xerr"
 
@@ -14,6 +16,12 @@ import (
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
+var (
+	ErrInvalidChatLogCursor    = xerr.New(xerr.REQUEST_PARAM_ERROR, "聊天记录游标无效")
+	ErrInvalidChatLogDirection = xerr.New(xerr.REQUEST_PARAM_ERROR, "不支持的翻页方向")
+	ErrChatLogNotFound         = xerr.New(xerr.REQUEST_PARAM_ERROR, "消息不存在")
+)
+
 type GetChatLogLogic struct {
 	ctx    context.Context
 	svcCtx *svc.ServiceContext
@@ -38,27 +46,30 @@ func (l *GetChatLogLogic) GetChatLog(in *im.GetChatLogReq) (*im.GetChatLogResp,
 		if err != nil {
 			return nil, errors.Wrapf(xerr.NewDBErr(), "find chatLog by msgId err %v, req %v", err, in.MsgId)
 		}
+		if chatlog.IsExpired(time.Now().UnixMilli()) {
+			return &im.GetChatLogResp{}, nil
+		}
 
 		return &im.GetChatLogResp{
-			List: []*im.ChatLog{{
-				Id:             chatlog.ID.Hex(),
-				ConversationId: chatlog.ConversationId,
-				SendId:         chatlog.SendId,
-				RecvId:         chatlog.RecvId,
-				MsgType:        int32(chatlog.MsgType),
-				MsgContent:     chatlog.MsgContent,
-				ChatType:       int32(chatlog.ChatType),
-				SendTime:       chatlog.SendTime,
-				ReadRecords:    chatlog.ReadRecords,
-			}},
+			List: []*im.ChatLog{toPbChatLog(chatlog, ctxdata.GetUId(l.ctx))},
 		}, nil
 	}
 
+	if in.AroundMsgId != "" {
+		return l.listAround(in)
+	}
+	if constants.ChatLogDirection(in.Direction) != constants.ChatLogBySendTime {
+		return l.listByCursor(in)
+	}
+
 	data, err := l.svcCtx.ChatLogModel.ListBySendTime(l.ctx, in.ConversationId, in.StartSendTime, in.EndSendTime, in.Count)
 	if err != nil {
 		return nil, errors.Wrapf(xerr.NewDBErr(), "find chatLog list by SendTime err %v, req %v", err, in)
 	}
 
+	// 过滤已过期但尚未被清理的阅后即焚消息
+	data = filterExpiredMessages(data)
+
 	// 过滤用户已删除的消息
 	userId := ctxdata.GetUId(l.ctx)
 	if userId != "" {
@@ -67,17 +78,7 @@ func (l *GetChatLogLogic) GetChatLog(in *im.GetChatLogReq) (*im.GetChatLogResp,
 
 	res := make([]*im.ChatLog, 0, len(data))
 	for _, datum := range data {
-		res = append(res, &im.ChatLog{
-			Id:             datum.ID.Hex(),
-			ConversationId: datum.ConversationId,
-			SendId:         datum.SendId,
-			RecvId:         datum.RecvId,
-			MsgType:        int32(datum.MsgType),
-			MsgContent:     datum.MsgContent,
-			ChatType:       int32(datum.ChatType),
-			SendTime:       datum.SendTime,
-			ReadRecords:    datum.ReadRecords,
-		})
+		res = append(res, toPbChatLog(datum, userId))
 	}
 
 	return &im.GetChatLogResp{
@@ -85,6 +86,134 @@ func (l *GetChatLogLogic) GetChatLog(in *im.GetChatLogReq) (*im.GetChatLogResp,
 	}, nil
 }
 
+// listByCursor 从游标处向前或向后翻页
+// 游标由 (seq, msgId) 组成，同一毫秒内发送的消息也不会被跳过或重复返回
+func (l *GetChatLogLogic) listByCursor(in *im.GetChatLogReq) (*im.GetChatLogResp, error) {
+	var before bool
+	switch constants.ChatLogDirection(in.Direction) {
+	case constants.ChatLogBefore:
+		before = true
+	case constants.ChatLogAfter:
+	default:
+		return nil, errors.WithStack(ErrInvalidChatLogDirection)
+	}
+
+	var cursor *immodels.ChatLogCursor
+	if in.Cursor != "" {
+		var err error
+		if cursor, err = immodels.ParseChatLogCursor(in.Cursor); err != nil {
+			return nil, errors.WithStack(ErrInvalidChatLogCursor)
+		}
+	}
+
+	data, hasMore, err := l.listFrom(in.ConversationId, cursor, before, chatLogCount(in.Count))
+	if err != nil {
+		return nil, err
+	}
+
+	// 没有查到消息时保留原游标，客户端可以稍后重试；从游标处翻页时，游标的另一侧一定还有消息
+	res := &im.GetChatLogResp{
+		BeforeCursor: in.Cursor,
+		AfterCursor:  in.Cursor,
+	}
+	if before {
+		res.HasMoreBefore, res.HasMoreAfter = hasMore, cursor != nil
+	} else {
+		res.HasMoreBefore, res.HasMoreAfter = cursor != nil, hasMore
+	}
+	l.fillPage(res, in, data)
+	return res, nil
+}
+
+// listAround 定位到某条消息，返回该消息及其前后各 count 条消息，用于从搜索结果或引用跳转
+func (l *GetChatLogLogic) listAround(in *im.GetChatLogReq) (*im.GetChatLogResp, error) {
+	target, err := l.svcCtx.ChatLogModel.FindOne(l.ctx, in.AroundMsgId)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) || errors.Is(err, immodels.ErrInvalidObjectId) {
+			return nil, errors.WithStack(ErrChatLogNotFound)
+		}
+		return nil, errors.Wrapf(xerr.NewDBErr(), "find chatLog by msgId err %v, req %v", err, in.AroundMsgId)
+	}
+	if in.ConversationId != "" && target.ConversationId != in.ConversationId {
+		return nil, errors.WithStack(ErrChatLogNotFound)
+	}
+
+	count := chatLogCount(in.Count)
+	cursor := immodels.NewChatLogCursor(target)
+	before, hasMoreBefore, err := l.listFrom(target.ConversationId, cursor, true, count)
+	if err != nil {
+		return nil, err
+	}
+	after, hasMoreAfter, err := l.listFrom(target.ConversationId, cursor, false, count)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]*immodels.ChatLog, 0, len(after)+len(before)+1)
+	data = append(data, after...)
+	data = append(data, target)
+	data = append(data, before...)
+
+	res := &im.GetChatLogResp{
+		HasMoreBefore: hasMoreBefore,
+		HasMoreAfter:  hasMoreAfter,
+	}
+	l.fillPage(res, &im.GetChatLogReq{
+		ConversationId: target.ConversationId,
+		UserId:         in.UserId,
+	}, data)
+	return res, nil
+}
+
+// listFrom 查询游标一侧的 count 条消息，多查一条判断是否还有更多
+func (l *GetChatLogLogic) listFrom(conversationId string, cursor *immodels.ChatLogCursor, before bool,
+	count int64) ([]*immodels.ChatLog, bool, error) {
+	data, err := l.svcCtx.ChatLogModel.ListByCursor(l.ctx, conversationId, cursor, before, count+1)
+	if err != nil {
+		return nil, false, errors.Wrapf(xerr.NewDBErr(), "find chatLog list by cursor err %v, conversationId %v",
+			err, conversationId)
+	}
+	if int64(len(data)) <= count {
+		return data, false, nil
+	}
+
+	// 结果按从新到旧排序，多出的一条在离游标最远的一端
+	if before {
+		return data[:count], true, nil
+	}
+	return data[1:], true, nil
+}
+
+// fillPage 生成翻页游标并过滤消息，data 按从新到旧排序
+// 游标根据过滤前的消息生成，被过滤的消息不会导致下一页重复或遗漏
+func (l *GetChatLogLogic) fillPage(res *im.GetChatLogResp, in *im.GetChatLogReq, data []*immodels.ChatLog) {
+	if len(data) > 0 {
+		res.AfterCursor = immodels.NewChatLogCursor(data[0]).String()
+		res.BeforeCursor = immodels.NewChatLogCursor(data[len(data)-1]).String()
+	}
+
+	data = filterExpiredMessages(data)
+	userId := in.UserId
+	if userId == "" {
+		userId = ctxdata.GetUId(l.ctx)
+	}
+	if userId != "" {
+		data = l.filterDeletedMessages(l.ctx, userId, in.ConversationId, data)
+	}
+
+	res.List = make([]*im.ChatLog, 0, len(data))
+	for _, datum := range data {
+		res.List = append(res.List, toPbChatLog(datum, userId))
+	}
+}
+
+func chatLogCount(count int64) int64 {
+	if count <= 0 || count > immodels.DefaultChatLogLimit {
+		return immodels.DefaultChatLogLimit
+	}
+	return count
+}
+
 // filterDeletedMessages 过滤掉用户已删除的消息
 func (l *GetChatLogLogic) filterDeletedMessages(ctx context.Context, userId, conversationId string, chatLogs []*immodels.ChatLog) []*immodels.ChatLog {
 	if len(chatLogs) == 0 {
@@ -127,3 +256,71 @@ func (l *GetChatLogLogic) filterDeletedMessages(ctx context.Context, userId, con
 
 	return filtered
 }
+
+// filterExpiredMessages 过滤掉已过期的阅后即焚消息
+func filterExpiredMessages(chatLogs []*immodels.ChatLog) []*immodels.ChatLog {
+	now := time.Now().UnixMilli()
+
+	filtered := chatLogs[:0]
+	for _, cl := range chatLogs {
+		if !cl.IsExpired(now) {
+			filtered = append(filtered, cl)
+		}
+	}
+	return filtered
+}
+
+// toPbChatLog 转换为 rpc 的聊天记录，userId 用于标记当前用户回应过的表情
+func toPbChatLog(chatLog *immodels.ChatLog, userId string) *im.ChatLog {
+	res := &im.ChatLog{
+		Id:               chatLog.ID.Hex(),
+		ConversationId:   chatLog.ConversationId,
+		SendId:           chatLog.SendId,
+		RecvId:           chatLog.RecvId,
+		MsgType:          int32(chatLog.MsgType),
+		MsgContent:       chatLog.MsgContent,
+		ChatType:         int32(chatLog.ChatType),
+		SendTime:         chatLog.SendTime,
+		Seq:              chatLog.Seq,
+		ReadRecords:      chatLog.ReadRecords,
+		DeliveredRecords: chatLog.DeliveredRecords,
+		IsEdited:         chatLog.IsEdited,
+		EditTime:         chatLog.EditTime,
+		ExpireAt:         chatLog.ExpireAt,
+		DisappearTtl:     chatLog.DisappearTtl,
+		MsgFrom:          int32(chatLog.MsgFrom),
+	}
+	if chatLog.Quote != nil {
+		res.Quote = &im.ChatLogQuote{
+			MsgId:   chatLog.Quote.MsgId,
+			SendId:  chatLog.Quote.SendId,
+			MsgType: int32(chatLog.Quote.MsgType),
+			Snippet: chatLog.Quote.Snippet,
+		}
+	}
+	if chatLog.MergeForward != nil {
+		res.MergeForward = &im.ChatLogMergeForward{
+			ConversationId: chatLog.MergeForward.ConversationId,
+			Title:          chatLog.MergeForward.Title,
+			Summary:        chatLog.MergeForward.Summary,
+			Count:          int32(len(chatLog.MergeForward.Items)),
+		}
+	}
+	if chatLog.LinkPreview != nil {
+		res.LinkPreview = &im.ChatLogLinkPreview{
+			Url:         chatLog.LinkPreview.Url,
+			Title:       chatLog.LinkPreview.Title,
+			Description: chatLog.LinkPreview.Description,
+			Image:       chatLog.LinkPreview.Image,
+			SiteName:    chatLog.LinkPreview.SiteName,
+		}
+	}
+	for _, reaction := range chatLog.ReactionCounts(userId) {
+		res.Reactions = append(res.Reactions, &im.ChatLogReaction{
+			Emoji:   reaction.Emoji,
+			Count:   int64(reaction.Count),
+			Reacted: reaction.Reacted,
+		})
+	}
+	return res
+}
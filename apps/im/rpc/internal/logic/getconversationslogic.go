@@ -43,6 +43,13 @@ func (l *GetConversationsLogic) GetConversations(in *im.GetConversationsReq) (*i
 	var res im.GetConversationsResp
 	copier.Copy(&res, &data)
 
+	// 有人@我
+	for id, conversation := range data.ConversationList {
+		if conversation.Mention != nil && res.ConversationList[id] != nil {
+			res.ConversationList[id].MentionMsgId = conversation.Mention.MsgId
+		}
+	}
+
 	// 根据会话列表，查询具体的会话
 	ids := make([]string, 0, len(data.ConversationList))
 	for _, conversation := range data.ConversationList {
@@ -53,11 +60,44 @@ func (l *GetConversationsLogic) GetConversations(in *im.GetConversationsReq) (*i
 		return nil, errors.Wrapf(xerr.NewDBErr(), "ConversationModel.ListByConversationIds err %v, req %v", err, ids)
 	}
 
+	// 用户的已读游标
+	cursors, err := l.svcCtx.ReadCursorModel.ListByUserId(l.ctx, in.UserId)
+	if err != nil {
+		return nil, errors.Wrapf(xerr.NewDBErr(), "ReadCursorModel.ListByUserId err %v, req %v", err, in.UserId)
+	}
+	readCursors := immodels.ReadCursors(cursors)
+
 	// 计算是否存在未读消息
 	for _, conversation := range conversations {
 		if _, ok := res.ConversationList[conversation.ConversationId]; !ok {
 			continue
 		}
+		if conversation.Disappear != nil {
+			res.ConversationList[conversation.ConversationId].DisappearMode = int32(conversation.Disappear.Mode)
+			res.ConversationList[conversation.ConversationId].DisappearTtl = conversation.Disappear.Ttl
+		}
+		if conversation.SlowMode != nil {
+			res.ConversationList[conversation.ConversationId].SlowMode = conversation.SlowMode.Interval
+		}
+		// 有已读游标时，未读数统计游标之后的消息，序号不连续，不能由最新序号与游标相减
+		if readSeq, ok := readCursors[conversation.ConversationId]; ok && conversation.Seq > 0 {
+			item := res.ConversationList[conversation.ConversationId]
+			item.Seq = conversation.Seq
+			item.ReadSeq = readSeq
+			item.Total = int32(conversation.Total)
+			item.ToRead = 0
+			if conversation.Seq > readSeq {
+				toRead, err := l.svcCtx.ChatLogModel.CountAfterSeq(l.ctx, conversation.ConversationId, in.UserId,
+					readSeq, immodels.UnreadCountLimit)
+				if err != nil {
+					return nil, errors.Wrapf(xerr.NewDBErr(), "ChatLogModel.CountAfterSeq err %v, req %v",
+						err, conversation.ConversationId)
+				}
+				item.ToRead = int32(toRead)
+				item.IsShow = toRead > 0
+			}
+			continue
+		}
 		// 用户读取的消息量
 		total := res.ConversationList[conversation.ConversationId].Total
 		if total < int32(conversation.Total) {
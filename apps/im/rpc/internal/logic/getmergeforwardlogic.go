@@ -0,0 +1,113 @@
+package logic
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/xerr"
+
+	"imooc.com/easy-chat/apps/im/rpc/im"
+	"imooc.com/easy-chat/apps/im/rpc/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+var (
+	ErrNotMergeForward       = xerr.New(xerr.REQUEST_PARAM_ERROR, "该消息不是合并转发的聊天记录")
+	ErrMergeForwardInvisible = xerr.NewMsg("聊天记录不存在或已被删除")
+)
+
+type GetMergeForwardLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewGetMergeForwardLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetMergeForwardLogic {
+	return &GetMergeForwardLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 展开合并转发的聊天记录
+func (l *GetMergeForwardLogic) GetMergeForward(in *im.GetMergeForwardReq) (*im.GetMergeForwardResp, error) {
+	chatLog, err := l.svcCtx.ChatLogModel.FindOne(l.ctx, in.MsgId)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			return nil, errors.WithStack(ErrMergeForwardInvisible)
+		}
+		return nil, errors.Wrapf(xerr.NewDBErr(), "find chatLog by msgId err %v, req %v", err, in.MsgId)
+	}
+	if chatLog.MsgType != constants.MergeForwardMType || chatLog.MergeForward == nil {
+		return nil, errors.WithStack(ErrNotMergeForward)
+	}
+
+	// 卡片本身需要对用户可见
+	visible, err := l.visible(in.UserId, chatLog)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, errors.WithStack(ErrMergeForwardInvisible)
+	}
+
+	// 逐层展开嵌套的合并转发
+	mergeForward := chatLog.MergeForward
+	for _, msgId := range in.Path {
+		var next *immodels.ChatLogMergeForward
+		for _, item := range mergeForward.Items {
+			if item.MsgId == msgId {
+				next = item.MergeForward
+				break
+			}
+		}
+		if next == nil {
+			return nil, errors.WithStack(ErrNotMergeForward)
+		}
+		mergeForward = next
+	}
+
+	list := make([]*im.ChatLog, 0, len(mergeForward.Items))
+	for _, item := range mergeForward.Items {
+		list = append(list, &im.ChatLog{
+			Id:             item.MsgId,
+			ConversationId: mergeForward.ConversationId,
+			SendId:         item.SendId,
+			MsgType:        int32(item.MsgType),
+			MsgContent:     item.MsgContent,
+			SendTime:       item.SendTime,
+		})
+	}
+
+	return &im.GetMergeForwardResp{
+		ConversationId: mergeForward.ConversationId,
+		Title:          mergeForward.Title,
+		List:           list,
+	}, nil
+}
+
+// visible 用户在卡片所在会话中，且卡片未撤回、未被用户删除
+func (l *GetMergeForwardLogic) visible(userId string, chatLog *immodels.ChatLog) (bool, error) {
+	if chatLog.Status == 4 {
+		return false, nil
+	}
+
+	conversations, err := l.svcCtx.ConversationsModel.FindByUserId(l.ctx, userId)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			return false, nil
+		}
+		return false, errors.Wrapf(xerr.NewDBErr(), "ConversationsModel.FindByUserId err %v, req %v", err, userId)
+	}
+	if conversations.ConversationList[chatLog.ConversationId] == nil {
+		return false, nil
+	}
+
+	data := NewGetChatLogLogic(l.ctx, l.svcCtx).filterDeletedMessages(l.ctx, userId, chatLog.ConversationId,
+		[]*immodels.ChatLog{chatLog})
+	return len(data) > 0, nil
+}
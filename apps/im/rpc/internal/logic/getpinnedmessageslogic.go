@@ -0,0 +1,83 @@
+package logic
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/xerr"
+
+	"imooc.com/easy-chat/apps/im/rpc/im"
+	"imooc.com/easy-chat/apps/im/rpc/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetPinnedMessagesLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewGetPinnedMessagesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetPinnedMessagesLogic {
+	return &GetPinnedMessagesLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 获取会话的置顶消息
+func (l *GetPinnedMessagesLogic) GetPinnedMessages(in *im.GetPinnedMessagesReq) (*im.GetPinnedMessagesResp, error) {
+	conversation, err := l.svcCtx.ConversationModel.FindOne(l.ctx, in.ConversationId)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			return &im.GetPinnedMessagesResp{}, nil
+		}
+		return nil, errors.Wrapf(xerr.NewDBErr(), "ConversationModel.FindOne err %v, req %v", err, in.ConversationId)
+	}
+	if len(conversation.Pins) == 0 {
+		return &im.GetPinnedMessagesResp{}, nil
+	}
+
+	pins := make(map[string]*immodels.ConversationPin, len(conversation.Pins))
+	msgIds := make([]string, 0, len(conversation.Pins))
+	for _, pin := range conversation.Pins {
+		pins[pin.MsgId] = pin
+		msgIds = append(msgIds, pin.MsgId)
+	}
+
+	data, err := l.svcCtx.ChatLogModel.ListByMsgIds(l.ctx, msgIds)
+	if err != nil && !errors.Is(err, immodels.ErrNotFound) {
+		return nil, errors.Wrapf(xerr.NewDBErr(), "find chatLog list by msgIds err %v, req %v", err, msgIds)
+	}
+
+	data = filterExpiredMessages(data)
+	// 用户删除或清空的消息，对该用户不再显示为置顶
+	if in.UserId != "" {
+		data = NewGetChatLogLogic(l.ctx, l.svcCtx).filterDeletedMessages(l.ctx, in.UserId, in.ConversationId, data)
+	}
+
+	list := make([]*im.PinnedMessage, 0, len(data))
+	for _, datum := range data {
+		if datum.Status == 4 {
+			continue
+		}
+		pin := pins[datum.ID.Hex()]
+		list = append(list, &im.PinnedMessage{
+			Msg:     toPbChatLog(datum, in.UserId),
+			PinBy:   pin.PinBy,
+			PinTime: pin.PinTime,
+		})
+	}
+
+	// 最近置顶的在前
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].PinTime > list[j].PinTime
+	})
+
+	return &im.GetPinnedMessagesResp{
+		List: list,
+	}, nil
+}
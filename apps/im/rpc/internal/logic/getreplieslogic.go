@@ -0,0 +1,53 @@
+package logic
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/ctxdata"
+	"imooc.com/easy-chat/pkg/xerr"
+
+	"imooc.com/easy-chat/apps/im/rpc/im"
+	"imooc.com/easy-chat/apps/im/rpc/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetRepliesLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewGetRepliesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetRepliesLogic {
+	return &GetRepliesLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 获取引用了某条消息的全部回复
+func (l *GetRepliesLogic) GetReplies(in *im.GetRepliesReq) (*im.GetRepliesResp, error) {
+	data, err := l.svcCtx.ChatLogModel.ListByQuoteMsgId(l.ctx, in.ConversationId, in.MsgId, in.Count)
+	if err != nil && !errors.Is(err, immodels.ErrNotFound) {
+		return nil, errors.Wrapf(xerr.NewDBErr(), "find chatLog list by quote msgId err %v, req %v", err, in)
+	}
+
+	// 与聊天记录一致，过滤已过期和用户已删除的消息
+	data = filterExpiredMessages(data)
+	userId := ctxdata.GetUId(l.ctx)
+	if userId != "" {
+		data = NewGetChatLogLogic(l.ctx, l.svcCtx).filterDeletedMessages(l.ctx, userId, in.ConversationId, data)
+	}
+
+	res := make([]*im.ChatLog, 0, len(data))
+	for _, datum := range data {
+		res = append(res, toPbChatLog(datum, userId))
+	}
+
+	return &im.GetRepliesResp{
+		List: res,
+	}, nil
+}
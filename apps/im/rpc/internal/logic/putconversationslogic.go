@@ -39,9 +39,15 @@ func (l *PutConversationsLogic) PutConversations(in *im.PutConversationsReq) (*i
 	}
 
 	for s, conversation := range in.ConversationList {
-		var oldTotal int
+		var (
+			oldTotal   int
+			oldClearUp int64
+			oldMention *immodels.ConversationMention
+		)
 		if data.ConversationList[s] != nil {
 			oldTotal = data.ConversationList[s].Total
+			oldClearUp = data.ConversationList[s].ClearUpTo
+			oldMention = data.ConversationList[s].Mention
 		}
 
 		data.ConversationList[s] = &immodels.Conversation{
@@ -50,6 +56,8 @@ func (l *PutConversationsLogic) PutConversations(in *im.PutConversationsReq) (*i
 			IsShow:         conversation.IsShow,
 			Total:          int(conversation.Read) + oldTotal,
 			Seq:            conversation.Seq,
+			ClearUpTo:      oldClearUp,
+			Mention:        oldMention,
 		}
 	}
 
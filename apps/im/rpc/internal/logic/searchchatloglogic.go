@@ -0,0 +1,210 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/search"
+	"imooc.com/easy-chat/pkg/xerr"
+
+	"imooc.com/easy-chat/apps/im/rpc/im"
+	"imooc.com/easy-chat/apps/im/rpc/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+var ErrInvalidSearchCursor = xerr.New(xerr.REQUEST_PARAM_ERROR, "搜索游标无效")
+
+const (
+	defaultSearchCount = 20
+	maxSearchCount     = 100
+	// 摘要保留的字符数
+	searchSnippetLen = 60
+	// 索引召回的消息被过滤后，继续向后补齐一页的最大次数
+	maxSearchRounds = 5
+)
+
+type SearchChatLogLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewSearchChatLogLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SearchChatLogLogic {
+	return &SearchChatLogLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// 在用户的会话中搜索聊天记录
+// 只搜索用户会话列表中、且调用方确认有权访问的会话，并排除清空之前、用户已删除、已撤回和已过期的消息
+func (l *SearchChatLogLogic) SearchChatLog(in *im.SearchChatLogReq) (*im.SearchChatLogResp, error) {
+	if len(search.Terms(in.Keyword)) == 0 {
+		return &im.SearchChatLogResp{}, nil
+	}
+
+	allowed, err := l.allowedConversations(in)
+	if err != nil {
+		return nil, err
+	}
+	if len(allowed) == 0 {
+		return &im.SearchChatLogResp{}, nil
+	}
+
+	count := int(in.Count)
+	if count <= 0 {
+		count = defaultSearchCount
+	}
+	if count > maxSearchCount {
+		count = maxSearchCount
+	}
+
+	q := &search.Query{
+		Keyword:       in.Keyword,
+		Conversations: allowed,
+		SendId:        in.SendId,
+		MType:         int(in.MType),
+		StartTime:     in.StartTime,
+		EndTime:       in.EndTime,
+		Cursor:        in.Cursor,
+	}
+
+	var (
+		list       = make([]*im.SearchHit, 0, count)
+		nextCursor string
+		deletes    = make(map[string]map[string]bool)
+	)
+	for round := 0; round < maxSearchRounds && len(list) < count; round++ {
+		q.Limit = count - len(list)
+		res, err := l.svcCtx.Indexer.Search(l.ctx, q)
+		if err != nil {
+			if errors.Is(err, search.ErrInvalidCursor) {
+				return nil, errors.WithStack(ErrInvalidSearchCursor)
+			}
+			return nil, errors.Wrapf(xerr.NewDBErr(), "Indexer.Search err %v, req %v", err, in)
+		}
+
+		hits, err := l.verify(in, allowed, deletes, res.Hits)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, hits...)
+
+		nextCursor = res.NextCursor
+		if nextCursor == "" {
+			break
+		}
+		q.Cursor = nextCursor
+	}
+
+	return &im.SearchChatLogResp{
+		List:       list,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// allowedConversations 计算可搜索的会话及其 ClearUpTo
+func (l *SearchChatLogLogic) allowedConversations(in *im.SearchChatLogReq) (map[string]int64, error) {
+	conversations, err := l.svcCtx.ConversationsModel.FindByUserId(l.ctx, in.UserId)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(xerr.NewDBErr(), "ConversationsModel.FindByUserId err %v, req %v", err, in.UserId)
+	}
+
+	allowed := make(map[string]int64, len(in.ConversationIds))
+	for _, conversationId := range in.ConversationIds {
+		if in.ConversationId != "" && conversationId != in.ConversationId {
+			continue
+		}
+		conversation, ok := conversations.ConversationList[conversationId]
+		if !ok {
+			continue
+		}
+		allowed[conversationId] = conversation.ClearUpTo
+	}
+	return allowed, nil
+}
+
+// verify 按消息的最新状态校验索引召回的结果，索引可能落后于消息的撤回、编辑与删除
+func (l *SearchChatLogLogic) verify(in *im.SearchChatLogReq, allowed map[string]int64,
+	deletes map[string]map[string]bool, hits []*search.Hit) ([]*im.SearchHit, error) {
+	if len(hits) == 0 {
+		return nil, nil
+	}
+
+	msgIds := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		msgIds = append(msgIds, hit.MsgId)
+	}
+	data, err := l.svcCtx.ChatLogModel.ListByMsgIds(l.ctx, msgIds)
+	if err != nil && !errors.Is(err, immodels.ErrNotFound) {
+		return nil, errors.Wrapf(xerr.NewDBErr(), "find chatLog list by msgIds err %v, req %v", err, msgIds)
+	}
+	chatLogs := make(map[string]*immodels.ChatLog, len(data))
+	for _, datum := range data {
+		chatLogs[datum.ID.Hex()] = datum
+	}
+
+	var (
+		now   = time.Now().UnixMilli()
+		stale []string
+		res   = make([]*im.SearchHit, 0, len(hits))
+	)
+	for _, hit := range hits {
+		chatLog, ok := chatLogs[hit.MsgId]
+		if !ok {
+			// 消息已被删除（如阅后即焚到期）
+			stale = append(stale, hit.MsgId)
+			continue
+		}
+		if chatLog.Status == 4 || chatLog.IsExpired(now) {
+			continue
+		}
+		clearUpTo, ok := allowed[chatLog.ConversationId]
+		if !ok || chatLog.SendTime <= clearUpTo {
+			continue
+		}
+		if !search.Match(chatLog.MsgContent, in.Keyword) {
+			continue
+		}
+		if l.isDeleted(in.UserId, chatLog, deletes) {
+			continue
+		}
+
+		res = append(res, &im.SearchHit{
+			Msg:     toPbChatLog(chatLog, in.UserId),
+			Snippet: search.Highlight(chatLog.MsgContent, in.Keyword, searchSnippetLen),
+		})
+	}
+
+	if len(stale) > 0 {
+		if err := l.svcCtx.Indexer.Delete(l.ctx, stale...); err != nil {
+			l.Errorf("SearchChatLog Indexer.Delete err %v", err)
+		}
+	}
+	return res, nil
+}
+
+// isDeleted 消息是否被用户删除，每个会话的删除记录只查询一次
+func (l *SearchChatLogLogic) isDeleted(userId string, chatLog *immodels.ChatLog,
+	deletes map[string]map[string]bool) bool {
+	deleted, ok := deletes[chatLog.ConversationId]
+	if !ok {
+		records, err := l.svcCtx.UserMessageDeletesModel.ListByUserIdAndConversation(l.ctx, userId, chatLog.ConversationId)
+		if err != nil {
+			l.Errorf("SearchChatLog ListByUserIdAndConversation err %v", err)
+		}
+		deleted = make(map[string]bool, len(records))
+		for _, record := range records {
+			deleted[record.MsgId] = true
+		}
+		deletes[chatLog.ConversationId] = deleted
+	}
+	return deleted[chatLog.ID.Hex()]
+}
@@ -28,6 +28,30 @@ func (s *ImServer) GetChatLog(ctx context.Context, in *im.GetChatLogReq) (*im.Ge
 	return l.GetChatLog(in)
 }
 
+// 获取引用了某条消息的全部回复
+func (s *ImServer) GetReplies(ctx context.Context, in *im.GetRepliesReq) (*im.GetRepliesResp, error) {
+	l := logic.NewGetRepliesLogic(ctx, s.svcCtx)
+	return l.GetReplies(in)
+}
+
+// 展开合并转发的聊天记录
+func (s *ImServer) GetMergeForward(ctx context.Context, in *im.GetMergeForwardReq) (*im.GetMergeForwardResp, error) {
+	l := logic.NewGetMergeForwardLogic(ctx, s.svcCtx)
+	return l.GetMergeForward(in)
+}
+
+// 获取会话的置顶消息
+func (s *ImServer) GetPinnedMessages(ctx context.Context, in *im.GetPinnedMessagesReq) (*im.GetPinnedMessagesResp, error) {
+	l := logic.NewGetPinnedMessagesLogic(ctx, s.svcCtx)
+	return l.GetPinnedMessages(in)
+}
+
+// 在用户的会话中搜索聊天记录
+func (s *ImServer) SearchChatLog(ctx context.Context, in *im.SearchChatLogReq) (*im.SearchChatLogResp, error) {
+	l := logic.NewSearchChatLogLogic(ctx, s.svcCtx)
+	return l.SearchChatLog(in)
+}
+
 // 建立会话: 群聊, 私聊
 func (s *ImServer) SetUpUserConversation(ctx context.Context, in *im.SetUpUserConversationReq) (*im.SetUpUserConversationResp, error) {
 	l := logic.NewSetUpUserConversationLogic(ctx, s.svcCtx)
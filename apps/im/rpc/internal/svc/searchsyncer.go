@@ -0,0 +1,137 @@
+package svc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/search"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultSearchSyncInterval  = 2 * time.Second
+	defaultSearchSyncBatchSize = int64(1000)
+	// 消息写入 chat_log 的顺序与发送时间不完全一致，每次同步回看一段时间，重复索引不影响结果
+	defaultSearchSyncLag = time.Minute
+)
+
+// SearchSyncer 把 chat_log 中的新消息与编辑同步到进程内的搜索索引
+// 启动时从头同步全部消息，之后按发送时间与编辑时间增量同步
+type SearchSyncer struct {
+	logx.Logger
+
+	chatLogModel immodels.ChatLogModel
+	indexer      search.Indexer
+
+	interval  time.Duration
+	batchSize int64
+	lag       int64 // 回看的时间，单位毫秒
+
+	once sync.Once
+	done chan struct{}
+}
+
+// NewSearchSyncer interval、batchSize 与 lag 不大于 0 时使用默认值
+func NewSearchSyncer(chatLogModel immodels.ChatLogModel, indexer search.Indexer, interval time.Duration,
+	batchSize int64, lag time.Duration) *SearchSyncer {
+	if interval <= 0 {
+		interval = defaultSearchSyncInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultSearchSyncBatchSize
+	}
+	if lag <= 0 {
+		lag = defaultSearchSyncLag
+	}
+
+	return &SearchSyncer{
+		Logger:       logx.WithContext(context.Background()),
+		chatLogModel: chatLogModel,
+		indexer:      indexer,
+		interval:     interval,
+		batchSize:    batchSize,
+		lag:          lag.Milliseconds(),
+		done:         make(chan struct{}),
+	}
+}
+
+func (s *SearchSyncer) Start() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	var sent, edited syncWatermark
+	for {
+		s.sync(context.Background(), "sendTime", &sent)
+		s.sync(context.Background(), "editTime", &edited)
+
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *SearchSyncer) Stop() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+// syncWatermark 已同步到的位置，按 (时间, _id) 排序
+type syncWatermark struct {
+	value int64
+	id    primitive.ObjectID
+}
+
+func (s *SearchSyncer) sync(ctx context.Context, field string, mark *syncWatermark) {
+	// 每轮从回看的位置开始，追上之后推进水位
+	value, id := mark.value-s.lag, primitive.NilObjectID
+	if mark.value == 0 {
+		value = 0
+	}
+
+	for {
+		chatLogs, err := s.chatLogModel.ListAfter(ctx, field, value, id, s.batchSize)
+		if err != nil {
+			s.Errorf("SearchSyncer ListAfter %v err %v", field, err)
+			return
+		}
+		if len(chatLogs) == 0 {
+			return
+		}
+
+		docs := make([]*search.Document, 0, len(chatLogs))
+		for _, chatLog := range chatLogs {
+			docs = append(docs, &search.Document{
+				MsgId:          chatLog.ID.Hex(),
+				ConversationId: chatLog.ConversationId,
+				SendId:         chatLog.SendId,
+				MType:          int(chatLog.MsgType),
+				SendTime:       chatLog.SendTime,
+				Content:        chatLog.MsgContent,
+			})
+		}
+		if err := s.indexer.Index(ctx, docs...); err != nil {
+			s.Errorf("SearchSyncer Index err %v", err)
+			return
+		}
+
+		last := chatLogs[len(chatLogs)-1]
+		value, id = last.SendTime, last.ID
+		if field == "editTime" {
+			value = last.EditTime
+		}
+		if value > mark.value || (value == mark.value && id.Hex() > mark.id.Hex()) {
+			mark.value, mark.id = value, id
+		}
+
+		if int64(len(chatLogs)) < s.batchSize {
+			return
+		}
+	}
+}
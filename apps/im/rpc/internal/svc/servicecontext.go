@@ -1,8 +1,11 @@
 package svc
 
 import (
+	"time"
+
 	"imooc.com/easy-chat/apps/im/immodels"
 	"imooc.com/easy-chat/apps/im/rpc/internal/config"
+	"imooc.com/easy-chat/pkg/search"
 )
 
 type ServiceContext struct {
@@ -12,15 +15,33 @@ type ServiceContext struct {
 	immodels.ConversationsModel
 	immodels.ConversationModel
 	immodels.UserMessageDeletesModel
+	immodels.ReadCursorModel
+
+	Indexer search.Indexer
+	// SearchSyncer 仅 memory 模式下有值
+	SearchSyncer *SearchSyncer
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
-	return &ServiceContext{
+	svc := &ServiceContext{
 		Config: c,
 
 		ChatLogModel:            immodels.MustChatLogModel(c.Mongo.Url, c.Mongo.Db),
 		ConversationsModel:      immodels.MustConversationsModel(c.Mongo.Url, c.Mongo.Db),
 		ConversationModel:       immodels.MustConversationModel(c.Mongo.Url, c.Mongo.Db),
 		UserMessageDeletesModel: immodels.MustUserMessageDeletesModel(c.Mongo.Url, c.Mongo.Db),
+		ReadCursorModel:         immodels.MustReadCursorModel(c.Mongo.Url, c.Mongo.Db),
 	}
+
+	switch c.Search.Engine {
+	case "memory":
+		svc.Indexer = search.NewMemoryIndex()
+		svc.SearchSyncer = NewSearchSyncer(svc.ChatLogModel, svc.Indexer,
+			time.Duration(c.Search.SyncInterval)*time.Second, c.Search.BatchSize,
+			time.Duration(c.Search.SyncLag)*time.Second)
+	default:
+		svc.Indexer = immodels.NewChatLogTextIndex(c.Mongo.Url, c.Mongo.Db)
+	}
+
+	return svc
 }
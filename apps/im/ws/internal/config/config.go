@@ -8,6 +8,9 @@ package config
 import (
 	"github.com/zeromicro/go-zero/core/service"
 	"github.com/zeromicro/go-zero/core/stores/redis"
+	"github.com/zeromicro/go-zero/zrpc"
+	"imooc.com/easy-chat/pkg/membership"
+	"imooc.com/easy-chat/pkg/mqx"
 )
 
 type Config struct {
@@ -20,11 +23,16 @@ type Config struct {
 	}
 	Redisx redis.RedisConf
 
+	SocialRpc zrpc.RpcClientConf
+
 	Mongo struct {
 		Url string
 		Db  string
 	}
 
+	// Queue 消息队列的后端，kafka、redis 或进程内的 memory，各个服务需要一致
+	Queue mqx.Conf `json:",optional"`
+
 	MsgChatTransfer struct {
 		Topic string
 		Addrs []string
@@ -39,4 +47,32 @@ type Config struct {
 		Topic string
 		Addrs []string
 	}
+
+	MsgEditTransfer struct {
+		Topic string
+		Addrs []string
+	}
+
+	MsgReactionTransfer struct {
+		Topic string
+		Addrs []string
+	}
+
+	MsgPinTransfer struct {
+		Topic string
+		Addrs []string
+	}
+
+	MsgCardActionTransfer struct {
+		Topic string
+		Addrs []string
+	}
+
+	// 慢速模式的设置在本地缓存的时间（秒），修改设置后最多延迟这么久生效
+	SlowMode struct {
+		CacheExpire int64 `json:",optional"`
+	}
+
+	// GroupMembers 群成员列表的缓存，用于判断群管理员，成员变化时由社交服务发布通知使缓存失效
+	GroupMembers membership.Conf `json:",optional"`
 }
@@ -0,0 +1,41 @@
+package conversation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"imooc.com/easy-chat/apps/im/ws/internal/svc"
+	"imooc.com/easy-chat/apps/im/ws/websocket"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+)
+
+// CardAction 点击卡片按钮，处理结果通过 ContentCardUpdate 推送给会话的全部参与者
+func CardAction(svc *svc.ServiceContext) websocket.HandlerFunc {
+	return func(srv *websocket.Server, conn *websocket.Conn, msg *websocket.Message) {
+		var data ws.CardAction
+		if err := mapstructure.Decode(msg.Data, &data); err != nil {
+			srv.Send(websocket.NewErrMessage(err), conn)
+			return
+		}
+
+		if data.ConversationId == "" || data.MsgId == "" || data.ButtonId == "" {
+			srv.Send(websocket.NewErrMessage(errors.New("msgId, conversationId and buttonId are required")), conn)
+			return
+		}
+
+		// 权限与卡片状态由 Task-MQ 查 ChatLog 后校验
+		err := svc.MsgCardActionTransferClient.Push(&mq.MsgCardActionTransfer{
+			MsgId:          data.MsgId,
+			ConversationId: data.ConversationId,
+			SendId:         conn.Uid,
+			ButtonId:       data.ButtonId,
+			ActionTime:     time.Now().UnixMilli(),
+		})
+		if err != nil {
+			srv.Send(websocket.NewErrMessage(err), conn)
+			return
+		}
+	}
+}
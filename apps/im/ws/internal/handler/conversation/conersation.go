@@ -6,6 +6,7 @@
 package conversation
 
 import (
+	"context"
 	"github.com/mitchellh/mapstructure"
 	"imooc.com/easy-chat/apps/im/ws/internal/svc"
 	"imooc.com/easy-chat/apps/im/ws/websocket"
@@ -34,6 +35,16 @@ func Chat(svc *svc.ServiceContext) websocket.HandlerFunc {
 			}
 		}
 
+		// 慢速模式在消息进入队列之前拦截
+		if data.ChatType == constants.GroupChatType && !slowModeAllow(svc, srv, conn, msg, data.RecvId) {
+			return
+		}
+
+		var quoteMsgId string
+		if data.Msg.Quote != nil {
+			quoteMsgId = data.Msg.Quote.MsgId
+		}
+
 		err := svc.MsgChatTransferClient.Push(&mq.MsgChatTransfer{
 			ConversationId: data.ConversationId,
 			ChatType:       data.ChatType,
@@ -42,9 +53,14 @@ func Chat(svc *svc.ServiceContext) websocket.HandlerFunc {
 			SendTime:       time.Now().UnixMilli(),
 			MType:          data.Msg.MType,
 			Content:        data.Msg.Content,
+			QuoteMsgId:     quoteMsgId,
+			Mentions:       data.Msg.Mentions,
 			MsgId:          msg.Id,
 		})
 		if err != nil {
+			if data.ChatType == constants.GroupChatType {
+				svc.SlowMode.Cancel(context.Background(), data.RecvId, conn.Uid)
+			}
 			srv.Send(websocket.NewErrMessage(err), conn)
 			return
 		}
@@ -66,6 +82,32 @@ func MarkRead(svc *svc.ServiceContext) websocket.HandlerFunc {
 			SendId:         conn.Uid,
 			RecvId:         data.RecvId,
 			MsgIds:         data.MsgIds,
+			ReadSeq:        data.ReadSeq,
+		})
+
+		if err != nil {
+			srv.Send(websocket.NewErrMessage(err), conn)
+			return
+		}
+	}
+}
+
+// MarkDelivered 送达回执，客户端收到推送的消息后确认
+func MarkDelivered(svc *svc.ServiceContext) websocket.HandlerFunc {
+	return func(srv *websocket.Server, conn *websocket.Conn, msg *websocket.Message) {
+		var data ws.MarkRead
+		if err := mapstructure.Decode(msg.Data, &data); err != nil {
+			srv.Send(websocket.NewErrMessage(err), conn)
+			return
+		}
+
+		err := svc.MsgReadTransferClient.Push(&mq.MsgMarkRead{
+			ChatType:       data.ChatType,
+			ConversationId: data.ConversationId,
+			SendId:         conn.Uid,
+			RecvId:         data.RecvId,
+			MsgIds:         data.MsgIds,
+			Delivered:      true,
 		})
 
 		if err != nil {
@@ -0,0 +1,44 @@
+package conversation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"imooc.com/easy-chat/apps/im/ws/internal/svc"
+	"imooc.com/easy-chat/apps/im/ws/websocket"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+)
+
+func Edit(svc *svc.ServiceContext) websocket.HandlerFunc {
+	return func(srv *websocket.Server, conn *websocket.Conn, msg *websocket.Message) {
+		var data ws.Edit
+		if err := mapstructure.Decode(msg.Data, &data); err != nil {
+			srv.Send(websocket.NewErrMessage(err), conn)
+			return
+		}
+
+		if data.ConversationId == "" || data.MsgId == "" {
+			srv.Send(websocket.NewErrMessage(errors.New("msgId and conversationId are required")), conn)
+			return
+		}
+		if data.Content == "" {
+			srv.Send(websocket.NewErrMessage(errors.New("content is required")), conn)
+			return
+		}
+
+		// 与撤回一致：权限与时间窗口由 Task-MQ 查 ChatLog 后校验
+		err := svc.MsgEditTransferClient.Push(&mq.MsgEditTransfer{
+			MsgId:          data.MsgId,
+			ConversationId: data.ConversationId,
+			SendId:         conn.Uid,
+			Content:        data.Content,
+			EditTime:       time.Now().UnixMilli(),
+		})
+		if err != nil {
+			srv.Send(websocket.NewErrMessage(err), conn)
+			return
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package conversation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/im/ws/internal/svc"
+	"imooc.com/easy-chat/apps/im/ws/websocket"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/wuid"
+)
+
+// 一次最多转发到的会话数
+const maxForwardTargets = 9
+
+func Forward(svc *svc.ServiceContext) websocket.HandlerFunc {
+	return func(srv *websocket.Server, conn *websocket.Conn, msg *websocket.Message) {
+		var data ws.Forward
+		if err := mapstructure.Decode(msg.Data, &data); err != nil {
+			srv.Send(websocket.NewErrMessage(err), conn)
+			return
+		}
+
+		if data.ConversationId == "" || len(data.MsgIds) == 0 {
+			srv.Send(websocket.NewErrMessage(errors.New("msgIds and conversationId are required")), conn)
+			return
+		}
+		if len(data.Targets) == 0 || len(data.Targets) > maxForwardTargets {
+			srv.Send(websocket.NewErrMessage(errors.New("invalid forward targets")), conn)
+			return
+		}
+		// 逐条转发时每条消息都会生成一条新消息，同样受数量限制
+		if len(data.MsgIds) > immodels.MaxMergeForwardCount {
+			srv.Send(websocket.NewErrMessage(errors.New("too many messages to forward")), conn)
+			return
+		}
+
+		forwards := make([]*mq.ChatForward, 0, len(data.MsgIds))
+		if data.Merge {
+			forwards = append(forwards, &mq.ChatForward{
+				ConversationId: data.ConversationId,
+				MsgIds:         data.MsgIds,
+				Merge:          true,
+				Title:          data.Title,
+			})
+		} else {
+			for _, msgId := range data.MsgIds {
+				forwards = append(forwards, &mq.ChatForward{
+					ConversationId: data.ConversationId,
+					MsgIds:         []string{msgId},
+				})
+			}
+		}
+
+		// 原消息是否可见由 Task-MQ 查 ChatLog 后校验
+		for _, target := range data.Targets {
+			var conversationId string
+			switch target.ChatType {
+			case constants.SingleChatType:
+				conversationId = wuid.CombineId(conn.Uid, target.RecvId)
+			case constants.GroupChatType:
+				conversationId = target.RecvId
+				// 一次转发到群里算作一次发言
+				if !slowModeAllow(svc, srv, conn, msg, target.RecvId) {
+					continue
+				}
+			default:
+				continue
+			}
+
+			for _, forward := range forwards {
+				err := svc.MsgChatTransferClient.Push(&mq.MsgChatTransfer{
+					ConversationId: conversationId,
+					ChatType:       target.ChatType,
+					SendId:         conn.Uid,
+					RecvId:         target.RecvId,
+					SendTime:       time.Now().UnixMilli(),
+					MsgId:          msg.Id,
+					Forward:        forward,
+				})
+				if err != nil {
+					srv.Send(websocket.NewErrMessage(err), conn)
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package conversation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"imooc.com/easy-chat/apps/im/ws/internal/svc"
+	"imooc.com/easy-chat/apps/im/ws/websocket"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+)
+
+func Pin(svc *svc.ServiceContext) websocket.HandlerFunc {
+	return pin(svc, false)
+}
+
+func Unpin(svc *svc.ServiceContext) websocket.HandlerFunc {
+	return pin(svc, true)
+}
+
+func pin(svc *svc.ServiceContext, unpin bool) websocket.HandlerFunc {
+	return func(srv *websocket.Server, conn *websocket.Conn, msg *websocket.Message) {
+		var data ws.Pin
+		if err := mapstructure.Decode(msg.Data, &data); err != nil {
+			srv.Send(websocket.NewErrMessage(err), conn)
+			return
+		}
+
+		if data.ConversationId == "" || data.MsgId == "" {
+			srv.Send(websocket.NewErrMessage(errors.New("msgId and conversationId are required")), conn)
+			return
+		}
+
+		// 权限与数量限制由 Task-MQ 查 ChatLog 后校验
+		err := svc.MsgPinTransferClient.Push(&mq.MsgPinTransfer{
+			MsgId:          data.MsgId,
+			ConversationId: data.ConversationId,
+			SendId:         conn.Uid,
+			Unpin:          unpin,
+			PinTime:        time.Now().UnixMilli(),
+		})
+		if err != nil {
+			srv.Send(websocket.NewErrMessage(err), conn)
+			return
+		}
+	}
+}
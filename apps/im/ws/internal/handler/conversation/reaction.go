@@ -0,0 +1,56 @@
+package conversation
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"imooc.com/easy-chat/apps/im/ws/internal/svc"
+	"imooc.com/easy-chat/apps/im/ws/websocket"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+)
+
+// 表情的最大字节数，一个组合 emoji 也不会超过这个长度
+const maxEmojiLen = 32
+
+func React(svc *svc.ServiceContext) websocket.HandlerFunc {
+	return reaction(svc, false)
+}
+
+func Unreact(svc *svc.ServiceContext) websocket.HandlerFunc {
+	return reaction(svc, true)
+}
+
+func reaction(svc *svc.ServiceContext, unreact bool) websocket.HandlerFunc {
+	return func(srv *websocket.Server, conn *websocket.Conn, msg *websocket.Message) {
+		var data ws.Reaction
+		if err := mapstructure.Decode(msg.Data, &data); err != nil {
+			srv.Send(websocket.NewErrMessage(err), conn)
+			return
+		}
+
+		if data.ConversationId == "" || data.MsgId == "" {
+			srv.Send(websocket.NewErrMessage(errors.New("msgId and conversationId are required")), conn)
+			return
+		}
+		// emoji 会作为 mongo 的字段名，不能包含 . 和 $
+		if data.Emoji == "" || len(data.Emoji) > maxEmojiLen || strings.ContainsAny(data.Emoji, ".$") {
+			srv.Send(websocket.NewErrMessage(errors.New("invalid emoji")), conn)
+			return
+		}
+
+		// 数量限制与权限由 Task-MQ 查 ChatLog 后校验
+		err := svc.MsgReactionTransferClient.Push(&mq.MsgReactionTransfer{
+			MsgId:          data.MsgId,
+			ConversationId: data.ConversationId,
+			SendId:         conn.Uid,
+			Emoji:          data.Emoji,
+			Unreact:        unreact,
+		})
+		if err != nil {
+			srv.Send(websocket.NewErrMessage(err), conn)
+			return
+		}
+	}
+}
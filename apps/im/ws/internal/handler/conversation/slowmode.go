@@ -0,0 +1,40 @@
+package conversation
+
+import (
+	"context"
+	"fmt"
+
+	"imooc.com/easy-chat/apps/im/ws/internal/svc"
+	"imooc.com/easy-chat/apps/im/ws/websocket"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+)
+
+const slowModeErrCode = "slow_mode"
+
+// slowModeAllow 检查用户在群中的发言频率，受限时向发送者返回带剩余冷却时间的错误帧
+//
+// 限流本身出错时放行，不因 redis 或 rpc 故障影响正常聊天
+func slowModeAllow(svc *svc.ServiceContext, srv *websocket.Server, conn *websocket.Conn, msg *websocket.Message,
+	groupId string) bool {
+	ok, interval, remaining, err := svc.SlowMode.Allow(context.Background(), groupId, conn.Uid)
+	if err != nil {
+		srv.Errorf("slow mode check err %v, groupId %v, uid %v", err, groupId, conn.Uid)
+		return true
+	}
+	if ok {
+		return true
+	}
+
+	srv.Send(&websocket.Message{
+		FrameType: websocket.FrameErr,
+		Id:        msg.Id,
+		Data: &ws.SlowModeErr{
+			Code:           slowModeErrCode,
+			Msg:            fmt.Sprintf("慢速模式已开启，请在 %d 秒后再发言", remaining),
+			ConversationId: groupId,
+			Interval:       interval,
+			Remaining:      remaining,
+		},
+	}, conn)
+	return false
+}
@@ -6,11 +6,14 @@
 package push
 
 import (
+	"encoding/json"
+
 	"github.com/mitchellh/mapstructure"
 	"imooc.com/easy-chat/apps/im/ws/internal/svc"
 	"imooc.com/easy-chat/apps/im/ws/websocket"
 	"imooc.com/easy-chat/apps/im/ws/ws"
 	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/fanout"
 )
 
 func Push(svc *svc.ServiceContext) websocket.HandlerFunc {
@@ -44,30 +47,88 @@ func single(srv *websocket.Server, data *ws.Push, recvId string) error {
 
 	srv.Infof("push msg %v", data)
 
-	return srv.Send(websocket.NewMessage(data.SendId, &ws.Chat{
+	return srv.Send(newPushMessage(data, isMentioned(data.Mentions, recvId)), rconn)
+}
+
+// newPushMessage 推送给客户端的消息，除了是否被@之外，群里每个接收者收到的内容相同
+func newPushMessage(data *ws.Push, mentioned bool) *websocket.Message {
+	return websocket.NewMessage(data.SendId, &ws.Chat{
 		ConversationId: data.ConversationId,
 		ChatType:       data.ChatType,
 		SendTime:       data.SendTime,
 		Msg: ws.Msg{
-			ReadRecords: data.ReadRecords,
-			MsgId:       data.MsgId,
-			MType:       data.MType,
-			Content:     data.Content,
+			ReadRecords:      data.ReadRecords,
+			DeliveredRecords: data.DeliveredRecords,
+			ReadCursors:      data.ReadCursors,
+			MsgId:            data.MsgId,
+			Seq:              data.Seq,
+			MType:            data.MType,
+			Content:          data.Content,
+			ContentType:      data.ContentType,
+			IsEdited:         data.IsEdited,
+			Quote:            data.Quote,
+			Reactions:        data.Reactions,
+			Mentions:         data.Mentions,
+			MergeForward:     data.MergeForward,
+			ExpireAt:         data.ExpireAt,
+			DisappearTtl:     data.DisappearTtl,
+			LinkPreview:      data.LinkPreview,
+			MsgFrom:          data.MsgFrom,
+			Mentioned:        mentioned,
 		},
-	}), rconn)
+	})
+}
+
+// isMentioned 判断接收者是否被@
+func isMentioned(mentions []string, recvId string) bool {
+	for _, uid := range mentions {
+		if uid == recvId || uid == constants.MentionAll {
+			return true
+		}
+	}
+	return false
 }
 
+// group 推送给本节点上的群成员
+//
+// 一次加锁取出全部接收者的连接，内容按是否被@最多序列化两次，再由固定数量的 goroutine 写给各个连接
 func group(srv *websocket.Server, data *ws.Push) error {
-	for _, id := range data.RecvIds {
-		func(id string) {
-			srv.Schedule(func() {
-				single(srv, data, id)
-			})
-		}(id)
+	plain, err := json.Marshal(newPushMessage(data, false))
+	if err != nil {
+		return err
+	}
+	mentioned := plain
+	if len(data.Mentions) > 0 {
+		if mentioned, err = json.Marshal(newPushMessage(data, true)); err != nil {
+			return err
+		}
 	}
+
+	broadcast(srv, data.RecvIds, func(recvId string) []byte {
+		if isMentioned(data.Mentions, recvId) {
+			return mentioned
+		}
+		return plain
+	})
 	return nil
 }
 
+// broadcast 在后台把内容写给在线的接收者，不阻塞当前连接上后续消息的处理
+func broadcast(srv *websocket.Server, recvIds []string, payload func(recvId string) []byte) {
+	conns := srv.GetConns(recvIds...)
+	srv.Schedule(func() {
+		failed := fanout.Broadcast(len(conns), fanout.DefaultConcurrency, func(i int) error {
+			if conns[i] == nil {
+				return nil
+			}
+			return srv.SendBytes(payload(recvIds[i]), conns[i])
+		})
+		if failed > 0 {
+			srv.Errorf("group push failed %d of %d", failed, len(conns))
+		}
+	})
+}
+
 // revoke 推送撤回通知（与普通消息推送格式不同）
 func revoke(srv *websocket.Server, data *ws.Push) {
 	switch data.ChatType {
@@ -87,25 +148,22 @@ func revoke(srv *websocket.Server, data *ws.Push) {
 			},
 		}), rconn)
 	case constants.GroupChatType:
-		for _, id := range data.RecvIds {
-			func(id string) {
-				srv.Schedule(func() {
-					rconn := srv.GetConn(id)
-					if rconn == nil {
-						return
-					}
-					srv.Send(websocket.NewMessage(data.SendId, &ws.Chat{
-						ConversationId: data.ConversationId,
-						ChatType:       data.ChatType,
-						SendTime:       data.SendTime,
-						Msg: ws.Msg{
-							MsgId:   data.MsgId,
-							Content: data.Content,
-							MType:   data.MType,
-						},
-					}), rconn)
-				})
-			}(id)
+		payload, err := json.Marshal(websocket.NewMessage(data.SendId, &ws.Chat{
+			ConversationId: data.ConversationId,
+			ChatType:       data.ChatType,
+			SendTime:       data.SendTime,
+			Msg: ws.Msg{
+				MsgId:   data.MsgId,
+				Content: data.Content,
+				MType:   data.MType,
+			},
+		}))
+		if err != nil {
+			srv.Errorf("revoke push marshal err %v", err)
+			return
 		}
+		broadcast(srv, data.RecvIds, func(string) []byte {
+			return payload
+		})
 	}
 }
@@ -0,0 +1,205 @@
+package push
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+	"github.com/zeromicro/go-zero/core/logx"
+	"imooc.com/easy-chat/apps/im/ws/websocket"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+const (
+	benchMembers = 5000
+	benchGroupId = "group-1"
+)
+
+// userIdAuth 使用请求参数中的 userId 作为用户
+type userIdAuth struct{}
+
+func (userIdAuth) Auth(w http.ResponseWriter, r *http.Request) bool {
+	return true
+}
+
+func (userIdAuth) UserId(r *http.Request) string {
+	return r.URL.Query().Get("userId")
+}
+
+// pushCluster 网关节点以及群成员的客户端连接，约十分之一的成员不在线
+type pushCluster struct {
+	srv    *websocket.Server
+	uids   []string
+	online int
+	// 每个在线成员收到一帧时写入一次
+	received chan string
+	mentions chan string
+}
+
+func newPushCluster(tb testing.TB, members int) *pushCluster {
+	tb.Helper()
+	logx.Disable()
+
+	srv := websocket.NewServer("127.0.0.1:0", websocket.WithServerAuthentication(userIdAuth{}))
+	hs := httptest.NewServer(http.HandlerFunc(srv.ServerWs))
+	c := &pushCluster{
+		srv:      srv,
+		uids:     make([]string, members),
+		received: make(chan string, members),
+		mentions: make(chan string, members),
+	}
+
+	var clients []*gorilla.Conn
+	tb.Cleanup(func() {
+		for _, client := range clients {
+			client.Close()
+		}
+		hs.Close()
+	})
+
+	url := "ws" + strings.TrimPrefix(hs.URL, "http") + "?userId="
+	for i := range c.uids {
+		c.uids[i] = fmt.Sprintf("user-%05d", i)
+		if i%10 == 0 {
+			continue
+		}
+
+		client, _, err := gorilla.DefaultDialer.Dial(url+c.uids[i], nil)
+		if err != nil {
+			tb.Fatalf("dial %v err %v", c.uids[i], err)
+		}
+		clients = append(clients, client)
+		c.online++
+
+		go func(uid string) {
+			for {
+				_, data, err := client.ReadMessage()
+				if err != nil {
+					return
+				}
+				if strings.Contains(string(data), `"Mentioned":true`) {
+					select {
+					case c.mentions <- uid:
+					default:
+					}
+				}
+				c.received <- uid
+			}
+		}(c.uids[i])
+	}
+
+	// 连接在握手之后才记录到网关节点
+	deadline := time.Now().Add(10 * time.Second)
+	for len(srv.GetUsers()) < c.online {
+		if time.Now().After(deadline) {
+			tb.Fatalf("registered %d conns, want %d", len(srv.GetUsers()), c.online)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return c
+}
+
+// wait 等待 n 个在线成员收到推送
+func (c *pushCluster) wait(tb testing.TB, n int) {
+	tb.Helper()
+	timeout := time.After(10 * time.Second)
+	for i := 0; i < n; i++ {
+		select {
+		case <-c.received:
+		case <-timeout:
+			tb.Fatalf("received %d of %d pushes", i, n)
+		}
+	}
+}
+
+// newPushFrame 与 task 发送给网关的帧相同，Data 经过 json 解码后交给 push 处理
+func newPushFrame(tb testing.TB, push *ws.Push) *websocket.Message {
+	tb.Helper()
+	body, err := json.Marshal(websocket.Message{FrameType: websocket.FrameData, Method: "push", Data: push})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	var msg websocket.Message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		tb.Fatal(err)
+	}
+	return &msg
+}
+
+func newGroupPush(recvIds []string) *ws.Push {
+	return &ws.Push{
+		ConversationId: benchGroupId,
+		ChatType:       constants.GroupChatType,
+		SendId:         "user-00000",
+		RecvId:         benchGroupId,
+		RecvIds:        recvIds,
+		SendTime:       1700000000000,
+		MsgId:          "65f0c0ffee0000000000abcd",
+		MType:          constants.TextMType,
+		Content:        "大家好，今晚八点开会，请准时参加",
+		Mentions:       []string{"user-00001"},
+	}
+}
+
+func TestPush_Group(t *testing.T) {
+	c := newPushCluster(t, 100)
+
+	Push(nil)(c.srv, nil, newPushFrame(t, newGroupPush(c.uids)))
+	c.wait(t, c.online)
+
+	select {
+	case uid := <-c.mentions:
+		if uid != "user-00001" {
+			t.Errorf("mentioned push sent to %v", uid)
+		}
+	case <-time.After(time.Second):
+		t.Error("mentioned user did not receive the mentioned push")
+	}
+	select {
+	case uid := <-c.received:
+		t.Errorf("extra push to %v", uid)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// BenchmarkPush_Group 一帧推送给节点上的 5000 个群成员
+func BenchmarkPush_Group(b *testing.B) {
+	c := newPushCluster(b, benchMembers)
+	handler := Push(nil)
+	frame := newPushFrame(b, newGroupPush(c.uids))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		handler(c.srv, nil, frame)
+		c.wait(b, c.online)
+	}
+}
+
+// BenchmarkPush_PerRecipient 对照：每个成员一帧单聊推送，即按接收方逐个推送的方式
+func BenchmarkPush_PerRecipient(b *testing.B) {
+	c := newPushCluster(b, benchMembers)
+	handler := Push(nil)
+	frames := make([]*websocket.Message, len(c.uids))
+	for i, uid := range c.uids {
+		push := newGroupPush(nil)
+		push.ChatType = constants.SingleChatType
+		push.RecvId = uid
+		frames[i] = newPushFrame(b, push)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, frame := range frames {
+			handler(c.srv, nil, frame)
+		}
+		c.wait(b, c.online)
+	}
+}
@@ -27,10 +27,42 @@ func RegisterHandlers(srv *websocket.Server, svc *svc.ServiceContext) {
 			Method:  "conversation.markChat",
 			Handler: conversation.MarkRead(svc),
 		},
+		{
+			Method:  "conversation.delivered",
+			Handler: conversation.MarkDelivered(svc),
+		},
 		{
 			Method:  "conversation.revoke",
 			Handler: conversation.Revoke(svc),
 		},
+		{
+			Method:  "conversation.edit",
+			Handler: conversation.Edit(svc),
+		},
+		{
+			Method:  "conversation.react",
+			Handler: conversation.React(svc),
+		},
+		{
+			Method:  "conversation.unreact",
+			Handler: conversation.Unreact(svc),
+		},
+		{
+			Method:  "conversation.forward",
+			Handler: conversation.Forward(svc),
+		},
+		{
+			Method:  "conversation.pin",
+			Handler: conversation.Pin(svc),
+		},
+		{
+			Method:  "conversation.unpin",
+			Handler: conversation.Unpin(svc),
+		},
+		{
+			Method:  "conversation.cardAction",
+			Handler: conversation.CardAction(svc),
+		},
 		{
 			Method:  "push",
 			Handler: push.Push(svc),
@@ -6,9 +6,16 @@
 package svc
 
 import (
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/redis"
+	"github.com/zeromicro/go-zero/zrpc"
 	"imooc.com/easy-chat/apps/im/immodels"
 	"imooc.com/easy-chat/apps/im/ws/internal/config"
+	"imooc.com/easy-chat/apps/social/rpc/socialclient"
 	"imooc.com/easy-chat/apps/task/mq/mqclient"
+	"imooc.com/easy-chat/pkg/membership"
+	"imooc.com/easy-chat/pkg/mqx"
 )
 
 type ServiceContext struct {
@@ -18,14 +25,41 @@ type ServiceContext struct {
 	mqclient.MsgChatTransferClient
 	mqclient.MsgReadTransferClient
 	mqclient.MsgRevokeTransferClient
+	mqclient.MsgEditTransferClient
+	mqclient.MsgReactionTransferClient
+	mqclient.MsgPinTransferClient
+	mqclient.MsgCardActionTransferClient
+
+	SlowMode *SlowMode
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
+	rds := redis.MustNewRedis(c.Redisx)
+	groupMembers := membership.NewCache(
+		socialclient.NewMembershipLoader(socialclient.NewSocial(zrpc.MustNewClient(c.SocialRpc))),
+		membership.WithRedis(rds), membership.WithConf(c.GroupMembers))
+	groupMembers.Watch(c.Redisx)
+
 	return &ServiceContext{
-		Config:                  c,
-		MsgChatTransferClient:   mqclient.NewMsgChatTransferClient(c.MsgChatTransfer.Addrs, c.MsgChatTransfer.Topic),
-		MsgReadTransferClient:   mqclient.NewMsgReadTransferClient(c.MsgReadTransfer.Addrs, c.MsgReadTransfer.Topic),
-		MsgRevokeTransferClient: mqclient.NewMsgRevokeTransferClient(c.MsgRevokeTransfer.Addrs, c.MsgRevokeTransfer.Topic),
-		ChatLogModel:            immodels.MustChatLogModel(c.Mongo.Url, c.Mongo.Db),
+		Config: c,
+		MsgChatTransferClient: mqclient.NewMsgChatTransferClient(
+			mqx.NewProducer(c.Queue, c.MsgChatTransfer.Addrs, c.MsgChatTransfer.Topic)),
+		MsgReadTransferClient: mqclient.NewMsgReadTransferClient(
+			mqx.NewProducer(c.Queue, c.MsgReadTransfer.Addrs, c.MsgReadTransfer.Topic)),
+		MsgRevokeTransferClient: mqclient.NewMsgRevokeTransferClient(
+			mqx.NewProducer(c.Queue, c.MsgRevokeTransfer.Addrs, c.MsgRevokeTransfer.Topic)),
+		MsgEditTransferClient: mqclient.NewMsgEditTransferClient(
+			mqx.NewProducer(c.Queue, c.MsgEditTransfer.Addrs, c.MsgEditTransfer.Topic)),
+		MsgReactionTransferClient: mqclient.NewMsgReactionTransferClient(
+			mqx.NewProducer(c.Queue, c.MsgReactionTransfer.Addrs, c.MsgReactionTransfer.Topic)),
+		MsgPinTransferClient: mqclient.NewMsgPinTransferClient(
+			mqx.NewProducer(c.Queue, c.MsgPinTransfer.Addrs, c.MsgPinTransfer.Topic)),
+		ChatLogModel: immodels.MustChatLogModel(c.Mongo.Url, c.Mongo.Db),
+
+		MsgCardActionTransferClient: mqclient.NewMsgCardActionTransferClient(
+			mqx.NewProducer(c.Queue, c.MsgCardActionTransfer.Addrs, c.MsgCardActionTransfer.Topic)),
+
+		SlowMode: NewSlowMode(rds, immodels.MustConversationModel(c.Mongo.Url, c.Mongo.Db), groupMembers,
+			time.Duration(c.SlowMode.CacheExpire)*time.Second),
 	}
 }
@@ -0,0 +1,115 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/collection"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/membership"
+)
+
+// 慢速模式设置的本地缓存时间，也是修改设置后生效的最大延迟
+const defaultSlowModeCacheExpire = 10 * time.Second
+
+// SlowMode 群聊慢速模式的限流
+//
+// 发言记录保存在 redis 中，多个 ws 节点共享；慢速模式的设置在本地缓存，群主、管理员通过群成员缓存判断，
+// 避免每条消息都查询数据库
+type SlowMode struct {
+	redis         *redis.Redis
+	conversations immodels.ConversationModel
+	members       *membership.Cache
+
+	intervals   *collection.Cache
+	cacheExpire time.Duration
+}
+
+// NewSlowMode cacheExpire 为 0 时使用默认的缓存时间
+func NewSlowMode(rds *redis.Redis, conversations immodels.ConversationModel, members *membership.Cache,
+	cacheExpire time.Duration) *SlowMode {
+	if cacheExpire <= 0 {
+		cacheExpire = defaultSlowModeCacheExpire
+	}
+	intervals, err := collection.NewCache(cacheExpire)
+	if err != nil {
+		panic(err)
+	}
+
+	return &SlowMode{
+		redis:         rds,
+		conversations: conversations,
+		members:       members,
+		intervals:     intervals,
+		cacheExpire:   cacheExpire,
+	}
+}
+
+// Allow 检查并记录用户在群中的一次发言
+//
+// 受限时返回 false 以及发言间隔与剩余的冷却时间（秒）；群主和管理员不受限制
+func (s *SlowMode) Allow(ctx context.Context, groupId, userId string) (bool, int64, int64, error) {
+	interval, err := s.interval(ctx, groupId)
+	if err != nil || interval <= 0 {
+		return true, 0, 0, err
+	}
+
+	role, err := s.members.Role(ctx, groupId, userId)
+	if err != nil {
+		return true, 0, 0, err
+	}
+	if role := constants.GroupRoleLevel(role); role == constants.CreatorGroupRoleLevel ||
+		role == constants.ManagerGroupRoleLevel {
+		return true, 0, 0, nil
+	}
+
+	key := slowModeKey(groupId, userId)
+	ok, err := s.redis.SetnxExCtx(ctx, key, "1", int(interval))
+	if err != nil || ok {
+		return true, interval, 0, err
+	}
+
+	remaining, err := s.redis.TtlCtx(ctx, key)
+	if err != nil {
+		return false, interval, interval, err
+	}
+	if remaining <= 0 {
+		// 记录恰好过期或没有过期时间，按剩余 1 秒处理
+		remaining = 1
+	}
+	return false, interval, int64(remaining), nil
+}
+
+// Cancel 撤销一次发言记录，消息未能进入队列时调用，让用户可以立即重发
+func (s *SlowMode) Cancel(ctx context.Context, groupId, userId string) {
+	_, _ = s.redis.DelCtx(ctx, slowModeKey(groupId, userId))
+}
+
+// interval 群聊的发言间隔（秒），0 表示未开启慢速模式
+func (s *SlowMode) interval(ctx context.Context, groupId string) (int64, error) {
+	v, err := s.intervals.Take(groupId, func() (any, error) {
+		conversation, err := s.conversations.FindOne(ctx, groupId)
+		switch {
+		case errors.Is(err, immodels.ErrNotFound):
+			return int64(0), nil
+		case err != nil:
+			return nil, err
+		case conversation.SlowMode == nil:
+			return int64(0), nil
+		default:
+			return conversation.SlowMode.Interval, nil
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.(int64), nil
+}
+
+func slowModeKey(groupId, userId string) string {
+	return fmt.Sprintf("%s%s:%s", constants.REDIS_SLOW_MODE, groupId, userId)
+}
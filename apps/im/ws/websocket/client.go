@@ -7,18 +7,24 @@ package websocket
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/gorilla/websocket"
 	"net/url"
+	"sync"
 )
 
 type Client interface {
 	Close() error
 	Send(v any) error
 	SendUid(v any, uids ...string) error
+	// SendBatch 按接收方所在的节点分组，每个节点发送一次，build 根据该节点的接收方生成消息
+	SendBatch(uids []string, build func(uids []string) any) error
 	Read(v any) error
 }
 
 type client struct {
+	// 同一连接不能并发写入
+	mu sync.Mutex
 	*websocket.Conn
 	host string
 
@@ -28,21 +34,30 @@ type client struct {
 }
 
 func NewClient(host string, opts ...DailOptions) *client {
+	c, err := dialClient(host, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func dialClient(host string, opts ...DailOptions) (*client, error) {
 	opt := newDailOptions(opts...)
 
-	c := client{
-		Conn: nil,
-		host: host,
-		opt:  opt,
+	c := &client{
+		Conn:     nil,
+		host:     host,
+		opt:      opt,
+		Discover: opt.Discover,
 	}
 
 	conn, err := c.dail()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	c.Conn = conn
-	return &c
+	return c, nil
 }
 
 func (c *client) dail() (*websocket.Conn, error) {
@@ -56,6 +71,10 @@ func (c *client) Send(v any) error {
 	if err != nil {
 		return err
 	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	err = c.WriteMessage(websocket.TextMessage, data)
 	if err == nil {
 		return nil
@@ -76,6 +95,35 @@ func (c *client) SendUid(v any, uids ...string) error {
 	return c.Send(v)
 }
 
+// SendBatch 存在服务发现时，按用户绑定的节点分组，直接发给各个节点，不在线的用户不发送；
+// 否则全部发给当前连接的节点
+func (c *client) SendBatch(uids []string, build func(uids []string) any) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	if c.Discover == nil {
+		return c.Send(build(uids))
+	}
+
+	nodes, err := c.Discover.Locate(uids...)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for srvAddr, ids := range nodes {
+		send := func(v any) error { return c.Discover.SendNode(srvAddr, v) }
+		if srvAddr == "" {
+			// 服务发现不区分节点，发给当前连接的节点
+			send = c.Send
+		}
+		if err := send(build(ids)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (c *client) Read(v any) error {
 	_, msg, err := c.Conn.ReadMessage()
 	if err != nil {
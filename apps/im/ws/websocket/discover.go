@@ -11,9 +11,14 @@ package websocket
 import (
 	"fmt"
 	"github.com/zeromicro/go-zero/core/stores/redis"
+	"imooc.com/easy-chat/pkg/fanout"
 	"net/http"
+	"sync"
 )
 
+// 按节点查找用户时每次 hmget 的用户数
+const locateBatchSize = 500
+
 // 服务发现机制【该方式是去中心化，自己在内部实现服务发现整套机制】
 // 该机制主要针对用户而设立
 //
@@ -28,6 +33,10 @@ type Discover interface {
 	RelieveUser(uid string) error
 	// 转发
 	Transpond(msg interface{}, uid ...string) error
+	// 按用户所在的节点分组，不在线的用户被忽略
+	Locate(uids ...string) (map[string][]string, error)
+	// 直接发送给节点
+	SendNode(srvAddr string, msg interface{}) error
 }
 
 // 默认的
@@ -46,6 +55,13 @@ func (d *nopDiscover) RelieveUser(uid string) error { return nil }
 // 转发消息
 func (d *nopDiscover) Transpond(msg interface{}, uid ...string) error { return nil }
 
+// 全部用户都在当前节点
+func (d *nopDiscover) Locate(uids ...string) (map[string][]string, error) {
+	return map[string][]string{"": uids}, nil
+}
+
+func (d *nopDiscover) SendNode(srvAddr string, msg interface{}) error { return nil }
+
 // 默认的
 type redisDiscover struct {
 	serverAddr string
@@ -62,7 +78,8 @@ type redisDiscover struct {
 	redis        *redis.Redis
 	//key：其他服务实例的地址（如 "192.168.1.101:8080"）
 	//value：指向该服务实例的客户端连接对象
-	clients map[string]Client
+	clientsMu sync.Mutex
+	clients   map[string]Client
 }
 
 func NewRedisDiscover(auth http.Header, srvKey string, redisCfg redis.RedisConf) *redisDiscover {
@@ -85,23 +102,23 @@ func (d *redisDiscover) Register(serverAddr string) (err error) {
 	return
 }
 
-// 绑定用户
+// 绑定用户，用户重新连接到其他节点时覆盖原有的绑定，推送按绑定关系找到节点
 func (d *redisDiscover) BoundUser(uid string) (err error) {
-	// 用户绑定
-	exists, err := d.redis.Hexists(d.boundUserKey, uid)
+	return d.redis.Hset(d.boundUserKey, uid, d.serverAddr)
+}
+
+// 解除绑定，用户已经连接到其他节点时保留新的绑定
+func (d *redisDiscover) RelieveUser(uid string) (err error) {
+	srvAddr, err := d.redis.Hget(d.boundUserKey, uid)
 	if err != nil {
+		if err == redis.Nil {
+			return nil
+		}
 		return err
 	}
-	if exists {
-		// 存在绑定关系
+	if srvAddr != d.serverAddr {
 		return nil
 	}
-
-	// 绑定
-	return d.redis.Hset(d.boundUserKey, uid, d.serverAddr)
-}
-
-func (d *redisDiscover) RelieveUser(uid string) (err error) {
 	_, err = d.redis.Hdel(d.boundUserKey, uid)
 	return
 }
@@ -114,13 +131,11 @@ func (d *redisDiscover) Transpond(msg interface{}, uids ...string) (err error) {
 		if err != nil {
 			return err
 		}
-		srvClient, ok := d.clients[srvAddr]
-		if !ok {
-			srvClient = d.createClient(srvAddr)
+		srvClient, err := d.client(srvAddr)
+		if err != nil {
+			return err
 		}
 
-		fmt.Println("redis transpand -》 ", srvAddr, " uid ", uid)
-
 		if err := d.send(srvClient, msg, uid); err != nil {
 			return err
 		}
@@ -129,6 +144,48 @@ func (d *redisDiscover) Transpond(msg interface{}, uids ...string) (err error) {
 	return
 }
 
+// Locate 批量查询用户绑定的节点，每 locateBatchSize 个用户一次 hmget
+func (d *redisDiscover) Locate(uids ...string) (map[string][]string, error) {
+	nodes := make([]string, 0, len(uids))
+	for start := 0; start < len(uids); start += locateBatchSize {
+		end := start + locateBatchSize
+		if end > len(uids) {
+			end = len(uids)
+		}
+		vals, err := d.redis.Hmget(d.boundUserKey, uids[start:end]...)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, vals...)
+	}
+	return fanout.Partition(uids, nodes), nil
+}
+
+// SendNode 通过节点的连接直接发送，由节点自己查找本地的用户连接
+func (d *redisDiscover) SendNode(srvAddr string, msg interface{}) error {
+	srvClient, err := d.client(srvAddr)
+	if err != nil {
+		return err
+	}
+	return srvClient.Send(msg)
+}
+
+// client 获取节点的连接，同一节点复用一个连接
+func (d *redisDiscover) client(srvAddr string) (Client, error) {
+	d.clientsMu.Lock()
+	defer d.clientsMu.Unlock()
+
+	if srvClient, ok := d.clients[srvAddr]; ok {
+		return srvClient, nil
+	}
+	srvClient, err := d.createClient(srvAddr)
+	if err != nil {
+		return nil, err
+	}
+	d.clients[srvAddr] = srvClient
+	return srvClient, nil
+}
+
 func (d *redisDiscover) send(srvClient Client, msg interface{}, uid string) error {
 	return srvClient.Send(Message{
 		FrameType:    FrameTranspond,
@@ -137,6 +194,6 @@ func (d *redisDiscover) send(srvClient Client, msg interface{}, uid string) erro
 	})
 }
 
-func (d *redisDiscover) createClient(srvAddr string) Client {
-	return NewClient(srvAddr, WithClientHeader(d.auth))
+func (d *redisDiscover) createClient(srvAddr string) (Client, error) {
+	return dialClient(srvAddr, WithClientHeader(d.auth))
 }
@@ -305,7 +305,6 @@ func (s *Server) GetConn(uid string) *Conn {
 	s.RWMutex.RLock()
 	defer s.RWMutex.RUnlock()
 
-	fmt.Println(s.userToConn)
 	return s.userToConn[uid]
 }
 
@@ -349,16 +348,27 @@ func (s *Server) GetUsers(conns ...*Conn) []string {
 
 func (s *Server) Close(conn *Conn) {
 	s.RWMutex.Lock()
-	defer s.RWMutex.Unlock()
 
 	uid := s.connToUser[conn]
 	if uid == "" {
 		// 已经被关闭
+		s.RWMutex.Unlock()
 		return
 	}
 
 	delete(s.connToUser, conn)
-	delete(s.userToConn, uid)
+	// 用户重新登录时 userToConn 已经指向新连接，只删除自己的记录
+	current := s.userToConn[uid] == conn
+	if current {
+		delete(s.userToConn, uid)
+	}
+	s.RWMutex.Unlock()
+
+	if current {
+		if err := s.discover.RelieveUser(uid); err != nil {
+			s.Errorf("discover relieve user err %v, uid %v", err, uid)
+		}
+	}
 
 	conn.Close()
 }
@@ -381,6 +391,11 @@ func (s *Server) Send(msg interface{}, conns ...*Conn) error {
 		return err
 	}
 
+	return s.SendBytes(data, conns...)
+}
+
+// SendBytes 发送已经序列化的消息，同一份内容发给多个连接时只需要序列化一次
+func (s *Server) SendBytes(data []byte, conns ...*Conn) error {
 	for _, conn := range conns {
 		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
 			return err
@@ -10,9 +10,57 @@ import "imooc.com/easy-chat/pkg/constants"
 type (
 	Msg struct {
 		MsgId           string            `mapstructure:"msgId"`
+		Seq             int64             `mapstructure:"seq"`
 		ReadRecords     map[string]string `mapstructure:"readRecords"`
 		constants.MType `mapstructure:"mType"`
-		Content         string `mapstructure:"content"`
+		Content         string                `mapstructure:"content"`
+		ContentType     constants.ContentType `mapstructure:"contentType"`
+		IsEdited        bool                  `mapstructure:"isEdited"`
+		Quote           *Quote                `mapstructure:"quote"`
+		// DeliveredRecords 送达回执，msgId -> 送达记录，格式与 ReadRecords 相同
+		DeliveredRecords map[string]string `mapstructure:"deliveredRecords"`
+		// ReadCursors 已读游标，userId -> 已读到的消息序号
+		ReadCursors map[string]int64 `mapstructure:"readCursors"`
+		// MergeForward 合并转发的卡片，MType 为 MergeForwardMType 时有值
+		MergeForward *MergeForward `mapstructure:"mergeForward"`
+		// ExpireAt 阅后即焚的过期时间（毫秒），DisappearTtl 为首次阅读后计时的存活时长（秒）
+		ExpireAt     int64 `mapstructure:"expireAt"`
+		DisappearTtl int64 `mapstructure:"disappearTtl"`
+		// Mentions 被@的用户，@全体成员使用 constants.MentionAll
+		Mentions []string `mapstructure:"mentions"`
+		// Mentioned 接收者是否被@，被@的消息不受会话免打扰限制
+		Mentioned bool `mapstructure:"mentioned"`
+		// Reactions 表情回应聚合，msgId -> emoji -> 人数
+		Reactions map[string]map[string]int `mapstructure:"reactions"`
+		// LinkPreview 消息中链接的预览，ContentType 为 ContentLinkPreview 时有值
+		LinkPreview *LinkPreview `mapstructure:"linkPreview"`
+		// MsgFrom 消息来源，机器人发送的消息为 constants.BotMsgFrom
+		MsgFrom constants.MsgFrom `mapstructure:"msgFrom"`
+	}
+
+	// Quote 引用的消息，发送时只需携带 msgId，其余字段由服务端补全
+	Quote struct {
+		MsgId           string `mapstructure:"msgId"`
+		SendId          string `mapstructure:"sendId"`
+		constants.MType `mapstructure:"mType"`
+		Snippet         string `mapstructure:"snippet"`
+	}
+
+	// MergeForward 合并转发卡片，完整记录通过 api 展开
+	MergeForward struct {
+		ConversationId string   `mapstructure:"conversationId"`
+		Title          string   `mapstructure:"title"`
+		Summary        []string `mapstructure:"summary"`
+		Count          int      `mapstructure:"count"`
+	}
+
+	// LinkPreview 链接预览
+	LinkPreview struct {
+		Url         string `mapstructure:"url"`
+		Title       string `mapstructure:"title"`
+		Description string `mapstructure:"description"`
+		Image       string `mapstructure:"image"`
+		SiteName    string `mapstructure:"siteName"`
 	}
 
 	Chat struct {
@@ -37,10 +85,28 @@ type (
 		SendTime int64    `mapstructure:"sendTime"`
 
 		MsgId       string                `mapstructure:"msgId"`
+		Seq         int64                 `mapstructure:"seq"`
 		ReadRecords map[string]string     `mapstructure:"readRecords"`
 		ContentType constants.ContentType `mapstructure:"contentType"`
 
-		Content string `mapstructure:"content"`
+		DeliveredRecords map[string]string `mapstructure:"deliveredRecords"`
+		ReadCursors      map[string]int64  `mapstructure:"readCursors"`
+
+		Content  string   `mapstructure:"content"`
+		IsEdited bool     `mapstructure:"isEdited"`
+		Quote    *Quote   `mapstructure:"quote"`
+		Mentions []string `mapstructure:"mentions"`
+
+		MergeForward *MergeForward `mapstructure:"mergeForward"`
+
+		ExpireAt     int64 `mapstructure:"expireAt"`
+		DisappearTtl int64 `mapstructure:"disappearTtl"`
+
+		Reactions map[string]map[string]int `mapstructure:"reactions"`
+
+		LinkPreview *LinkPreview `mapstructure:"linkPreview"`
+
+		MsgFrom constants.MsgFrom `mapstructure:"msgFrom"`
 	}
 
 	MarkRead struct {
@@ -48,10 +114,60 @@ type (
 		RecvId             string   `mapstructure:"recvId"`
 		ConversationId     string   `mapstructure:"conversationId"`
 		MsgIds             []string `mapstructure:"msgIds"`
+		// ReadSeq 已读到的消息序号，序号不大于它的消息都标记为已读
+		ReadSeq int64 `mapstructure:"readSeq"`
 	}
 
 	Revoke struct {
 		MsgId          string `mapstructure:"msgId"`
 		ConversationId string `mapstructure:"conversationId"`
 	}
+
+	Edit struct {
+		MsgId          string `mapstructure:"msgId"`
+		ConversationId string `mapstructure:"conversationId"`
+		Content        string `mapstructure:"content"`
+	}
+
+	// CardAction 点击卡片上的按钮
+	CardAction struct {
+		MsgId          string `mapstructure:"msgId"`
+		ConversationId string `mapstructure:"conversationId"`
+		ButtonId       string `mapstructure:"buttonId"`
+	}
+
+	Reaction struct {
+		MsgId          string `mapstructure:"msgId"`
+		ConversationId string `mapstructure:"conversationId"`
+		Emoji          string `mapstructure:"emoji"`
+	}
+
+	// Forward 转发消息，Merge 为 true 时合并为一条聊天记录卡片
+	Forward struct {
+		ConversationId string          `mapstructure:"conversationId"`
+		MsgIds         []string        `mapstructure:"msgIds"`
+		Merge          bool            `mapstructure:"merge"`
+		Title          string          `mapstructure:"title"`
+		Targets        []ForwardTarget `mapstructure:"targets"`
+	}
+
+	ForwardTarget struct {
+		constants.ChatType `mapstructure:"chatType"`
+		RecvId             string `mapstructure:"recvId"`
+	}
+
+	Pin struct {
+		MsgId          string `mapstructure:"msgId"`
+		ConversationId string `mapstructure:"conversationId"`
+	}
+
+	// SlowModeErr 慢速模式下发言过快时返回的错误帧数据
+	SlowModeErr struct {
+		Code           string `mapstructure:"code" json:"code"`
+		Msg            string `mapstructure:"msg" json:"msg"`
+		ConversationId string `mapstructure:"conversationId" json:"conversationId"`
+		// Interval 发言间隔（秒），Remaining 剩余的冷却时间（秒）
+		Interval  int64 `mapstructure:"interval" json:"interval"`
+		Remaining int64 `mapstructure:"remaining" json:"remaining"`
+	}
 )
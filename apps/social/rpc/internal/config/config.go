@@ -2,6 +2,7 @@ package config
 
 import (
 	"github.com/zeromicro/go-zero/core/stores/cache"
+	"github.com/zeromicro/go-zero/core/stores/redis"
 	"github.com/zeromicro/go-zero/zrpc"
 )
 
@@ -14,6 +15,9 @@ type Config struct {
 
 	Cache cache.CacheConf
 
+	// Redisx 发布群成员变化的通知，与其他服务共用
+	Redisx redis.RedisConf
+
 	// RPC客户端配置
 	UserRpc zrpc.RpcClientConf
 }
@@ -0,0 +1,17 @@
+package logic
+
+import (
+	"context"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"imooc.com/easy-chat/apps/social/rpc/internal/svc"
+	"imooc.com/easy-chat/pkg/membership"
+)
+
+// groupMembersChanged 发布群成员变化的通知，使其他服务缓存的成员列表失效
+// 失败时只记录日志，缓存最迟在过期后刷新
+func groupMembersChanged(ctx context.Context, svcCtx *svc.ServiceContext, groupId string) {
+	if err := membership.Publish(ctx, svcCtx.Redis, groupId); err != nil {
+		logx.WithContext(ctx).Errorf("publish group members changed err %v, groupId %v", err, groupId)
+	}
+}
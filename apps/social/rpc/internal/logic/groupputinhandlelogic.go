@@ -80,6 +80,9 @@ func (l *GroupPutInHandleLogic) GroupPutInHandle(in *social.GroupPutInHandleReq)
 	if constants.HandlerResult(groupReq.HandleResult.Int64) != constants.PassHandlerResult {
 		return &social.GroupPutInHandleResp{}, err
 	}
+	if err == nil {
+		groupMembersChanged(l.ctx, l.svcCtx, groupReq.GroupId)
+	}
 
 	return &social.GroupPutInHandleResp{
 		GroupId: groupReq.GroupId,
@@ -168,6 +168,7 @@ func (l *GroupPutinLogic) createGroupMember(in *social.GroupPutinReq) error {
 	if err != nil {
 		return errors.Wrapf(xerr.NewDBErr(), "insert friend err %v req %v", err, groupMember)
 	}
+	groupMembersChanged(l.ctx, l.svcCtx, in.GroupId)
 
 	return nil
 }
@@ -1,6 +1,7 @@
 package svc
 
 import (
+	"github.com/zeromicro/go-zero/core/stores/redis"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 	"github.com/zeromicro/go-zero/zrpc"
 	"imooc.com/easy-chat/apps/social/rpc/internal/config"
@@ -10,6 +11,7 @@ import (
 
 type ServiceContext struct {
 	Config config.Config
+	*redis.Redis
 
 	socialmodels.FriendsModel
 	socialmodels.FriendRequestsModel
@@ -27,6 +29,7 @@ func NewServiceContext(c config.Config) *ServiceContext {
 
 	return &ServiceContext{
 		Config: c,
+		Redis:  redis.MustNewRedis(c.Redisx),
 
 		FriendsModel:        socialmodels.NewFriendsModel(sqlConn, c.Cache),
 		FriendRequestsModel: socialmodels.NewFriendRequestsModel(sqlConn, c.Cache),
@@ -0,0 +1,28 @@
+package socialclient
+
+import (
+	"context"
+
+	"imooc.com/easy-chat/pkg/membership"
+)
+
+// NewMembershipLoader 群成员缓存未命中时通过 GroupUsers 查询
+func NewMembershipLoader(social Social) membership.Loader {
+	return func(ctx context.Context, groupId string) ([]*membership.Member, error) {
+		users, err := social.GroupUsers(ctx, &GroupUsersReq{
+			GroupId: groupId,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		members := make([]*membership.Member, 0, len(users.List))
+		for _, user := range users.List {
+			members = append(members, &membership.Member{
+				UserId:    user.UserId,
+				RoleLevel: int(user.RoleLevel),
+			})
+		}
+		return members, nil
+	}
+}
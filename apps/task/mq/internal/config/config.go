@@ -10,15 +10,27 @@ import (
 	"github.com/zeromicro/go-zero/core/service"
 	"github.com/zeromicro/go-zero/core/stores/redis"
 	"github.com/zeromicro/go-zero/zrpc"
+	"imooc.com/easy-chat/pkg/membership"
+	"imooc.com/easy-chat/pkg/moderation"
+	"imooc.com/easy-chat/pkg/mqx"
 )
 
 type Config struct {
 	service.ServiceConf
 	ListenOn string
 
+	// Queue 消息队列的后端，kafka、redis 或进程内的 memory，各个服务需要一致
+	Queue mqx.Conf `json:",optional"`
+
 	MsgChatTransfer   kq.KqConf
 	MsgReadTransfer   kq.KqConf
 	MsgRevokeTransfer kq.KqConf
+	MsgEditTransfer   kq.KqConf
+
+	MsgReactionTransfer kq.KqConf
+	MsgPinTransfer      kq.KqConf
+
+	MsgCardActionTransfer kq.KqConf
 
 	Redisx redis.RedisConf
 	Mongo  struct {
@@ -32,7 +44,144 @@ type Config struct {
 		GroupMsgReadRecordDelayCount int
 	}
 
+	// MsgRevokeHandler 默认的撤回策略，群聊可以在会话中单独设置
+	MsgRevokeHandler struct {
+		// 发送者撤回自己消息的时间窗口，单位秒
+		SenderWindow int64 `json:",default=120"`
+		// 群主和管理员是否可以撤回他人的消息
+		ManagerRevoke bool `json:",default=true"`
+		// 群主和管理员撤回他人消息的时间窗口，单位秒
+		ManagerWindow int64 `json:",default=120"`
+		// 群主撤回消息是否不受时间限制
+		CreatorUnlimited bool `json:",optional"`
+	}
+
+	MsgEditHandler struct {
+		// 允许编辑的时间窗口，单位秒
+		EditTimeWindow int64 `json:",optional"`
+	}
+
+	MsgReactionHandler struct {
+		// 群聊回应是否合并推送，取值与 GroupMsgReadHandler 一致，合并的时间与数量复用已读的配置
+		GroupMsgReactionHandler int `json:",optional"`
+		// 每个用户对同一条消息最多可使用的表情数
+		MaxReactionsPerUser int `json:",optional"`
+	}
+
+	MsgPinHandler struct {
+		// 每个会话最多置顶的消息数
+		MaxPins int `json:",optional"`
+	}
+
+	ScheduledMsg struct {
+		// 扫描到期消息的间隔，单位秒
+		Interval int64 `json:",optional"`
+		// 领取后的租期，超过租期未完成可被其他实例重新领取，单位秒
+		Lease int64 `json:",optional"`
+		// 每次扫描最多发送的消息数
+		BatchSize int `json:",optional"`
+		// 投递失败的最大次数，超过后标记为失败
+		MaxRetry int `json:",optional"`
+	}
+
+	// ConsumerRetry 消费失败时的重试，重试后仍然失败的消息发送到死信 topic（原 topic 加 -dlq 后缀）
+	ConsumerRetry struct {
+		// 最多处理的次数，包括第一次
+		Nums int `json:",optional"`
+		// 第一次重试前的等待时间，之后每次翻倍，单位毫秒
+		JetLag int64 `json:",optional"`
+		// 重试等待时间的上限，单位毫秒
+		MaxJetLag int64 `json:",optional"`
+		// 包括重试在内处理一条消息的最长时间，单位秒
+		Timeout int64 `json:",optional"`
+		// 消息重试后仍然失败时，同一个 key 暂停重试的时间，单位秒
+		ParkTime int64 `json:",optional"`
+		// 暂停重试期间同一个 key 的消息只处理一次，单次处理的最长时间，单位毫秒
+		ParkedTimeout int64 `json:",optional"`
+		// 扫描待重放死信的间隔，单位秒
+		ReplayInterval int64 `json:",optional"`
+	}
+
+	MsgExpireHandler struct {
+		// 扫描过期消息的间隔，单位秒
+		Interval int64 `json:",optional"`
+		// 每次扫描最多删除的消息数
+		BatchSize int `json:",optional"`
+	}
+
+	MsgExport struct {
+		// 归档保存的目录，im api 从 Export.Dir 读取下载，两者必须是同一个目录：
+		// 部署在同一台机器，或挂载同一个共享存储（如 NFS）
+		Dir string `json:",default=./data/export"`
+		// 扫描导出任务的间隔，单位秒
+		Interval int64 `json:",optional"`
+		// 领取后的租期，超过租期未完成可被其他实例重新领取，单位秒
+		Lease int64 `json:",optional"`
+		// 导出失败的最大次数，超过后标记为失败
+		MaxRetry int `json:",optional"`
+		// 归档的保留时长，到期后删除，单位秒
+		Retention int64 `json:",optional"`
+		// 单次导出的最大消息数
+		MaxMessages int `json:",optional"`
+	}
+
+	// Moderation 敏感词过滤，词库优先从配置中心加载并热更新，未配置或加载失败时使用本地的 Rules
+	Moderation struct {
+		Addr      string            `json:",optional"`
+		Namespace string            `json:",optional"`
+		Group     string            `json:",optional"`
+		DataId    string            `json:",optional"`
+		Rules     []moderation.Rule `json:",optional"`
+	}
+
+	Announcement struct {
+		// 扫描待发送公告的间隔，单位秒
+		Interval int64 `json:",optional"`
+		// 领取后的租期，超过租期没有汇报进度可被其他实例领取，单位秒
+		Lease int64 `json:",optional"`
+		// 每批投递的接收者数量
+		BatchSize int `json:",optional"`
+		// 两批之间的间隔，单位毫秒
+		BatchInterval int64 `json:",optional"`
+		// 发送失败的最大次数，超过后标记为失败
+		MaxRetry int `json:",optional"`
+	}
+
+	// LinkPreview 链接预览
+	LinkPreview struct {
+		// 并发抓取的数量
+		Workers int `json:",optional"`
+		// 等待抓取的队列长度，队列满时丢弃
+		QueueSize int `json:",optional"`
+		// 单次抓取的超时时间，单位秒
+		Timeout int64 `json:",optional"`
+		// 最多读取的页面字节数
+		MaxBytes int64 `json:",optional"`
+		// 抓取结果的缓存时长，单位秒
+		CacheTtl int64 `json:",optional"`
+		// 抓取失败的缓存时长，单位秒
+		FailTtl int64 `json:",optional"`
+	}
+
+	// BotWebhook 机器人的 outgoing webhook
+	BotWebhook struct {
+		// 并发投递的数量
+		Workers int `json:",optional"`
+		// 等待投递的队列长度，队列满时丢弃
+		QueueSize int `json:",optional"`
+		// 单次请求的超时时间，单位秒
+		Timeout int64 `json:",optional"`
+		// 投递失败后最多重试的次数
+		MaxRetry int `json:",optional"`
+		// 是否允许投递到内网地址
+		AllowPrivate bool `json:",optional"`
+	}
+
+	// GroupMembers 群成员列表的缓存，成员变化时由社交服务发布通知使缓存失效
+	GroupMembers membership.Conf `json:",optional"`
+
 	SocialRpc zrpc.RpcClientConf
+	UserRpc   zrpc.RpcClientConf
 
 	Ws struct {
 		Host string
@@ -0,0 +1,228 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zeromicro/go-queue/kq"
+	"github.com/zeromicro/go-zero/core/logx"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/pkg/job"
+	"imooc.com/easy-chat/pkg/mqx"
+)
+
+const (
+	defaultRetryNums      = 3
+	defaultRetryJetLag    = 200 * time.Millisecond
+	defaultRetryMaxJetLag = 2 * time.Second
+	defaultRetryTimeout   = 30 * time.Second
+	defaultParkTime       = time.Minute
+	defaultParkedTimeout  = 2 * time.Second
+)
+
+// Preparer 消费者可选实现，在第一次处理前补全消息，重试与重放都使用补全后的消息
+//
+// 用于让重复处理保持幂等，例如预先分配聊天记录id，重试时不会重复入库
+type Preparer interface {
+	Prepare(value string) (string, error)
+}
+
+// ContextHandler 消费者可选实现，处理超时后 ctx 被取消，消费者应尽快返回
+type ContextHandler interface {
+	ConsumeContext(ctx context.Context, key, value string) error
+}
+
+// Handler 为消费者增加重试，临时性的错误按指数退避重试，job.Permanent 标记的错误与重试后仍然失败的消息
+// 发送到死信 topic，同时记录到 DeadLetterModel 供管理员查看与重放
+//
+// 处理超时时取消本次处理的 ctx，等待处理返回后才进入死信，死信与重放不会和仍在进行的处理并发；
+// 没有实现 ContextHandler 的消费者无法被取消，超时后会一直等到处理结束
+//
+// 消费者按 key 串行处理，同一协程上的其他 key 要等当前消息处理完成。为了限制一条无法处理的消息阻塞其他会话的时间，
+// 消息重试后仍然失败时暂停这个 key 的重试：暂停期间同一个 key 的消息只处理一次且使用更短的超时，
+// 失败直接进入死信，成功后恢复正常重试
+type Handler struct {
+	svcCtx *svc.ServiceContext
+	logx.Logger
+
+	topic    string
+	handler  mqx.ConsumeHandler
+	producer mqx.Producer
+
+	retryNums      int
+	retryJetLag    time.Duration
+	retryMaxJetLag time.Duration
+	retryTimeout   time.Duration
+
+	parkTime      time.Duration
+	parkedTimeout time.Duration
+	mu            sync.Mutex
+	parked        map[string]time.Time // key 暂停重试的截止时间
+}
+
+func NewHandler(svc *svc.ServiceContext, c kq.KqConf, handler mqx.ConsumeHandler) *Handler {
+	h := &Handler{
+		svcCtx:         svc,
+		Logger:         logx.WithContext(context.Background()),
+		topic:          c.Topic,
+		handler:        handler,
+		producer:       mqx.NewProducer(svc.Config.Queue, c.Brokers, mq.DeadLetterTopic(c.Topic)),
+		retryNums:      defaultRetryNums,
+		retryJetLag:    defaultRetryJetLag,
+		retryMaxJetLag: defaultRetryMaxJetLag,
+		retryTimeout:   defaultRetryTimeout,
+		parkTime:       defaultParkTime,
+		parkedTimeout:  defaultParkedTimeout,
+		parked:         make(map[string]time.Time),
+	}
+
+	r := svc.Config.ConsumerRetry
+	if r.Nums > 0 {
+		h.retryNums = r.Nums
+	}
+	if r.JetLag > 0 {
+		h.retryJetLag = time.Duration(r.JetLag) * time.Millisecond
+	}
+	if r.MaxJetLag > 0 {
+		h.retryMaxJetLag = time.Duration(r.MaxJetLag) * time.Millisecond
+	}
+	if r.Timeout > 0 {
+		h.retryTimeout = time.Duration(r.Timeout) * time.Second
+	}
+	if r.ParkTime > 0 {
+		h.parkTime = time.Duration(r.ParkTime) * time.Second
+	}
+	if r.ParkedTimeout > 0 {
+		h.parkedTimeout = time.Duration(r.ParkedTimeout) * time.Millisecond
+	}
+	return h
+}
+
+func (h *Handler) Consume(key, value string) error {
+	if p, ok := h.handler.(Preparer); ok {
+		prepared, err := p.Prepare(value)
+		if err != nil {
+			h.deadLetter(key, value, job.Permanent(err), 0)
+			return nil
+		}
+		value = prepared
+	}
+
+	retryNums, retryTimeout := h.retryNums, h.retryTimeout
+	parked := h.isParked(key)
+	if parked {
+		retryNums, retryTimeout = 1, h.parkedTimeout
+	}
+
+	var attempts int32
+	err := job.WithRetry(context.Background(), func(ctx context.Context) (err error) {
+		atomic.AddInt32(&attempts, 1)
+		// 在 WithRetry 的协程中处理，panic 需要在这里恢复，按无法重试的错误处理
+		defer func() {
+			if r := recover(); r != nil {
+				err = job.Permanent(fmt.Errorf("panic: %v", r))
+			}
+		}()
+		if c, ok := h.handler.(ContextHandler); ok {
+			return c.ConsumeContext(ctx, key, value)
+		}
+		return h.handler.Consume(key, value)
+	}, job.WithRetryNums(retryNums),
+		job.WithRetryTimeout(retryTimeout),
+		job.WithIsRetryFunc(job.RetryTransient),
+		job.WithRetryJetLagFunc(job.RetryJetLagExponential(h.retryJetLag, h.retryMaxJetLag)))
+	switch {
+	case err == nil:
+		if parked {
+			h.unpark(key)
+		}
+	case job.IsPermanent(err):
+		h.deadLetter(key, value, err, int(atomic.LoadInt32(&attempts)))
+	default:
+		h.park(key)
+		h.deadLetter(key, value, err, int(atomic.LoadInt32(&attempts)))
+	}
+	return nil
+}
+
+// isParked key 是否处于暂停重试期间，没有 key 的消息不会暂停
+func (h *Handler) isParked(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	until, ok := h.parked[key]
+	if ok && time.Now().After(until) {
+		delete(h.parked, key)
+		return false
+	}
+	return ok
+}
+
+func (h *Handler) park(key string) {
+	if key == "" {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	// 顺便清理已经过期的 key
+	for k, until := range h.parked {
+		if now.After(until) {
+			delete(h.parked, k)
+		}
+	}
+	h.parked[key] = now.Add(h.parkTime)
+	h.Infof("park key %v of topic %v until %v", key, h.topic, h.parked[key])
+}
+
+func (h *Handler) unpark(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.parked, key)
+}
+
+// deadLetter 发送到死信 topic 并记录，两者都失败时消息只保留在日志中
+func (h *Handler) deadLetter(key, value string, err error, attempts int) {
+	letter := &mq.DeadLetter{
+		Topic:     h.topic,
+		Key:       key,
+		Value:     value,
+		Error:     err.Error(),
+		Attempts:  attempts,
+		Permanent: job.IsPermanent(err),
+		FailTime:  time.Now().UnixMilli(),
+	}
+	h.Errorf("dead letter topic %v, attempts %v, permanent %v, err %v, value %v", letter.Topic, letter.Attempts,
+		letter.Permanent, err, value)
+
+	body, err := json.Marshal(letter)
+	if err == nil {
+		err = h.producer.Push(key, string(body))
+	}
+	if err != nil {
+		h.Errorf("push dead letter err %v, topic %v", err, h.topic)
+	}
+
+	err = h.svcCtx.DeadLetterModel.Insert(context.Background(), &immodels.DeadLetter{
+		Topic:     letter.Topic,
+		Key:       letter.Key,
+		Value:     letter.Value,
+		Error:     letter.Error,
+		Attempts:  letter.Attempts,
+		Permanent: letter.Permanent,
+		FailTime:  letter.FailTime,
+	})
+	if err != nil {
+		h.Errorf("insert dead letter err %v, topic %v", err, h.topic)
+	}
+}
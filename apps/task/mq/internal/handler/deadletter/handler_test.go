@@ -0,0 +1,86 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/zeromicro/go-queue/kq"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/internal/config"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/pkg/mqx"
+)
+
+type memDeadLetterModel struct {
+	immodels.DeadLetterModel
+
+	mu      sync.Mutex
+	letters []*immodels.DeadLetter
+}
+
+func (m *memDeadLetterModel) Insert(ctx context.Context, data *immodels.DeadLetter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.letters = append(m.letters, data)
+	return nil
+}
+
+// failingHandler 记录每个 key 的处理次数，fail 中的 key 处理失败
+type failingHandler struct {
+	attempts map[string]int
+	fail     map[string]bool
+}
+
+func (f *failingHandler) Consume(key, value string) error {
+	f.attempts[key]++
+	if f.fail[key] {
+		return errors.New("transient")
+	}
+	return nil
+}
+
+func TestHandler_ParkFailingKey(t *testing.T) {
+	var c config.Config
+	c.Queue = mqx.Conf{Type: mqx.MemoryType}
+	c.ConsumerRetry.Nums = 3
+	c.ConsumerRetry.JetLag = 1
+	c.ConsumerRetry.MaxJetLag = 1
+
+	letters := &memDeadLetterModel{}
+	f := &failingHandler{attempts: make(map[string]int), fail: map[string]bool{"a": true, "b": true}}
+	h := NewHandler(&svc.ServiceContext{Config: c, DeadLetterModel: letters}, kq.KqConf{Topic: "park"}, f)
+
+	consume := func(key string) int {
+		f.attempts[key] = 0
+		if err := h.Consume(key, "{}"); err != nil {
+			t.Fatalf("Consume(%v) err = %v", key, err)
+		}
+		return f.attempts[key]
+	}
+
+	if n := consume("a"); n != 3 {
+		t.Errorf("first failure attempts = %d, want 3", n)
+	}
+	// 暂停期间同一个 key 只处理一次，其他 key 不受影响
+	if n := consume("a"); n != 1 {
+		t.Errorf("parked key attempts = %d, want 1", n)
+	}
+	if n := consume("b"); n != 3 {
+		t.Errorf("other key attempts = %d, want 3", n)
+	}
+	if len(letters.letters) != 3 {
+		t.Errorf("dead letters = %d, want 3", len(letters.letters))
+	}
+
+	// 处理成功后恢复重试
+	f.fail["a"] = false
+	if n := consume("a"); n != 1 {
+		t.Errorf("recovered attempts = %d, want 1", n)
+	}
+	f.fail["a"] = true
+	if n := consume("a"); n != 3 {
+		t.Errorf("attempts after unpark = %d, want 3", n)
+	}
+}
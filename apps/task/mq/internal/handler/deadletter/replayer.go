@@ -0,0 +1,112 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-queue/kq"
+	"github.com/zeromicro/go-zero/core/logx"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/pkg/job"
+	"imooc.com/easy-chat/pkg/mqx"
+)
+
+const (
+	defaultReplayInterval = 5 * time.Second
+	replayBatchSize       = 100
+)
+
+// Replayer 把管理员请求重放的死信以原来的 key 重新投递到原 topic，由原消费者再次处理
+type Replayer struct {
+	svcCtx *svc.ServiceContext
+	logx.Logger
+
+	producers map[string]mqx.Producer
+	interval  time.Duration
+
+	once sync.Once
+	done chan struct{}
+}
+
+// NewReplayer confs 为可以重放的 topic，与 NewHandler 包装的消费者一致
+func NewReplayer(svc *svc.ServiceContext, confs ...kq.KqConf) *Replayer {
+	interval := defaultReplayInterval
+	if svc.Config.ConsumerRetry.ReplayInterval > 0 {
+		interval = time.Duration(svc.Config.ConsumerRetry.ReplayInterval) * time.Second
+	}
+
+	producers := make(map[string]mqx.Producer, len(confs))
+	for _, c := range confs {
+		producers[c.Topic] = mqx.NewProducer(svc.Config.Queue, c.Brokers, c.Topic)
+	}
+
+	return &Replayer{
+		svcCtx:    svc,
+		Logger:    logx.WithContext(context.Background()),
+		producers: producers,
+		interval:  interval,
+		done:      make(chan struct{}),
+	}
+}
+
+func (r *Replayer) Start() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+			r.replay(context.Background())
+		}
+	}
+}
+
+func (r *Replayer) Stop() {
+	r.once.Do(func() {
+		close(r.done)
+	})
+}
+
+// replay 领取并投递等待重放的死信
+func (r *Replayer) replay(ctx context.Context) {
+	for i := 0; i < replayBatchSize; i++ {
+		select {
+		case <-r.done:
+			return
+		default:
+		}
+
+		letter, err := r.svcCtx.DeadLetterModel.TakeReplay(ctx)
+		if err != nil {
+			if !errors.Is(err, immodels.ErrNotFound) {
+				r.Errorf("Replayer TakeReplay err %v", err)
+			}
+			return
+		}
+
+		if err := r.push(ctx, letter); err != nil {
+			r.Errorf("Replayer push err %v, id %v, topic %v", err, letter.ID.Hex(), letter.Topic)
+			if err := r.svcCtx.DeadLetterModel.ReplayFailed(ctx, letter.ID, err.Error()); err != nil {
+				r.Errorf("Replayer ReplayFailed err %v, id %v", err, letter.ID.Hex())
+			}
+			continue
+		}
+		r.Infof("Replayer replayed %v to %v", letter.ID.Hex(), letter.Topic)
+	}
+}
+
+func (r *Replayer) push(ctx context.Context, letter *immodels.DeadLetter) error {
+	producer, ok := r.producers[letter.Topic]
+	if !ok {
+		return errors.New("topic 不支持重放")
+	}
+
+	return job.WithRetry(ctx, func(ctx context.Context) error {
+		return producer.Push(letter.Key, letter.Value)
+	}, job.WithRetryNums(3))
+}
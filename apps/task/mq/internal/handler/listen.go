@@ -6,10 +6,12 @@
 package handler
 
 import (
-	"github.com/zeromicro/go-queue/kq"
 	"github.com/zeromicro/go-zero/core/service"
+	"imooc.com/easy-chat/apps/task/mq/internal/handler/deadletter"
 	"imooc.com/easy-chat/apps/task/mq/internal/handler/msgTransfer"
+	"imooc.com/easy-chat/apps/task/mq/internal/handler/scheduler"
 	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/pkg/mqx"
 )
 
 type Listen struct {
@@ -21,10 +23,30 @@ func NewListen(svc *svc.ServiceContext) *Listen {
 }
 
 func (l *Listen) Services() []service.Service {
+	unfurler := msgTransfer.NewLinkUnfurler(l.svc)
+	bots := msgTransfer.NewBotDispatcher(l.svc)
+	commands := msgTransfer.NewCommands(l.svc)
+
+	c := l.svc.Config
 	return []service.Service{
-		kq.MustNewQueue(l.svc.Config.MsgReadTransfer, msgTransfer.NewMsgReadTransfer(l.svc)),
+		// 同一会话的消息按顺序处理；消费失败时重试，仍然失败的消息进入死信
+		mqx.MustNewConsumer(c.Queue, c.MsgReadTransfer, deadletter.NewHandler(l.svc, c.MsgReadTransfer,
+			msgTransfer.NewMsgReadTransfer(l.svc))),
 		// todo: 此处可以加载多个消费者
-		kq.MustNewQueue(l.svc.Config.MsgChatTransfer, msgTransfer.NewMsgChatTransfer(l.svc)),
-		kq.MustNewQueue(l.svc.Config.MsgRevokeTransfer, msgTransfer.NewMsgRevokeTransfer(l.svc)),
+		mqx.MustNewConsumer(c.Queue, c.MsgChatTransfer, deadletter.NewHandler(l.svc, c.MsgChatTransfer,
+			msgTransfer.NewMsgChatTransfer(l.svc, unfurler, bots, commands))),
+		mqx.MustNewConsumer(c.Queue, c.MsgRevokeTransfer, deadletter.NewHandler(l.svc, c.MsgRevokeTransfer,
+			msgTransfer.NewMsgRevokeTransfer(l.svc))),
+		mqx.MustNewConsumer(c.Queue, c.MsgEditTransfer, msgTransfer.NewMsgEditTransfer(l.svc, unfurler)),
+		mqx.MustNewConsumer(c.Queue, c.MsgReactionTransfer, msgTransfer.NewMsgReactionTransfer(l.svc)),
+		mqx.MustNewConsumer(c.Queue, c.MsgPinTransfer, msgTransfer.NewMsgPinTransfer(l.svc)),
+		mqx.MustNewConsumer(c.Queue, c.MsgCardActionTransfer, msgTransfer.NewMsgCardActionTransfer(l.svc, commands)),
+		scheduler.NewScheduledMsg(l.svc),
+		scheduler.NewAnnouncementSender(l.svc),
+		msgTransfer.NewMsgExpirePurger(l.svc),
+		msgTransfer.NewMsgExporter(l.svc),
+		unfurler,
+		bots,
+		deadletter.NewReplayer(l.svc, c.MsgReadTransfer, c.MsgChatTransfer, c.MsgRevokeTransfer),
 	}
 }
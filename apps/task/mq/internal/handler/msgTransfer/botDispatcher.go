@@ -0,0 +1,222 @@
+package msgTransfer
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/webhook"
+)
+
+const (
+	defaultBotWebhookWorkers   = 8
+	defaultBotWebhookQueueSize = 1000
+)
+
+// BotEvent 投递给机器人 webhook 的请求体
+type BotEvent struct {
+	Event          string            `json:"event"`
+	BotId          string            `json:"botId"`
+	GroupId        string            `json:"groupId"`
+	ConversationId string            `json:"conversationId"`
+	MsgId          string            `json:"msgId"`
+	Seq            int64             `json:"seq,omitempty"`
+	SendId         string            `json:"sendId"`
+	SendTime       int64             `json:"sendTime"`
+	MsgType        constants.MType   `json:"msgType"`
+	MsgFrom        constants.MsgFrom `json:"msgFrom"`
+	Content        string            `json:"content"`
+	Mentions       []string          `json:"mentions,omitempty"`
+	Command        string            `json:"command,omitempty"` // 命令名，不含 /
+	Args           string            `json:"args,omitempty"`    // 命令名之后的内容
+}
+
+// BotDispatcher 把群消息投递给群中订阅了消息或命令的机器人
+//
+// 投递在后台完成，失败时按指数退避重试；队列满时直接丢弃，不影响消息的投递。
+// 机器人发送的消息不再投递给机器人，避免机器人之间互相触发
+type BotDispatcher struct {
+	*baseMsgTransfer
+
+	sender  *webhook.Sender
+	tasks   chan *immodels.ChatLog
+	workers int
+
+	once sync.Once
+	done chan struct{}
+}
+
+func NewBotDispatcher(svc *svc.ServiceContext) *BotDispatcher {
+	c := svc.Config.BotWebhook
+	workers, queueSize := defaultBotWebhookWorkers, defaultBotWebhookQueueSize
+	if c.Workers > 0 {
+		workers = c.Workers
+	}
+	if c.QueueSize > 0 {
+		queueSize = c.QueueSize
+	}
+
+	return &BotDispatcher{
+		baseMsgTransfer: NewBaseMsgTransfer(svc),
+		sender:          newBotWebhookSender(svc),
+		tasks:           make(chan *immodels.ChatLog, queueSize),
+		workers:         workers,
+		done:            make(chan struct{}),
+	}
+}
+
+// newBotWebhookSender 按配置创建机器人 webhook 的投递客户端
+func newBotWebhookSender(svc *svc.ServiceContext) *webhook.Sender {
+	c := svc.Config.BotWebhook
+
+	var opts []webhook.Option
+	if c.Timeout > 0 {
+		opts = append(opts, webhook.WithTimeout(time.Duration(c.Timeout)*time.Second))
+	}
+	if c.MaxRetry > 0 {
+		opts = append(opts, webhook.WithMaxRetry(c.MaxRetry))
+	}
+	if c.AllowPrivate {
+		opts = append(opts, webhook.WithAllowPrivate())
+	}
+	return webhook.NewSender(opts...)
+}
+
+func (d *BotDispatcher) Start() {
+	var wg sync.WaitGroup
+	for i := 0; i < d.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-d.done:
+					return
+				case chatLog := <-d.tasks:
+					d.handle(chatLog)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (d *BotDispatcher) Stop() {
+	d.once.Do(func() {
+		close(d.done)
+	})
+}
+
+// Enqueue 提交用户发送的群消息，不阻塞消息的处理
+func (d *BotDispatcher) Enqueue(chatLog *immodels.ChatLog) {
+	if d == nil || chatLog.ChatType != constants.GroupChatType || chatLog.MsgFrom == int(constants.BotMsgFrom) {
+		return
+	}
+
+	select {
+	case d.tasks <- chatLog:
+	default:
+		d.Infof("BotDispatcher queue full, drop msgId %v", chatLog.ID.Hex())
+	}
+}
+
+func (d *BotDispatcher) handle(chatLog *immodels.ChatLog) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		// 服务停止时中断重试
+		select {
+		case <-d.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	bots, err := d.groupBots(ctx, chatLog.RecvId)
+	if err != nil {
+		d.Errorf("BotDispatcher groupBots err %v, groupId %v", err, chatLog.RecvId)
+		return
+	}
+
+	command, args := parseCommand(chatLog)
+	for _, bot := range bots {
+		if bot.WebhookUrl == "" || bot.ID == chatLog.SendId {
+			continue
+		}
+
+		event := ""
+		switch {
+		case command != "" && bot.Subscribed(immodels.BotEventCommand):
+			event = immodels.BotEventCommand
+		case bot.Subscribed(immodels.BotEventMessage):
+			event = immodels.BotEventMessage
+		default:
+			continue
+		}
+
+		body, err := json.Marshal(&BotEvent{
+			Event:          event,
+			BotId:          bot.ID,
+			GroupId:        chatLog.RecvId,
+			ConversationId: chatLog.ConversationId,
+			MsgId:          chatLog.ID.Hex(),
+			Seq:            chatLog.Seq,
+			SendId:         chatLog.SendId,
+			SendTime:       chatLog.SendTime,
+			MsgType:        chatLog.MsgType,
+			MsgFrom:        constants.MsgFrom(chatLog.MsgFrom),
+			Content:        chatLog.MsgContent,
+			Mentions:       chatLog.Mentions,
+			Command:        command,
+			Args:           args,
+		})
+		if err != nil {
+			d.Errorf("BotDispatcher marshal err %v", err)
+			continue
+		}
+
+		err = d.sender.Send(ctx, &webhook.Delivery{
+			Id:     chatLog.ID.Hex() + ":" + bot.ID,
+			Url:    bot.WebhookUrl,
+			Secret: bot.Secret,
+			Event:  event,
+			Body:   body,
+		})
+		if err != nil {
+			d.Errorf("BotDispatcher send err %v, botId %v, msgId %v", err, bot.ID, chatLog.ID.Hex())
+		}
+	}
+}
+
+// groupBots 查询群中的机器人
+func (d *BotDispatcher) groupBots(ctx context.Context, groupId string) ([]*immodels.Bot, error) {
+	members, err := d.svcCtx.GroupMembers.Members(ctx, groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.UserId)
+	}
+	return d.svcCtx.BotModel.FindByIds(ctx, ids)
+}
+
+// parseCommand 解析以 / 开头的文本消息，返回命令名与参数
+func parseCommand(chatLog *immodels.ChatLog) (string, string) {
+	if chatLog.MsgType != constants.TextMType {
+		return "", ""
+	}
+	content := strings.TrimSpace(chatLog.MsgContent)
+	if len(content) < 2 || content[0] != '/' {
+		return "", ""
+	}
+
+	command, args, _ := strings.Cut(content[1:], " ")
+	return command, strings.TrimSpace(args)
+}
@@ -44,9 +44,37 @@ func (m *groupMsgRead) mergePush(push *ws.Push) {
 	defer m.mu.Unlock()
 
 	m.count++
+	// 上一批已推送，以本次作为新一批的起点
+	if m.push == nil {
+		m.push = push
+		return
+	}
+
 	for msgId, read := range push.ReadRecords {
 		m.push.ReadRecords[msgId] = read
 	}
+	// 已读游标只会前进，保留最大值
+	for userId, readSeq := range push.ReadCursors {
+		if m.push.ReadCursors == nil {
+			m.push.ReadCursors = make(map[string]int64)
+		}
+		if readSeq > m.push.ReadCursors[userId] {
+			m.push.ReadCursors[userId] = readSeq
+		}
+	}
+	for msgId, delivered := range push.DeliveredRecords {
+		if m.push.DeliveredRecords == nil {
+			m.push.DeliveredRecords = make(map[string]string)
+		}
+		m.push.DeliveredRecords[msgId] = delivered
+	}
+	// 回应推送的是最新的聚合结果，直接覆盖
+	for msgId, reactions := range push.Reactions {
+		if m.push.Reactions == nil {
+			m.push.Reactions = make(map[string]map[string]int)
+		}
+		m.push.Reactions[msgId] = reactions
+	}
 }
 
 func (m *groupMsgRead) transfer() {
@@ -0,0 +1,197 @@
+package msgTransfer
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/linkpreview"
+)
+
+const (
+	defaultLinkPreviewWorkers   = 4
+	defaultLinkPreviewQueueSize = 1000
+	defaultLinkPreviewCacheTtl  = 24 * time.Hour
+	// 抓取失败的结果缓存较短时间，避免同一个链接被反复抓取
+	defaultLinkPreviewFailTtl = 10 * time.Minute
+)
+
+// LinkUnfurler 异步抓取消息中第一个链接的预览，保存到聊天记录并推送给会话参与者
+//
+// 抓取结果按链接缓存在 redis 中；队列满时直接丢弃，不影响消息的投递
+type LinkUnfurler struct {
+	*baseMsgTransfer
+
+	fetcher *linkpreview.Fetcher
+	tasks   chan *immodels.ChatLog
+
+	workers  int
+	cacheTtl time.Duration
+	failTtl  time.Duration
+
+	once sync.Once
+	done chan struct{}
+}
+
+func NewLinkUnfurler(svc *svc.ServiceContext) *LinkUnfurler {
+	c := svc.Config.LinkPreview
+	u := &LinkUnfurler{
+		baseMsgTransfer: NewBaseMsgTransfer(svc),
+		workers:         defaultLinkPreviewWorkers,
+		cacheTtl:        defaultLinkPreviewCacheTtl,
+		failTtl:         defaultLinkPreviewFailTtl,
+		done:            make(chan struct{}),
+	}
+	if c.Workers > 0 {
+		u.workers = c.Workers
+	}
+	if c.CacheTtl > 0 {
+		u.cacheTtl = time.Duration(c.CacheTtl) * time.Second
+	}
+	if c.FailTtl > 0 {
+		u.failTtl = time.Duration(c.FailTtl) * time.Second
+	}
+	queueSize := defaultLinkPreviewQueueSize
+	if c.QueueSize > 0 {
+		queueSize = c.QueueSize
+	}
+	u.tasks = make(chan *immodels.ChatLog, queueSize)
+
+	var opts []linkpreview.Option
+	if c.Timeout > 0 {
+		opts = append(opts, linkpreview.WithTimeout(time.Duration(c.Timeout)*time.Second))
+	}
+	if c.MaxBytes > 0 {
+		opts = append(opts, linkpreview.WithMaxBytes(c.MaxBytes))
+	}
+
+	u.fetcher = linkpreview.NewFetcher(opts...)
+	return u
+}
+
+func (u *LinkUnfurler) Start() {
+	var wg sync.WaitGroup
+	for i := 0; i < u.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-u.done:
+					return
+				case chatLog := <-u.tasks:
+					u.handle(context.Background(), chatLog)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (u *LinkUnfurler) Stop() {
+	u.once.Do(func() {
+		close(u.done)
+	})
+}
+
+// Enqueue 提交包含链接的文本消息，不阻塞消息的处理
+func (u *LinkUnfurler) Enqueue(chatLog *immodels.ChatLog) {
+	if u == nil || chatLog.MsgType != constants.TextMType || linkpreview.ExtractURL(chatLog.MsgContent) == "" {
+		return
+	}
+
+	select {
+	case u.tasks <- chatLog:
+	default:
+		u.Infof("LinkUnfurler queue full, drop msgId %v", chatLog.ID.Hex())
+	}
+}
+
+func (u *LinkUnfurler) handle(ctx context.Context, chatLog *immodels.ChatLog) {
+	preview := u.preview(ctx, linkpreview.ExtractURL(chatLog.MsgContent))
+	if preview == nil {
+		return
+	}
+
+	linkPreview := &immodels.ChatLogLinkPreview{
+		Url:         preview.Url,
+		Title:       preview.Title,
+		Description: preview.Description,
+		Image:       preview.Image,
+		SiteName:    preview.SiteName,
+	}
+	err := u.svcCtx.ChatLogModel.SetLinkPreview(ctx, chatLog.ID, chatLog.MsgContent, linkPreview)
+	if err != nil {
+		if !errors.Is(err, immodels.ErrNotFound) {
+			u.Errorf("LinkUnfurler SetLinkPreview err %v, msgId %v", err, chatLog.ID.Hex())
+		}
+		// 消息已撤回或内容已被编辑
+		return
+	}
+
+	u.push(ctx, chatLog, linkPreview)
+}
+
+// preview 优先读取缓存，抓取失败返回 nil
+func (u *LinkUnfurler) preview(ctx context.Context, url string) *linkpreview.Preview {
+	sum := sha1.Sum([]byte(url))
+	key := constants.REDIS_LINK_PREVIEW + hex.EncodeToString(sum[:])
+
+	if cached, err := u.svcCtx.Redis.GetCtx(ctx, key); err == nil && cached != "" {
+		var preview linkpreview.Preview
+		if err := json.Unmarshal([]byte(cached), &preview); err != nil || preview.Url == "" {
+			// 抓取失败的占位
+			return nil
+		}
+		return &preview
+	}
+
+	preview, err := u.fetcher.Fetch(ctx, url)
+	if err != nil {
+		u.Infof("LinkUnfurler Fetch err %v, url %v", err, url)
+		if err := u.svcCtx.Redis.SetexCtx(ctx, key, "{}", int(u.failTtl.Seconds())); err != nil {
+			u.Errorf("LinkUnfurler Setex err %v", err)
+		}
+		return nil
+	}
+
+	if body, err := json.Marshal(preview); err == nil {
+		if err := u.svcCtx.Redis.SetexCtx(ctx, key, string(body), int(u.cacheTtl.Seconds())); err != nil {
+			u.Errorf("LinkUnfurler Setex err %v", err)
+		}
+	}
+	return preview
+}
+
+// push 推送给会话的全部参与者，包括发送者本人
+func (u *LinkUnfurler) push(ctx context.Context, chatLog *immodels.ChatLog, preview *immodels.ChatLogLinkPreview) {
+	data := &ws.Push{
+		ConversationId: chatLog.ConversationId,
+		ChatType:       chatLog.ChatType,
+		RecvId:         chatLog.RecvId,
+		SendTime:       chatLog.SendTime,
+		MType:          chatLog.MsgType,
+		MsgId:          chatLog.ID.Hex(),
+		Seq:            chatLog.Seq,
+		ContentType:    constants.ContentLinkPreview,
+		LinkPreview: &ws.LinkPreview{
+			Url:         preview.Url,
+			Title:       preview.Title,
+			Description: preview.Description,
+			Image:       preview.Image,
+			SiteName:    preview.SiteName,
+		},
+	}
+
+	if err := u.pushToParticipants(ctx, chatLog, data); err != nil {
+		u.Errorf("LinkUnfurler push err %v, msgId %v", err, chatLog.ID.Hex())
+	}
+}
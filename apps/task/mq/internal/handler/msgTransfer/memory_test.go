@@ -0,0 +1,180 @@
+package msgTransfer
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/im/ws/websocket"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/pkg/moderation"
+)
+
+var errInjected = errors.New("injected error")
+
+// memChatLogModel 内存中的聊天记录，只实现发送消息用到的方法
+type memChatLogModel struct {
+	immodels.ChatLogModel
+
+	mu   sync.Mutex
+	logs map[string]*immodels.ChatLog
+}
+
+func (m *memChatLogModel) Insert(ctx context.Context, data *immodels.ChatLog) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.logs[data.ID.Hex()]; ok {
+		return mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}
+	}
+	stored := *data
+	m.logs[data.ID.Hex()] = &stored
+	return nil
+}
+
+func (m *memChatLogModel) FindOne(ctx context.Context, id string) (*immodels.ChatLog, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.logs[id]
+	if !ok {
+		return nil, immodels.ErrNotFound
+	}
+	stored := *data
+	return &stored, nil
+}
+
+func (m *memChatLogModel) UpdateReaction(ctx context.Context, id primitive.ObjectID, emoji, userId string, add bool,
+	maxPerUser int) (*immodels.ChatLog, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.logs[id.Hex()]
+	if !ok {
+		return nil, immodels.ErrNotFound
+	}
+	if add {
+		if data.Reactions == nil {
+			data.Reactions = make(map[string][]string)
+		}
+		data.Reactions[emoji] = append(data.Reactions[emoji], userId)
+	}
+	stored := *data
+	return &stored, nil
+}
+
+// memConversationModel 内存中的会话，failUpdateMsg 为更新会话消息时需要失败的次数
+type memConversationModel struct {
+	immodels.ConversationModel
+
+	mu            sync.Mutex
+	conversations map[string]*immodels.Conversation
+	failUpdateMsg int
+}
+
+func (m *memConversationModel) FindOne(ctx context.Context, conversationId string) (*immodels.Conversation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.conversations[conversationId]
+	if !ok {
+		return nil, immodels.ErrNotFound
+	}
+	stored := *data
+	return &stored, nil
+}
+
+func (m *memConversationModel) IncrSeq(ctx context.Context, conversationId string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.conversations[conversationId]
+	if !ok {
+		return 0, immodels.ErrNotFound
+	}
+	data.Seq++
+	return data.Seq, nil
+}
+
+func (m *memConversationModel) UpdateMsg(ctx context.Context, chatLog *immodels.ChatLog) error {
+	return m.updateMsg(chatLog, false)
+}
+
+func (m *memConversationModel) UpdateMsgIfNewer(ctx context.Context, chatLog *immodels.ChatLog) error {
+	return m.updateMsg(chatLog, true)
+}
+
+func (m *memConversationModel) updateMsg(chatLog *immodels.ChatLog, ifNewer bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failUpdateMsg > 0 {
+		m.failUpdateMsg--
+		return errInjected
+	}
+	data, ok := m.conversations[chatLog.ConversationId]
+	if !ok || ifNewer && data.Msg != nil && data.Msg.Seq >= chatLog.Seq {
+		return nil
+	}
+	data.Total++
+	data.Msg = chatLog
+	return nil
+}
+
+type memReadCursorModel struct {
+	immodels.ReadCursorModel
+}
+
+func (m *memReadCursorModel) Advance(ctx context.Context, conversationId, userId string, readSeq int64) (int64, error) {
+	return readSeq, nil
+}
+
+// recordingClient 记录发往网关的推送，每个接收者记录一次
+type recordingClient struct {
+	websocket.Client
+
+	mu     sync.Mutex
+	pushes map[string][]*ws.Push
+}
+
+func (c *recordingClient) SendBatch(uids []string, build func(uids []string) any) error {
+	msg := build(uids).(websocket.Message)
+	push := *msg.Data.(*ws.Push)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, uid := range uids {
+		c.pushes[uid] = append(c.pushes[uid], &push)
+	}
+	return nil
+}
+
+func (c *recordingClient) received(uid string) []*ws.Push {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*ws.Push(nil), c.pushes[uid]...)
+}
+
+// memStore 发送消息用到的内存模型与网关
+type memStore struct {
+	chatLogs      *memChatLogModel
+	conversations *memConversationModel
+	client        *recordingClient
+}
+
+func newMemStore(conversations ...*immodels.Conversation) (*memStore, *svc.ServiceContext) {
+	s := &memStore{
+		chatLogs:      &memChatLogModel{logs: make(map[string]*immodels.ChatLog)},
+		conversations: &memConversationModel{conversations: make(map[string]*immodels.Conversation)},
+		client:        &recordingClient{pushes: make(map[string][]*ws.Push)},
+	}
+	for _, conversation := range conversations {
+		s.conversations.conversations[conversation.ConversationId] = conversation
+	}
+
+	return s, &svc.ServiceContext{
+		WsClient:          s.client,
+		ChatLogModel:      s.chatLogs,
+		ConversationModel: s.conversations,
+		ReadCursorModel:   &memReadCursorModel{},
+		Moderation:        moderation.NewDictionary(nil),
+	}
+}
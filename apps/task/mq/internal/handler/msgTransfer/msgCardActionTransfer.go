@@ -0,0 +1,259 @@
+package msgTransfer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/pkg/card"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/webhook"
+)
+
+// 卡片被并发更新时重新处理的次数
+const cardActionRetry = 3
+
+// BotCardActionEvent 卡片按钮点击投递给机器人的请求体
+type BotCardActionEvent struct {
+	Event          string             `json:"event"`
+	BotId          string             `json:"botId"`
+	ConversationId string             `json:"conversationId"`
+	ChatType       constants.ChatType `json:"chatType"`
+	MsgId          string             `json:"msgId"`
+	UserId         string             `json:"userId"`
+	ButtonId       string             `json:"buttonId"`
+	ActionTime     int64              `json:"actionTime"`
+	Card           *card.Card         `json:"card"`
+}
+
+// BotCardActionResult 机器人的响应，Card 不为空时更新卡片，Reply 不为空时回复给点击者
+type BotCardActionResult struct {
+	Card  *card.Card `json:"card"`
+	Reply string     `json:"reply"`
+}
+
+type MsgCardActionTransfer struct {
+	*baseMsgTransfer
+	commands *Commands
+	sender   *webhook.Sender
+}
+
+func NewMsgCardActionTransfer(svc *svc.ServiceContext, commands *Commands) *MsgCardActionTransfer {
+	return &MsgCardActionTransfer{
+		baseMsgTransfer: NewBaseMsgTransfer(svc),
+		commands:        commands,
+		sender:          newBotWebhookSender(svc),
+	}
+}
+
+func (m *MsgCardActionTransfer) Consume(key, value string) error {
+	m.Info("MsgCardActionTransfer ", value)
+
+	var (
+		data mq.MsgCardActionTransfer
+		ctx  = context.Background()
+	)
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return err
+	}
+
+	chatLog, err := m.svcCtx.ChatLogModel.FindOne(ctx, data.MsgId)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			return nil
+		}
+		m.Errorf("MsgCardActionTransfer FindOne err %v, msgId %v", err, data.MsgId)
+		return err
+	}
+	if chatLog.ConversationId != data.ConversationId || chatLog.MsgType != constants.CardMType {
+		m.Errorf("MsgCardActionTransfer invalid action, msgId %v, conversationId %v", data.MsgId, data.ConversationId)
+		return nil
+	}
+	if errMsg := m.checkPermission(ctx, &data, chatLog); errMsg != "" {
+		m.pushToUser(ctx, data.SendId, chatLog.ConversationId, chatLog.ChatType, constants.ContentCardUpdate, errMsg)
+		return nil
+	}
+
+	var botResult *CardUpdate
+	for attempt := 0; attempt < cardActionRetry; attempt++ {
+		if chatLog.Status == 4 {
+			m.pushToUser(ctx, data.SendId, chatLog.ConversationId, chatLog.ChatType, constants.ContentCardUpdate,
+				"消息已撤回")
+			return nil
+		}
+		c, err := card.Parse(chatLog.MsgContent)
+		if err != nil {
+			m.Errorf("MsgCardActionTransfer Parse err %v, msgId %v", err, data.MsgId)
+			return nil
+		}
+		if c.Closed {
+			m.pushToUser(ctx, data.SendId, chatLog.ConversationId, chatLog.ChatType, constants.ContentCardUpdate,
+				"卡片已关闭")
+			return nil
+		}
+		if _, ok := c.Button(data.ButtonId); !ok {
+			return nil
+		}
+
+		var update *CardUpdate
+		if botId := c.BotId(); botId != "" {
+			// 机器人只调用一次，并发冲突时只重试保存
+			if botResult == nil {
+				if botResult, err = m.botAction(ctx, botId, &data, chatLog, c); err != nil {
+					m.Errorf("MsgCardActionTransfer botAction err %v, botId %v", err, botId)
+					m.pushToUser(ctx, data.SendId, chatLog.ConversationId, chatLog.ChatType, constants.ContentCardUpdate,
+						"机器人暂时无法响应")
+					return nil
+				}
+			}
+			update = botResult
+		} else {
+			handler, ok := m.commands.CardHandler(c.Handler)
+			if !ok {
+				m.Infof("MsgCardActionTransfer no handler %v, msgId %v", c.Handler, data.MsgId)
+				return nil
+			}
+			if update, err = handler.Action(ctx, chatLog, c, data.SendId, data.ButtonId); err != nil {
+				m.Errorf("MsgCardActionTransfer Action err %v, msgId %v", err, data.MsgId)
+				return err
+			}
+		}
+
+		if update.Reply != "" {
+			m.pushToUser(ctx, data.SendId, chatLog.ConversationId, chatLog.ChatType, constants.ContentCardUpdate,
+				update.Reply)
+		}
+		if update.Card == nil {
+			return nil
+		}
+
+		err = m.update(ctx, chatLog, c.Handler, update)
+		if !errors.Is(err, immodels.ErrNotFound) {
+			return err
+		}
+
+		// 卡片已被其他点击更新，重新读取后再处理
+		if chatLog, err = m.svcCtx.ChatLogModel.FindOne(ctx, data.MsgId); err != nil {
+			return err
+		}
+	}
+
+	m.Errorf("MsgCardActionTransfer too many conflicts, msgId %v", data.MsgId)
+	return nil
+}
+
+// checkPermission 只有会话的参与者可以点击卡片
+func (m *MsgCardActionTransfer) checkPermission(ctx context.Context, data *mq.MsgCardActionTransfer,
+	chatLog *immodels.ChatLog) string {
+	switch chatLog.ChatType {
+	case constants.SingleChatType:
+		if data.SendId != chatLog.SendId && data.SendId != chatLog.RecvId {
+			return "无权操作该卡片"
+		}
+	case constants.GroupChatType:
+		role, err := m.groupRole(ctx, chatLog.RecvId, data.SendId)
+		if err != nil {
+			m.Errorf("MsgCardActionTransfer groupRole err %v", err)
+			return "操作失败，请稍后重试"
+		}
+		if role == 0 {
+			return "无权操作该卡片"
+		}
+	}
+	return ""
+}
+
+// botAction 把按钮点击投递给发送卡片的机器人，由机器人返回更新后的卡片
+func (m *MsgCardActionTransfer) botAction(ctx context.Context, botId string, data *mq.MsgCardActionTransfer,
+	chatLog *immodels.ChatLog, c *card.Card) (*CardUpdate, error) {
+	bot, err := m.svcCtx.BotModel.FindOne(ctx, botId)
+	if err != nil {
+		return nil, err
+	}
+	if bot.WebhookUrl == "" {
+		return nil, fmt.Errorf("bot %v has no webhook", botId)
+	}
+
+	body, err := json.Marshal(&BotCardActionEvent{
+		Event:          immodels.BotEventCardAction,
+		BotId:          botId,
+		ConversationId: chatLog.ConversationId,
+		ChatType:       chatLog.ChatType,
+		MsgId:          data.MsgId,
+		UserId:         data.SendId,
+		ButtonId:       data.ButtonId,
+		ActionTime:     data.ActionTime,
+		Card:           c,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := m.sender.Call(ctx, &webhook.Delivery{
+		Id:     fmt.Sprintf("%s:%s:%d", data.MsgId, data.SendId, data.ActionTime),
+		Url:    bot.WebhookUrl,
+		Secret: bot.Secret,
+		Event:  immodels.BotEventCardAction,
+		Body:   body,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result BotCardActionResult
+	if len(resp) > 0 {
+		if err := json.Unmarshal(resp, &result); err != nil {
+			return nil, err
+		}
+	}
+	if result.Card != nil {
+		if err := result.Card.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &CardUpdate{Card: result.Card, Reply: result.Reply}, nil
+}
+
+// update 保存新的卡片并推送给会话的全部参与者
+func (m *MsgCardActionTransfer) update(ctx context.Context, chatLog *immodels.ChatLog, handler string,
+	update *CardUpdate) error {
+	// 处理者由服务端决定，机器人不能修改
+	update.Card.Handler = handler
+	content := update.Card.String()
+	state := update.State
+	if state == nil {
+		state = chatLog.CardState
+	}
+
+	if err := m.svcCtx.ChatLogModel.UpdateCard(ctx, chatLog.ID, chatLog.MsgContent, content, state); err != nil {
+		return err
+	}
+	chatLog.MsgContent = content
+	chatLog.CardState = state
+
+	// 如果是最后一条消息，更新会话摘要
+	if err := m.svcCtx.ConversationModel.UpdateMsgIfLatest(ctx, chatLog); err != nil {
+		m.Errorf("MsgCardActionTransfer UpdateMsgIfLatest err %v", err)
+	}
+
+	err := m.pushToParticipants(ctx, chatLog, &ws.Push{
+		ConversationId: chatLog.ConversationId,
+		ChatType:       chatLog.ChatType,
+		RecvId:         chatLog.RecvId,
+		SendTime:       chatLog.SendTime,
+		MType:          chatLog.MsgType,
+		MsgId:          chatLog.ID.Hex(),
+		Seq:            chatLog.Seq,
+		ContentType:    constants.ContentCardUpdate,
+		Content:        content,
+	})
+	if err != nil {
+		m.Errorf("MsgCardActionTransfer push err %v, msgId %v", err, chatLog.ID.Hex())
+	}
+	return nil
+}
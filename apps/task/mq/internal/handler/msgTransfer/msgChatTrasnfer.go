@@ -8,77 +8,379 @@ package msgTransfer
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"imooc.com/easy-chat/apps/im/immodels"
 	"imooc.com/easy-chat/apps/im/ws/ws"
 	"imooc.com/easy-chat/apps/task/mq/internal/svc"
 	"imooc.com/easy-chat/apps/task/mq/mq"
 	"imooc.com/easy-chat/pkg/bitmap"
+	"imooc.com/easy-chat/pkg/card"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/job"
 )
 
 type MsgChatTransfer struct {
 	*baseMsgTransfer
+	unfurler *LinkUnfurler
+	bots     *BotDispatcher
+	commands *Commands
 }
 
-func NewMsgChatTransfer(svc *svc.ServiceContext) *MsgChatTransfer {
+func NewMsgChatTransfer(svc *svc.ServiceContext, unfurler *LinkUnfurler, bots *BotDispatcher,
+	commands *Commands) *MsgChatTransfer {
 	return &MsgChatTransfer{
-		NewBaseMsgTransfer(svc),
+		baseMsgTransfer: NewBaseMsgTransfer(svc),
+		unfurler:        unfurler,
+		bots:            bots,
+		commands:        commands,
 	}
 }
 
+// Prepare 没有指定 chatLogId 的消息预先分配，重试与重放时不会重复入库
+func (m *MsgChatTransfer) Prepare(value string) (string, error) {
+	var data mq.MsgChatTransfer
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return "", err
+	}
+	if data.ChatLogId != "" {
+		return value, nil
+	}
+
+	data.ChatLogId = primitive.NewObjectID().Hex()
+	body, err := json.Marshal(&data)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
 func (m *MsgChatTransfer) Consume(key, value string) error {
-	fmt.Println("key : ", key, " value : ", value)
+	return m.ConsumeContext(context.Background(), key, value)
+}
 
+// ConsumeContext 处理超时后 ctx 被取消，数据库与推送操作随之返回
+func (m *MsgChatTransfer) ConsumeContext(ctx context.Context, key, value string) error {
 	var (
 		data  mq.MsgChatTransfer
-		ctx   = context.Background()
 		msgId = primitive.NewObjectID()
 	)
 	if err := json.Unmarshal([]byte(value), &data); err != nil {
-		return err
+		return job.Permanent(err)
+	}
+	if data.ChatLogId != "" {
+		oid, err := primitive.ObjectIDFromHex(data.ChatLogId)
+		if err != nil {
+			m.Errorf("MsgChatTransfer invalid chatLogId %v", data.ChatLogId)
+			return nil
+		}
+		msgId = oid
 	}
 
+	chatLog := newChatLog(msgId, &data)
+
+	// 转发：校验原消息并生成内容
+	if data.Forward != nil {
+		errMsg, err := m.forward(ctx, &data, chatLog)
+		if err != nil {
+			m.Errorf("MsgChatTransfer forward err %v, req %v", err, data.Forward)
+			return err
+		}
+		if errMsg != "" {
+			m.Errorf("MsgChatTransfer forward denied: userId=%s, err=%s", data.SendId, errMsg)
+			m.pushToUser(ctx, data.SendId, data.ConversationId, data.ChatType, constants.ContentChatMsg, errMsg)
+			return nil
+		}
+	}
+
+	// 卡片只能由机器人或命令生成
+	if data.MType == constants.CardMType {
+		if errMsg := m.botCard(chatLog); errMsg != "" {
+			m.pushToUser(ctx, data.SendId, data.ConversationId, data.ChatType, constants.ContentChatMsg, errMsg)
+			return nil
+		}
+	}
+
+	// 敏感词过滤，在命令之前检查原文，命令生成的卡片与提醒内容不会绕过
+	pass, reviewWords := m.moderate(chatLog)
+	if !pass {
+		m.pushModerationBlocked(ctx, data.SendId, chatLog)
+		return nil
+	}
+
+	// 斜杠命令
+	if !m.command(ctx, chatLog) {
+		return nil
+	}
+
+	// 阅后即焚
+	m.disappear(ctx, chatLog)
+
+	// 校验引用的消息
+	chatLog.Quote = m.quote(ctx, &data)
+	// 校验@的用户
+	mentions, mentionUids := m.mentions(ctx, &data)
+	chatLog.Mentions = mentions
+
 	// 记录数据
-	if err := m.addChatLog(ctx, msgId, &data); err != nil {
+	if err := m.addChatLog(ctx, chatLog); err != nil {
 		return err
 	}
+	if len(reviewWords) > 0 {
+		m.flagForReview(ctx, chatLog, reviewWords)
+	}
+
+	// 记录"有人@我"
+	if len(mentionUids) > 0 {
+		err := m.svcCtx.ConversationsModel.SetMention(ctx, mentionUids, data.ConversationId, &immodels.ConversationMention{
+			MsgId:    msgId.Hex(),
+			SendId:   data.SendId,
+			SendTime: data.SendTime,
+		})
+		if err != nil {
+			m.Errorf("MsgChatTransfer SetMention err %v, msgId %v", err, msgId.Hex())
+		}
+	}
 
-	return m.Transfer(ctx, &ws.Push{
+	push := &ws.Push{
 		ConversationId: data.ConversationId,
 		ChatType:       data.ChatType,
 		SendId:         data.SendId,
 		RecvId:         data.RecvId,
 		RecvIds:        data.RecvIds,
 		SendTime:       data.SendTime,
-		MType:          data.MType,
+		MType:          chatLog.MsgType,
 		MsgId:          msgId.Hex(),
-		Content:        data.Content,
-	})
+		Seq:            chatLog.Seq,
+		Content:        chatLog.MsgContent,
+		Mentions:       chatLog.Mentions,
+		ExpireAt:       chatLog.ExpireAt,
+		DisappearTtl:   chatLog.DisappearTtl,
+		MsgFrom:        data.MsgFrom,
+	}
+	if chatLog.MergeForward != nil {
+		push.MergeForward = &ws.MergeForward{
+			ConversationId: chatLog.MergeForward.ConversationId,
+			Title:          chatLog.MergeForward.Title,
+			Summary:        chatLog.MergeForward.Summary,
+			Count:          len(chatLog.MergeForward.Items),
+		}
+	}
+	if quote := chatLog.Quote; quote != nil {
+		push.Quote = &ws.Quote{
+			MsgId:   quote.MsgId,
+			SendId:  quote.SendId,
+			MType:   quote.MsgType,
+			Snippet: quote.Snippet,
+		}
+	}
+
+	if err := m.Transfer(ctx, push); err != nil {
+		return err
+	}
+
+	// 异步抓取链接预览
+	m.unfurler.Enqueue(chatLog)
+	// 投递给群中的机器人
+	m.bots.Enqueue(chatLog)
+	return nil
+}
+
+// botCard 校验机器人发送的卡片，按钮点击由发送卡片的机器人处理
+func (m *MsgChatTransfer) botCard(chatLog *immodels.ChatLog) string {
+	if chatLog.MsgFrom != int(constants.BotMsgFrom) {
+		return "不支持发送卡片消息"
+	}
+	c, err := card.Parse(chatLog.MsgContent)
+	if err != nil {
+		m.Errorf("MsgChatTransfer bot card err %v, sendId %v", err, chatLog.SendId)
+		return "卡片格式错误"
+	}
+	c.Handler = card.HandlerBotPrefix + chatLog.SendId
+	chatLog.MsgContent = c.String()
+	return ""
 }
 
-func (m *MsgChatTransfer) addChatLog(ctx context.Context, msgId primitive.ObjectID, data *mq.MsgChatTransfer) error {
-	// 记录消息
-	chatLog := immodels.ChatLog{
+// disappear 按会话的阅后即焚设置计算消息的过期时间
+func (m *MsgChatTransfer) disappear(ctx context.Context, chatLog *immodels.ChatLog) {
+	conversation, err := m.svcCtx.ConversationModel.FindOne(ctx, chatLog.ConversationId)
+	if err != nil {
+		if !errors.Is(err, immodels.ErrNotFound) {
+			m.Errorf("MsgChatTransfer disappear FindOne err %v, conversationId %v", err, chatLog.ConversationId)
+		}
+		return
+	}
+	if conversation.Disappear == nil || conversation.Disappear.Mode == constants.DisappearOff {
+		return
+	}
+
+	chatLog.DisappearTtl = conversation.Disappear.Ttl
+	chatLog.ExpireAt = conversation.Disappear.ExpireAt(chatLog.SendTime)
+}
+
+// quote 查询被引用的消息，只允许引用同一会话中存在的消息
+// 引用无效时仅丢弃引用，消息本身照常发送
+func (m *MsgChatTransfer) quote(ctx context.Context, data *mq.MsgChatTransfer) *immodels.ChatLogQuote {
+	if data.QuoteMsgId == "" {
+		return nil
+	}
+
+	quoted, err := m.svcCtx.ChatLogModel.FindOne(ctx, data.QuoteMsgId)
+	if err != nil {
+		m.Errorf("MsgChatTransfer quote FindOne err %v, quoteMsgId %v", err, data.QuoteMsgId)
+		return nil
+	}
+	if quoted.ConversationId != data.ConversationId {
+		m.Errorf("MsgChatTransfer quote msg %v not in conversation %v", data.QuoteMsgId, data.ConversationId)
+		return nil
+	}
+	if quoted.Status == 4 {
+		m.Infof("MsgChatTransfer quote msg %v already revoked", data.QuoteMsgId)
+		return nil
+	}
+
+	return immodels.NewChatLogQuote(quoted)
+}
+
+// mentions 校验@的用户，只有群聊支持@
+// 返回消息中保存的@列表，以及需要记录"有人@我"的用户
+// @全体成员仅群主和管理员可用，其余用户必须是群成员
+func (m *MsgChatTransfer) mentions(ctx context.Context, data *mq.MsgChatTransfer) ([]string, []string) {
+	if len(data.Mentions) == 0 || data.ChatType != constants.GroupChatType {
+		return nil, nil
+	}
+
+	users, err := m.svcCtx.GroupMembers.Members(ctx, data.RecvId)
+	if err != nil {
+		m.Errorf("MsgChatTransfer mentions GroupMembers err %v, groupId %v", err, data.RecvId)
+		return nil, nil
+	}
+
+	var (
+		members    = make(map[string]struct{}, len(users))
+		senderRole constants.GroupRoleLevel
+	)
+	for _, member := range users {
+		members[member.UserId] = struct{}{}
+		if member.UserId == data.SendId {
+			senderRole = constants.GroupRoleLevel(member.RoleLevel)
+		}
+	}
+
+	var (
+		mentions    []string
+		mentionUids []string
+		mentionAll  bool
+		seen        = make(map[string]struct{}, len(data.Mentions))
+	)
+	for _, uid := range data.Mentions {
+		if _, ok := seen[uid]; ok || uid == data.SendId {
+			continue
+		}
+		seen[uid] = struct{}{}
+
+		if uid == constants.MentionAll {
+			if senderRole != constants.CreatorGroupRoleLevel && senderRole != constants.ManagerGroupRoleLevel {
+				m.Infof("MsgChatTransfer user %v has no permission to mention all in %v", data.SendId, data.RecvId)
+				continue
+			}
+			mentionAll = true
+			mentions = append(mentions, uid)
+			continue
+		}
+
+		if _, ok := members[uid]; !ok {
+			m.Infof("MsgChatTransfer mention user %v not in group %v", uid, data.RecvId)
+			continue
+		}
+		mentions = append(mentions, uid)
+		mentionUids = append(mentionUids, uid)
+	}
+
+	// @全体成员时所有成员都需要记录
+	if mentionAll {
+		mentionUids = make([]string, 0, len(members))
+		for uid := range members {
+			if uid != data.SendId {
+				mentionUids = append(mentionUids, uid)
+			}
+		}
+	}
+
+	return mentions, mentionUids
+}
+
+func newChatLog(msgId primitive.ObjectID, data *mq.MsgChatTransfer) *immodels.ChatLog {
+	return &immodels.ChatLog{
 		ID:             msgId,
 		ConversationId: data.ConversationId,
 		SendId:         data.SendId,
 		RecvId:         data.RecvId,
 		ChatType:       data.ChatType,
-		MsgFrom:        0,
+		MsgFrom:        int(data.MsgFrom),
 		MsgType:        data.MType,
 		MsgContent:     data.Content,
 		SendTime:       data.SendTime,
 	}
+}
+
+// addChatLog 记录消息并更新会话，消息已经入库时（消费重试与死信重放）沿用库中的记录与序号，
+// 只重新执行入库之后的步骤
+func (m *MsgChatTransfer) addChatLog(ctx context.Context, chatLog *immodels.ChatLog) error {
+	stored, err := m.svcCtx.ChatLogModel.FindOne(ctx, chatLog.ID.Hex())
+	switch {
+	case err == nil:
+		m.Infof("MsgChatTransfer chatLog %v already exists, seq %v", chatLog.ID.Hex(), stored.Seq)
+		*chatLog = *stored
+	case errors.Is(err, immodels.ErrNotFound):
+		if err := m.insertChatLog(ctx, chatLog); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	// 发送者自己的消息视为已读
+	if chatLog.Seq > 0 {
+		if _, err := m.svcCtx.ReadCursorModel.Advance(ctx, chatLog.ConversationId, chatLog.SendId, chatLog.Seq); err != nil {
+			m.Errorf("MsgChatTransfer Advance read cursor err %v, msgId %v", err, chatLog.ID.Hex())
+		}
+		return m.svcCtx.ConversationModel.UpdateMsgIfNewer(ctx, chatLog)
+	}
+
+	return m.svcCtx.ConversationModel.UpdateMsg(ctx, chatLog)
+}
+
+// insertChatLog 分配会话内的消息序号并入库
+func (m *MsgChatTransfer) insertChatLog(ctx context.Context, chatLog *immodels.ChatLog) error {
+	seq, err := m.svcCtx.ConversationModel.IncrSeq(ctx, chatLog.ConversationId)
+	switch {
+	case err == nil:
+		chatLog.Seq = seq
+	case errors.Is(err, immodels.ErrNotFound):
+		m.Infof("MsgChatTransfer conversation %v not found, chatLog without seq", chatLog.ConversationId)
+	default:
+		return err
+	}
 
 	readRecords := bitmap.NewBitmap(0)
 	readRecords.Set(chatLog.SendId)
 	chatLog.ReadRecords = readRecords.Export()
+	if chatLog.ChatType == constants.GroupChatType {
+		chatLog.DeliveredRecords = append([]byte(nil), chatLog.ReadRecords...)
+	}
 
-	err := m.svcCtx.ChatLogModel.Insert(ctx, &chatLog)
-	if err != nil {
+	err = m.svcCtx.ChatLogModel.Insert(ctx, chatLog)
+	if !mongo.IsDuplicateKeyError(err) {
 		return err
 	}
 
-	return m.svcCtx.ConversationModel.UpdateMsg(ctx, &chatLog)
+	// 同一条消息并发入库，以先入库的记录为准，本次分配的序号不再使用
+	stored, err := m.svcCtx.ChatLogModel.FindOne(ctx, chatLog.ID.Hex())
+	if err != nil {
+		return err
+	}
+	*chatLog = *stored
+	return nil
 }
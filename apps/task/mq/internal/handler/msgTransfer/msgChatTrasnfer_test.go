@@ -0,0 +1,62 @@
+package msgTransfer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+func TestMsgChatTransfer_RetryAfterUpdateMsgFailed(t *testing.T) {
+	s, svcCtx := newMemStore(&immodels.Conversation{ConversationId: "u1_u2", ChatType: constants.SingleChatType})
+	s.conversations.failUpdateMsg = 1
+	m := NewMsgChatTransfer(svcCtx, nil, nil, nil)
+
+	body, _ := json.Marshal(&mq.MsgChatTransfer{
+		ConversationId: "u1_u2",
+		ChatType:       constants.SingleChatType,
+		SendId:         "u1",
+		RecvId:         "u2",
+		MType:          constants.TextMType,
+		Content:        "hello",
+	})
+	value, err := m.Prepare(string(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Consume("u1_u2", value); !errors.Is(err, errInjected) {
+		t.Fatalf("first Consume err = %v, want %v", err, errInjected)
+	}
+	if got := s.client.received("u2"); len(got) != 0 {
+		t.Fatalf("pushed %d before UpdateMsg succeeded", len(got))
+	}
+
+	// 重试时消息已经入库，沿用原来的序号并完成推送
+	if err := m.Consume("u1_u2", value); err != nil {
+		t.Fatalf("retry Consume err = %v", err)
+	}
+	got := s.client.received("u2")
+	if len(got) != 1 || got[0].Content != "hello" || got[0].Seq != 1 {
+		t.Fatalf("retry pushes = %+v, want one push with seq 1", got)
+	}
+
+	conversation, _ := s.conversations.FindOne(context.Background(), "u1_u2")
+	if conversation.Seq != 1 || conversation.Total != 1 || conversation.Msg == nil || conversation.Msg.Seq != 1 {
+		t.Errorf("conversation seq %d total %d msg %+v, want seq 1 total 1", conversation.Seq, conversation.Total,
+			conversation.Msg)
+	}
+
+	// 推送之后再次重放不会重复计数
+	if err := m.Consume("u1_u2", value); err != nil {
+		t.Fatalf("replay Consume err = %v", err)
+	}
+	conversation, _ = s.conversations.FindOne(context.Background(), "u1_u2")
+	if conversation.Seq != 1 || conversation.Total != 1 {
+		t.Errorf("after replay seq %d total %d, want 1 1", conversation.Seq, conversation.Total)
+	}
+}
@@ -0,0 +1,94 @@
+package msgTransfer
+
+import (
+	"context"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/pkg/card"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+// 命令执行出错时回复给发送者的提示
+const commandFailedMsg = "命令执行失败，请稍后重试"
+
+// CommandHandler 处理斜杠命令
+type CommandHandler interface {
+	// Command 处理命令，args 为命令名之后的内容
+	// 可以修改 chatLog（如转换为卡片）后照常发送，send 为 false 时消息不再发送；reply 不为空时回复给发送者
+	Command(ctx context.Context, chatLog *immodels.ChatLog, args string) (send bool, reply string, err error)
+}
+
+// CardHandler 处理命令生成的卡片上的按钮点击
+type CardHandler interface {
+	Action(ctx context.Context, chatLog *immodels.ChatLog, c *card.Card, userId, buttonId string) (*CardUpdate, error)
+}
+
+// CardUpdate 按钮点击的处理结果，Card 为 nil 时卡片不变；Reply 不为空时回复给点击者
+type CardUpdate struct {
+	Card  *card.Card
+	State map[string]string
+	Reply string
+}
+
+// Commands 已注册的斜杠命令，没有注册的命令作为普通消息发送，由订阅了命令的机器人处理
+type Commands struct {
+	commands map[string]CommandHandler
+	cards    map[string]CardHandler
+}
+
+func NewCommands(svc *svc.ServiceContext) *Commands {
+	c := &Commands{
+		commands: make(map[string]CommandHandler),
+		cards:    make(map[string]CardHandler),
+	}
+	c.Register("poll", &pollCommand{})
+	c.Register("remind", &remindCommand{baseMsgTransfer: NewBaseMsgTransfer(svc)})
+	return c
+}
+
+// Register 注册命令，同时实现了 CardHandler 的命令可以处理 Handler 为命令名的卡片
+func (c *Commands) Register(name string, handler CommandHandler) {
+	c.commands[name] = handler
+	if h, ok := handler.(CardHandler); ok {
+		c.cards[name] = h
+	}
+}
+
+func (c *Commands) Command(name string) (CommandHandler, bool) {
+	if c == nil {
+		return nil, false
+	}
+	h, ok := c.commands[name]
+	return h, ok
+}
+
+func (c *Commands) CardHandler(name string) (CardHandler, bool) {
+	if c == nil {
+		return nil, false
+	}
+	h, ok := c.cards[name]
+	return h, ok
+}
+
+// command 执行用户发送的斜杠命令，返回 false 表示消息不再发送
+func (m *MsgChatTransfer) command(ctx context.Context, chatLog *immodels.ChatLog) bool {
+	if chatLog.MsgFrom == int(constants.BotMsgFrom) {
+		return true
+	}
+	name, args := parseCommand(chatLog)
+	handler, ok := m.commands.Command(name)
+	if name == "" || !ok {
+		return true
+	}
+
+	send, reply, err := handler.Command(ctx, chatLog, args)
+	if err != nil {
+		m.Errorf("MsgChatTransfer command %v err %v, sendId %v", name, err, chatLog.SendId)
+		send, reply = false, commandFailedMsg
+	}
+	if reply != "" {
+		m.pushToUser(ctx, chatLog.SendId, chatLog.ConversationId, chatLog.ChatType, constants.ContentChatMsg, reply)
+	}
+	return send
+}
@@ -0,0 +1,108 @@
+package msgTransfer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/card"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+const (
+	pollUsage        = "用法：/poll 问题 | 选项1 | 选项2"
+	pollMinOptions   = 2
+	pollCloseButton  = "close"
+	pollStateCreator = "_creator" // 投票状态中记录发起人的 key，用户ID不会以 _ 开头
+)
+
+// pollCommand 发起投票：/poll 问题 | 选项1 | 选项2
+// 投票以卡片发送，每个选项一个按钮；再次点击已选的选项取消投票，发起人可以结束投票
+type pollCommand struct{}
+
+func (p *pollCommand) Command(ctx context.Context, chatLog *immodels.ChatLog, args string) (bool, string, error) {
+	parts := strings.FieldsFunc(args, func(r rune) bool {
+		return r == '|' || r == '｜'
+	})
+	var fields []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			fields = append(fields, part)
+		}
+	}
+	// 保留一个按钮给结束投票
+	if len(fields) < pollMinOptions+1 || len(fields) > card.MaxButtons {
+		return false, pollUsage, nil
+	}
+
+	c := &card.Card{
+		Title:   fields[0],
+		Text:    "投票",
+		Handler: "poll",
+	}
+	for i, option := range fields[1:] {
+		c.Buttons = append(c.Buttons, card.Button{Id: strconv.Itoa(i + 1), Text: option})
+	}
+	c.Buttons = append(c.Buttons, card.Button{Id: pollCloseButton, Text: "结束投票", Style: "danger"})
+	renderPoll(c, nil)
+	if err := c.Validate(); err != nil {
+		return false, pollUsage, nil
+	}
+
+	chatLog.MsgType = constants.CardMType
+	chatLog.MsgContent = c.String()
+	chatLog.CardState = map[string]string{pollStateCreator: chatLog.SendId}
+	return true, "", nil
+}
+
+func (p *pollCommand) Action(ctx context.Context, chatLog *immodels.ChatLog, c *card.Card, userId,
+	buttonId string) (*CardUpdate, error) {
+	state := make(map[string]string, len(chatLog.CardState)+1)
+	for k, v := range chatLog.CardState {
+		state[k] = v
+	}
+
+	if buttonId == pollCloseButton {
+		if state[pollStateCreator] != userId {
+			return &CardUpdate{Reply: "只有发起人可以结束投票"}, nil
+		}
+		c.Closed = true
+		renderPoll(c, state)
+		return &CardUpdate{Card: c, State: state}, nil
+	}
+
+	// 再次点击已选的选项取消投票，点击其他选项改投
+	if state[userId] == buttonId {
+		delete(state, userId)
+	} else {
+		state[userId] = buttonId
+	}
+	renderPoll(c, state)
+	return &CardUpdate{Card: c, State: state}, nil
+}
+
+// renderPoll 按投票状态更新每个选项的票数与参与人数
+func renderPoll(c *card.Card, state map[string]string) {
+	counts := make(map[string]int, len(c.Buttons))
+	voters := 0
+	for userId, option := range state {
+		if userId == pollStateCreator {
+			continue
+		}
+		counts[option]++
+		voters++
+	}
+	for i := range c.Buttons {
+		if c.Buttons[i].Id != pollCloseButton {
+			c.Buttons[i].Count = counts[c.Buttons[i].Id]
+		}
+	}
+
+	if c.Closed {
+		c.Footer = fmt.Sprintf("投票已结束，共 %d 人参与", voters)
+	} else {
+		c.Footer = fmt.Sprintf("共 %d 人参与", voters)
+	}
+}
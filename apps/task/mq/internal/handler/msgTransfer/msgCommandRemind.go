@@ -0,0 +1,86 @@
+package msgTransfer
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/wuid"
+)
+
+const (
+	remindUsage       = "用法：/remind 时长 内容，如 /remind 30m 开会，时长支持 m、h、d"
+	remindMinDuration = time.Minute
+	remindMaxDuration = 30 * 24 * time.Hour
+)
+
+// remindCommand 设置提醒：/remind 30m 开会
+// 到期后由系统账号在与用户的系统会话中发送提醒，命令本身不会发送到会话中
+type remindCommand struct {
+	*baseMsgTransfer
+}
+
+func (r *remindCommand) Command(ctx context.Context, chatLog *immodels.ChatLog, args string) (bool, string, error) {
+	after, text, _ := strings.Cut(strings.TrimSpace(args), " ")
+	text = strings.TrimSpace(text)
+	d, ok := parseRemindDuration(after)
+	if !ok || text == "" {
+		return false, remindUsage, nil
+	}
+	if d < remindMinDuration || d > remindMaxDuration {
+		return false, "提醒时间需要在 1 分钟到 30 天之间", nil
+	}
+
+	userId := chatLog.SendId
+	conversationId := wuid.CombineId(constants.SYSTEM_ROOT_UID, userId)
+	err := r.svcCtx.ConversationModel.InsertIfNotExists(ctx, &immodels.Conversation{
+		ConversationId: conversationId,
+		ChatType:       constants.SingleChatType,
+		TargetId:       userId,
+		IsShow:         true,
+	})
+	if err != nil {
+		return false, "", err
+	}
+	err = r.svcCtx.ConversationsModel.AddIfNotExists(ctx, userId, &immodels.Conversation{
+		ConversationId: conversationId,
+		ChatType:       constants.SingleChatType,
+		TargetId:       constants.SYSTEM_ROOT_UID,
+		IsShow:         true,
+	})
+	if err != nil {
+		return false, "", err
+	}
+
+	sendAt := time.UnixMilli(chatLog.SendTime).Add(d)
+	err = r.svcCtx.ScheduledMessageModel.Insert(ctx, &immodels.ScheduledMessage{
+		ConversationId: conversationId,
+		ChatType:       constants.SingleChatType,
+		SendId:         constants.SYSTEM_ROOT_UID,
+		RecvId:         userId,
+		MsgType:        constants.TextMType,
+		MsgContent:     "提醒：" + text,
+		SendAt:         sendAt.UnixMilli(),
+	})
+	if err != nil {
+		return false, "", err
+	}
+
+	return false, "已设置提醒，将在 " + sendAt.Format("2006-01-02 15:04") + " 提醒你", nil
+}
+
+// parseRemindDuration 解析时长，在 time.ParseDuration 的基础上支持以 d 结尾的天数
+func parseRemindDuration(s string) (time.Duration, bool) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, false
+		}
+		return time.Duration(n) * 24 * time.Hour, true
+	}
+	d, err := time.ParseDuration(s)
+	return d, err == nil
+}
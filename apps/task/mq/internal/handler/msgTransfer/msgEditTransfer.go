@@ -0,0 +1,147 @@
+package msgTransfer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+// 默认编辑时间窗口：15 分钟
+const defaultEditTimeWindowMs int64 = 15 * 60 * 1000
+
+type MsgEditTransfer struct {
+	*baseMsgTransfer
+	unfurler *LinkUnfurler
+
+	editTimeWindowMs int64
+}
+
+func NewMsgEditTransfer(svc *svc.ServiceContext, unfurler *LinkUnfurler) *MsgEditTransfer {
+	editTimeWindowMs := defaultEditTimeWindowMs
+	if svc.Config.MsgEditHandler.EditTimeWindow > 0 {
+		editTimeWindowMs = svc.Config.MsgEditHandler.EditTimeWindow * 1000
+	}
+
+	return &MsgEditTransfer{
+		baseMsgTransfer:  NewBaseMsgTransfer(svc),
+		unfurler:         unfurler,
+		editTimeWindowMs: editTimeWindowMs,
+	}
+}
+
+func (m *MsgEditTransfer) Consume(key, value string) error {
+	var (
+		data mq.MsgEditTransfer
+		ctx  = context.Background()
+	)
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return err
+	}
+
+	// Step 1: 查询 ChatLog
+	chatLog, err := m.svcCtx.ChatLogModel.FindOne(ctx, data.MsgId)
+	if err != nil {
+		m.Errorf("MsgEditTransfer FindOne err %v, msgId %v", err, data.MsgId)
+		return err
+	}
+	if chatLog.Status == 4 {
+		m.pushErrorToUser(data.SendId, chatLog, "消息已撤回无法编辑")
+		return nil
+	}
+	if chatLog.MsgType != constants.TextMType {
+		m.pushErrorToUser(data.SendId, chatLog, "该消息不支持编辑")
+		return nil
+	}
+	if chatLog.MsgContent == data.Content {
+		// 内容未变化，幂等处理
+		m.Infof("MsgEditTransfer content not changed, msgId %v", data.MsgId)
+		return nil
+	}
+
+	// Step 2: 只有发送者本人可以编辑
+	if data.SendId != chatLog.SendId {
+		m.pushErrorToUser(data.SendId, chatLog, "无权编辑他人消息")
+		return nil
+	}
+
+	// Step 3: 时间校验
+	if data.EditTime-chatLog.SendTime > m.editTimeWindowMs {
+		m.pushErrorToUser(data.SendId, chatLog, fmt.Sprintf("已超过%d分钟无法编辑", m.editTimeWindowMs/60000))
+		return nil
+	}
+
+	// Step 4: 敏感词过滤，编辑后的内容同样需要检查
+	edited := *chatLog
+	edited.MsgContent = data.Content
+	pass, reviewWords := m.moderate(&edited)
+	if !pass {
+		m.pushModerationBlocked(ctx, data.SendId, chatLog)
+		return nil
+	}
+	data.Content = edited.MsgContent
+
+	// Step 5: 保存新内容，旧内容进入编辑历史
+	prevEditTime := chatLog.EditTime
+	if prevEditTime == 0 {
+		prevEditTime = chatLog.SendTime
+	}
+	err = m.svcCtx.ChatLogModel.UpdateEdit(ctx, chatLog.ID, data.Content, data.EditTime, &immodels.ChatLogEdit{
+		MsgContent: chatLog.MsgContent,
+		EditTime:   prevEditTime,
+	})
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			// 编辑期间消息被撤回
+			m.pushErrorToUser(data.SendId, chatLog, "消息已撤回无法编辑")
+			return nil
+		}
+		m.Errorf("MsgEditTransfer UpdateEdit err %v", err)
+		return err
+	}
+
+	chatLog.MsgContent = data.Content
+	chatLog.IsEdited = true
+	chatLog.EditTime = data.EditTime
+	if len(reviewWords) > 0 {
+		m.flagForReview(ctx, chatLog, reviewWords)
+	}
+
+	// 如果编辑的是最后一条消息，更新会话摘要
+	if err := m.svcCtx.ConversationModel.UpdateMsgIfLatest(ctx, chatLog); err != nil {
+		m.Errorf("MsgEditTransfer UpdateMsgIfLatest err %v", err)
+	}
+
+	// Step 6: 推送编辑通知给全体参与者
+	err = m.Transfer(ctx, &ws.Push{
+		ConversationId: chatLog.ConversationId,
+		ChatType:       chatLog.ChatType,
+		SendId:         chatLog.SendId,
+		RecvId:         chatLog.RecvId,
+		SendTime:       chatLog.SendTime,
+		MType:          chatLog.MsgType,
+		MsgId:          data.MsgId,
+		ContentType:    constants.ContentEdit,
+		Content:        data.Content,
+		IsEdited:       true,
+	})
+	if err != nil {
+		return err
+	}
+
+	// 按新内容重新抓取链接预览
+	m.unfurler.Enqueue(chatLog)
+	return nil
+}
+
+// pushErrorToUser 推送错误消息给操作者本人
+func (m *MsgEditTransfer) pushErrorToUser(userId string, chatLog *immodels.ChatLog, errMsg string) {
+	m.Errorf("MsgEditTransfer edit denied: userId=%s, err=%s", userId, errMsg)
+	m.pushToUser(context.Background(), userId, chatLog.ConversationId, chatLog.ChatType, constants.ContentEdit, errMsg)
+}
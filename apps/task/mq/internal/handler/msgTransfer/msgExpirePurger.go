@@ -0,0 +1,121 @@
+package msgTransfer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+const (
+	defaultMsgExpireInterval  = time.Second
+	defaultMsgExpireBatchSize = 100
+)
+
+// MsgExpirePurger 删除已过期的阅后即焚消息，并通知会话参与者移除
+// 通过 ChatLogModel.TakeExpired 原子地取出并删除，多个 task 实例同时运行时不会重复处理
+//
+// 目前的消息类型（文本、合并转发、卡片）内容全部保存在聊天记录中，没有独立存储的媒体文件；
+// 接入图片、文件等媒体消息及对象存储后，需要在 purge 中一并删除对应的对象
+type MsgExpirePurger struct {
+	*baseMsgTransfer
+
+	interval  time.Duration
+	batchSize int
+
+	once sync.Once
+	done chan struct{}
+}
+
+func NewMsgExpirePurger(svc *svc.ServiceContext) *MsgExpirePurger {
+	m := &MsgExpirePurger{
+		baseMsgTransfer: NewBaseMsgTransfer(svc),
+		interval:        defaultMsgExpireInterval,
+		batchSize:       defaultMsgExpireBatchSize,
+		done:            make(chan struct{}),
+	}
+	if svc.Config.MsgExpireHandler.Interval > 0 {
+		m.interval = time.Duration(svc.Config.MsgExpireHandler.Interval) * time.Second
+	}
+	if svc.Config.MsgExpireHandler.BatchSize > 0 {
+		m.batchSize = svc.Config.MsgExpireHandler.BatchSize
+	}
+	return m
+}
+
+func (m *MsgExpirePurger) Start() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			m.purge(context.Background())
+		}
+	}
+}
+
+func (m *MsgExpirePurger) Stop() {
+	m.once.Do(func() {
+		close(m.done)
+	})
+}
+
+func (m *MsgExpirePurger) purge(ctx context.Context) {
+	for i := 0; i < m.batchSize; i++ {
+		chatLog, err := m.svcCtx.ChatLogModel.TakeExpired(ctx, time.Now().UnixMilli())
+		if err != nil {
+			if !errors.Is(err, immodels.ErrNotFound) {
+				m.Errorf("MsgExpirePurger TakeExpired err %v", err)
+			}
+			return
+		}
+
+		m.Infof("MsgExpirePurger purge msg %v", chatLog.ID.Hex())
+
+		// 会话摘要不能展示过期的内容
+		if err := m.svcCtx.ConversationModel.ClearMsgIfLatest(ctx, chatLog.ConversationId, chatLog.ID); err != nil {
+			m.Errorf("MsgExpirePurger ClearMsgIfLatest err %v, msgId %v", err, chatLog.ID.Hex())
+		}
+		if _, err := m.svcCtx.ConversationModel.RemovePin(ctx, chatLog.ConversationId, chatLog.ID.Hex()); err != nil {
+			m.Errorf("MsgExpirePurger RemovePin err %v, msgId %v", err, chatLog.ID.Hex())
+		}
+
+		m.pushExpire(ctx, chatLog)
+	}
+}
+
+// pushExpire 通知会话的全部参与者移除消息，包括发送者本人
+func (m *MsgExpirePurger) pushExpire(ctx context.Context, chatLog *immodels.ChatLog) {
+	push := &ws.Push{
+		ConversationId: chatLog.ConversationId,
+		ChatType:       chatLog.ChatType,
+		RecvId:         chatLog.RecvId,
+		SendTime:       chatLog.SendTime,
+		MsgId:          chatLog.ID.Hex(),
+		ContentType:    constants.ContentExpire,
+	}
+
+	var err error
+	switch chatLog.ChatType {
+	case constants.SingleChatType:
+		if err = m.Transfer(ctx, push); err != nil {
+			break
+		}
+		senderPush := *push
+		senderPush.RecvId = chatLog.SendId
+		err = m.Transfer(ctx, &senderPush)
+	case constants.GroupChatType:
+		err = m.Transfer(ctx, push)
+	}
+	if err != nil {
+		m.Errorf("MsgExpirePurger push err %v, msgId %v", err, chatLog.ID.Hex())
+	}
+}
@@ -0,0 +1,204 @@
+package msgTransfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+const exportTimeLayout = "2006-01-02 15:04:05"
+
+// 消息内容中的媒体地址，媒体消息的内容保存文件地址
+var exportMediaRegexp = regexp.MustCompile(`https?://[^\s"'<>]+`)
+
+type exportArchive struct {
+	ConversationId string             `json:"conversationId"`
+	ChatType       constants.ChatType `json:"chatType"`
+	ExportBy       string             `json:"exportBy"`
+	ExportTime     int64              `json:"exportTime"`
+	// Truncated 消息数超过上限，只导出了最新的部分
+	Truncated bool             `json:"truncated,omitempty"`
+	Members   []*exportMember  `json:"members"`
+	Messages  []*exportMessage `json:"messages"`
+}
+
+type exportMember struct {
+	UserId   string `json:"userId"`
+	Nickname string `json:"nickname"`
+	Avatar   string `json:"avatar,omitempty"`
+}
+
+type exportMessage struct {
+	MsgId    string          `json:"msgId"`
+	Seq      int64           `json:"seq,omitempty"`
+	SendId   string          `json:"sendId"`
+	SendTime int64           `json:"sendTime"`
+	MsgType  constants.MType `json:"msgType"`
+	Content  string          `json:"content"`
+	Revoked  bool            `json:"revoked,omitempty"`
+	Edited   bool            `json:"edited,omitempty"`
+	// QuoteMsgId 引用的消息
+	QuoteMsgId string `json:"quoteMsgId,omitempty"`
+	// Media 消息中引用的媒体地址
+	Media []string `json:"media,omitempty"`
+	// MergeForward 合并转发卡片的标题与条数，完整记录不展开
+	MergeForward *exportMergeForward `json:"mergeForward,omitempty"`
+}
+
+type exportMergeForward struct {
+	Title string `json:"title"`
+	Count int    `json:"count"`
+}
+
+func newExportMessage(chatLog *immodels.ChatLog) *exportMessage {
+	msg := &exportMessage{
+		MsgId:    chatLog.ID.Hex(),
+		Seq:      chatLog.Seq,
+		SendId:   chatLog.SendId,
+		SendTime: chatLog.SendTime,
+		MsgType:  chatLog.MsgType,
+		Content:  chatLog.MsgContent,
+		Edited:   chatLog.IsEdited,
+	}
+	// 已撤回的消息只保留记录，不导出内容
+	if chatLog.Status == 4 {
+		msg.Revoked = true
+		msg.Content = ""
+		return msg
+	}
+	if chatLog.Quote != nil {
+		msg.QuoteMsgId = chatLog.Quote.MsgId
+	}
+	if chatLog.MergeForward != nil {
+		msg.MergeForward = &exportMergeForward{
+			Title: chatLog.MergeForward.Title,
+			Count: len(chatLog.MergeForward.Items),
+		}
+	}
+	msg.Media = exportMediaRegexp.FindAllString(chatLog.MsgContent, -1)
+	return msg
+}
+
+// text 消息在 HTML 与 CSV 中展示的文本
+func (m *exportMessage) text() string {
+	switch {
+	case m.Revoked:
+		return "[消息已撤回]"
+	case m.MergeForward != nil:
+		return fmt.Sprintf("[%s，共%d条]", m.MergeForward.Title, m.MergeForward.Count)
+	default:
+		return m.Content
+	}
+}
+
+func renderExport(w io.Writer, format string, archive *exportArchive) error {
+	switch format {
+	case immodels.ExportFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(archive)
+	case immodels.ExportFormatHTML:
+		return renderExportHTML(w, archive)
+	case immodels.ExportFormatCSV:
+		return renderExportCSV(w, archive)
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func renderExportCSV(w io.Writer, archive *exportArchive) error {
+	// 写入 BOM，便于表格软件识别 UTF-8
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+
+	nicknames := archive.nicknames()
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"time", "msgId", "sendId", "nickname", "msgType", "content", "media", "edited", "revoked"})
+	for _, msg := range archive.Messages {
+		cw.Write([]string{
+			time.UnixMilli(msg.SendTime).Format(exportTimeLayout),
+			msg.MsgId,
+			msg.SendId,
+			nicknames[msg.SendId],
+			strconv.Itoa(int(msg.MsgType)),
+			msg.text(),
+			strings.Join(msg.Media, " "),
+			strconv.FormatBool(msg.Edited),
+			strconv.FormatBool(msg.Revoked),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+var exportHTMLTemplate = template.Must(template.New("export").Funcs(template.FuncMap{
+	"time": func(ms int64) string { return time.UnixMilli(ms).Format(exportTimeLayout) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.ConversationId}}</title>
+<style>
+body { font-family: sans-serif; max-width: 800px; margin: 0 auto; }
+.msg { padding: 8px 0; border-bottom: 1px solid #eee; }
+.meta { color: #888; font-size: 12px; }
+.content { white-space: pre-wrap; }
+.revoked { color: #aaa; font-style: italic; }
+</style>
+</head>
+<body>
+<h2>{{.ConversationId}}</h2>
+<p class="meta">导出时间 {{time .ExportTime}}，共 {{len .Messages}} 条消息</p>
+{{if .Truncated}}<p class="meta">{{.TruncatedNote}}</p>
+{{end}}{{range .Messages}}<div class="msg">
+<div class="meta">{{index $.Nicknames .SendId}} · {{time .SendTime}}{{if .Edited}} · 已编辑{{end}}</div>
+<div class="content{{if .Revoked}} revoked{{end}}">{{.Text}}</div>
+{{range .Media}}<div><a href="{{.}}">{{.}}</a></div>
+{{end}}</div>
+{{end}}</body>
+</html>
+`))
+
+func renderExportHTML(w io.Writer, archive *exportArchive) error {
+	type message struct {
+		*exportMessage
+		Text string
+	}
+	messages := make([]message, 0, len(archive.Messages))
+	for _, msg := range archive.Messages {
+		messages = append(messages, message{exportMessage: msg, Text: msg.text()})
+	}
+
+	return exportHTMLTemplate.Execute(w, map[string]any{
+		"ConversationId": archive.ConversationId,
+		"ExportTime":     archive.ExportTime,
+		"Truncated":      archive.Truncated,
+		"TruncatedNote":  exportTruncatedNote(len(archive.Messages)),
+		"Nicknames":      archive.nicknames(),
+		"Messages":       messages,
+	})
+}
+
+// exportTruncatedNote 超过导出上限时在归档中的说明
+func exportTruncatedNote(count int) string {
+	return fmt.Sprintf("消息数超过导出上限，仅包含最新的 %d 条消息", count)
+}
+
+func (a *exportArchive) nicknames() map[string]string {
+	res := make(map[string]string, len(a.Members))
+	for _, member := range a.Members {
+		res[member.UserId] = member.Nickname
+	}
+	return res
+}
@@ -0,0 +1,378 @@
+package msgTransfer
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/apps/user/rpc/userclient"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+const (
+	defaultMsgExportInterval    = 2 * time.Second
+	defaultMsgExportLease       = 5 * time.Minute
+	defaultMsgExportMaxRetry    = 3
+	defaultMsgExportRetention   = 7 * 24 * time.Hour
+	defaultMsgExportMaxMessages = 100000
+
+	// 每轮最多处理的任务数，避免长时间占用
+	msgExportBatchSize = 10
+	// 分页读取聊天记录的条数
+	msgExportPageSize int64 = 500
+)
+
+var errExportNotMember = errors.New("不是会话成员，无法导出")
+
+// MsgExporter 处理会话导出任务，把用户可见的聊天记录渲染为指定格式并打包为 zip
+//
+// 任务通过 ExportJobModel.Claim 领取，多个 task 实例同时运行时同一个任务只会被一个实例处理；
+// 导出结束后通过 ws 通知发起人，下载链接由 im api 签发
+type MsgExporter struct {
+	*baseMsgTransfer
+
+	dir string
+	// 实例标识，用于领取任务
+	owner string
+
+	interval    time.Duration
+	lease       time.Duration
+	maxRetry    int
+	retention   time.Duration
+	maxMessages int
+
+	once sync.Once
+	done chan struct{}
+}
+
+func NewMsgExporter(svc *svc.ServiceContext) *MsgExporter {
+	c := svc.Config.MsgExport
+	m := &MsgExporter{
+		baseMsgTransfer: NewBaseMsgTransfer(svc),
+		dir:             c.Dir,
+		owner:           primitive.NewObjectID().Hex(),
+		interval:        defaultMsgExportInterval,
+		lease:           defaultMsgExportLease,
+		maxRetry:        defaultMsgExportMaxRetry,
+		retention:       defaultMsgExportRetention,
+		maxMessages:     defaultMsgExportMaxMessages,
+		done:            make(chan struct{}),
+	}
+	if c.Interval > 0 {
+		m.interval = time.Duration(c.Interval) * time.Second
+	}
+	if c.Lease > 0 {
+		m.lease = time.Duration(c.Lease) * time.Second
+	}
+	if c.MaxRetry > 0 {
+		m.maxRetry = c.MaxRetry
+	}
+	if c.Retention > 0 {
+		m.retention = time.Duration(c.Retention) * time.Second
+	}
+	if c.MaxMessages > 0 {
+		m.maxMessages = c.MaxMessages
+	}
+	return m
+}
+
+func (m *MsgExporter) Start() {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		m.Errorf("MsgExporter MkdirAll err %v, dir %v", err, m.dir)
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			ctx := context.Background()
+			m.purge(ctx)
+			m.dispatch(ctx)
+		}
+	}
+}
+
+func (m *MsgExporter) Stop() {
+	m.once.Do(func() {
+		close(m.done)
+	})
+}
+
+// dispatch 领取并处理待导出的任务
+func (m *MsgExporter) dispatch(ctx context.Context) {
+	for i := 0; i < msgExportBatchSize; i++ {
+		select {
+		case <-m.done:
+			return
+		default:
+		}
+
+		job, err := m.svcCtx.ExportJobModel.Claim(ctx, m.owner, time.Now().UnixMilli(), m.lease.Milliseconds())
+		if err != nil {
+			if !errors.Is(err, immodels.ErrNotFound) {
+				m.Errorf("MsgExporter Claim err %v", err)
+			}
+			return
+		}
+
+		m.handle(ctx, job)
+	}
+}
+
+// purge 删除已过期的归档
+func (m *MsgExporter) purge(ctx context.Context) {
+	for {
+		job, err := m.svcCtx.ExportJobModel.TakeExpired(ctx, time.Now().UnixMilli())
+		if err != nil {
+			if !errors.Is(err, immodels.ErrNotFound) {
+				m.Errorf("MsgExporter TakeExpired err %v", err)
+			}
+			return
+		}
+		if err := os.Remove(filepath.Join(m.dir, job.FileName)); err != nil && !os.IsNotExist(err) {
+			m.Errorf("MsgExporter remove archive err %v, id %v", err, job.ID.Hex())
+		}
+	}
+}
+
+func (m *MsgExporter) handle(ctx context.Context, job *immodels.ExportJob) {
+	fileName, fileSize, msgCount, truncated, err := m.export(ctx, job)
+	if err != nil {
+		failed := errors.Is(err, errExportNotMember) || job.RetryCount+1 >= m.maxRetry
+		m.Errorf("MsgExporter export err %v, id %v, failed %v", err, job.ID.Hex(), failed)
+		if err := m.svcCtx.ExportJobModel.Release(ctx, job.ID, m.owner, failed, err.Error()); err != nil {
+			m.Errorf("MsgExporter Release err %v, id %v", err, job.ID.Hex())
+		}
+		if failed {
+			m.notify(ctx, job)
+		}
+		return
+	}
+
+	expireAt := time.Now().Add(m.retention).UnixMilli()
+	err = m.svcCtx.ExportJobModel.MarkDone(ctx, job.ID, m.owner, &immodels.ExportResult{
+		FileName:  fileName,
+		FileSize:  fileSize,
+		MsgCount:  msgCount,
+		Truncated: truncated,
+		ExpireAt:  expireAt,
+	})
+	if err != nil {
+		// 租期已过被其他实例重新领取，归档由对方重新生成
+		m.Errorf("MsgExporter MarkDone err %v, id %v", err, job.ID.Hex())
+		return
+	}
+	m.notify(ctx, job)
+}
+
+// notify 通知发起人任务已结束，客户端根据任务ID查询结果与下载链接
+func (m *MsgExporter) notify(ctx context.Context, job *immodels.ExportJob) {
+	m.pushToUser(ctx, job.UserId, job.ConversationId, job.ChatType, constants.ContentExport, job.ID.Hex())
+}
+
+// export 生成归档，返回文件名、文件大小、导出的消息数，以及是否因超过上限只导出了最新的消息
+func (m *MsgExporter) export(ctx context.Context, job *immodels.ExportJob) (string, int64, int, bool, error) {
+	clearUpTo, err := m.checkMember(ctx, job)
+	if err != nil {
+		return "", 0, 0, false, err
+	}
+
+	chatLogs, truncated, err := m.visibleChatLogs(ctx, job, clearUpTo)
+	if err != nil {
+		return "", 0, 0, false, err
+	}
+
+	archive := &exportArchive{
+		ConversationId: job.ConversationId,
+		ChatType:       job.ChatType,
+		ExportBy:       job.UserId,
+		ExportTime:     time.Now().UnixMilli(),
+		Truncated:      truncated,
+		Members:        m.senders(ctx, chatLogs),
+		Messages:       make([]*exportMessage, 0, len(chatLogs)),
+	}
+	for _, chatLog := range chatLogs {
+		archive.Messages = append(archive.Messages, newExportMessage(chatLog))
+	}
+
+	fileName := job.ID.Hex() + ".zip"
+	fileSize, err := m.writeArchive(fileName, job.Format, archive)
+	if err != nil {
+		return "", 0, 0, false, err
+	}
+	return fileName, fileSize, len(archive.Messages), truncated, nil
+}
+
+// checkMember 导出时再次确认发起人仍是会话成员，返回其清空聊天记录的时间
+func (m *MsgExporter) checkMember(ctx context.Context, job *immodels.ExportJob) (int64, error) {
+	conversations, err := m.svcCtx.ConversationsModel.FindByUserId(ctx, job.UserId)
+	if err != nil {
+		if errors.Is(err, immodels.ErrNotFound) {
+			return 0, errExportNotMember
+		}
+		return 0, err
+	}
+	conversation, ok := conversations.ConversationList[job.ConversationId]
+	if !ok {
+		return 0, errExportNotMember
+	}
+
+	if job.ChatType == constants.GroupChatType {
+		role, err := m.groupRole(ctx, job.ConversationId, job.UserId)
+		if err != nil {
+			return 0, err
+		}
+		if role == 0 {
+			return 0, errExportNotMember
+		}
+	}
+	return conversation.ClearUpTo, nil
+}
+
+// visibleChatLogs 读取发起人可见的聊天记录，排除清空之前、已删除和已过期的消息，结果按会话顺序排列
+// 从最新的消息开始向前翻页，超过 maxMessages 时只保留最新的消息，并返回 truncated
+func (m *MsgExporter) visibleChatLogs(ctx context.Context, job *immodels.ExportJob,
+	clearUpTo int64) ([]*immodels.ChatLog, bool, error) {
+	records, err := m.svcCtx.UserMessageDeletesModel.ListByUserIdAndConversation(ctx, job.UserId, job.ConversationId)
+	if err != nil {
+		return nil, false, err
+	}
+	deleted := make(map[string]bool, len(records))
+	for _, record := range records {
+		deleted[record.MsgId] = true
+	}
+
+	var (
+		res       []*immodels.ChatLog
+		cursor    *immodels.ChatLogCursor
+		truncated bool
+		now       = time.Now().UnixMilli()
+	)
+pages:
+	for {
+		// 每页按从新到旧排序
+		page, err := m.svcCtx.ChatLogModel.ListByCursor(ctx, job.ConversationId, cursor, true, msgExportPageSize)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		cursor = immodels.NewChatLogCursor(page[len(page)-1])
+
+		for _, chatLog := range page {
+			if chatLog.SendTime <= clearUpTo || deleted[chatLog.ID.Hex()] || chatLog.IsExpired(now) {
+				continue
+			}
+			if len(res) >= m.maxMessages {
+				truncated = true
+				break pages
+			}
+			res = append(res, chatLog)
+		}
+
+		if int64(len(page)) < msgExportPageSize {
+			break
+		}
+	}
+
+	// 转换为从旧到新
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res, truncated, nil
+}
+
+// senders 查询消息发送者的资料
+func (m *MsgExporter) senders(ctx context.Context, chatLogs []*immodels.ChatLog) []*exportMember {
+	seen := make(map[string]bool)
+	ids := make([]string, 0)
+	for _, chatLog := range chatLogs {
+		if !seen[chatLog.SendId] {
+			seen[chatLog.SendId] = true
+			ids = append(ids, chatLog.SendId)
+		}
+	}
+
+	members := make([]*exportMember, 0, len(ids))
+	if len(ids) == 0 {
+		return members
+	}
+
+	users, err := m.svcCtx.User.FindUser(ctx, &userclient.FindUserReq{Ids: ids})
+	if err != nil {
+		// 资料查询失败不影响导出，使用用户ID代替昵称
+		m.Errorf("MsgExporter FindUser err %v", err)
+	}
+	profiles := make(map[string]*userclient.UserEntity)
+	if users != nil {
+		for _, user := range users.User {
+			profiles[user.Id] = user
+		}
+	}
+
+	for _, id := range ids {
+		member := &exportMember{UserId: id, Nickname: id}
+		if user, ok := profiles[id]; ok {
+			member.Avatar = user.Avatar
+			if user.Nickname != "" {
+				member.Nickname = user.Nickname
+			}
+		}
+		members = append(members, member)
+	}
+	return members
+}
+
+// writeArchive 渲染并写入 zip 归档，先写入临时文件，完成后再重命名，避免下载到不完整的文件
+func (m *MsgExporter) writeArchive(fileName, format string, archive *exportArchive) (int64, error) {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(m.dir, fileName+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	zw := zip.NewWriter(tmp)
+	if archive.Truncated {
+		zw.SetComment(exportTruncatedNote(len(archive.Messages)))
+	}
+	w, err := zw.Create(fmt.Sprintf("messages.%s", format))
+	if err == nil {
+		err = renderExport(w, format, archive)
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	path := filepath.Join(m.dir, fileName)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
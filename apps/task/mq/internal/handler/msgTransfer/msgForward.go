@@ -0,0 +1,107 @@
+package msgTransfer
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/pkg/card"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+// 合并转发未指定标题时使用的默认标题
+const defaultMergeForwardTitle = "聊天记录"
+
+// forward 校验转发者能看到原消息，并根据原消息生成新消息的内容
+// 返回的 errMsg 不为空表示转发被拒绝，需要通知转发者
+func (m *MsgChatTransfer) forward(ctx context.Context, data *mq.MsgChatTransfer, chatLog *immodels.ChatLog) (string, error) {
+	src := data.Forward
+	if len(src.MsgIds) == 0 {
+		return "转发的消息不存在", nil
+	}
+	if len(src.MsgIds) > immodels.MaxMergeForwardCount {
+		return "转发的消息过多", nil
+	}
+
+	chatLogs, errMsg, err := m.visibleChatLogs(ctx, data.SendId, src.ConversationId, src.MsgIds)
+	if err != nil || errMsg != "" {
+		return errMsg, err
+	}
+
+	if !src.Merge {
+		// 逐条转发：复制原消息的内容
+		source := chatLogs[0]
+		chatLog.MsgType = source.MsgType
+		chatLog.MsgContent = source.MsgContent
+		chatLog.MergeForward = source.MergeForward
+		chatLog.Forward = &immodels.ChatLogForward{
+			ConversationId: source.ConversationId,
+			MsgId:          source.ID.Hex(),
+			SendId:         source.SendId,
+		}
+		// 转发的卡片只是快照，按钮不再响应
+		if source.MsgType == constants.CardMType {
+			if c, err := card.Parse(source.MsgContent); err == nil {
+				c.Closed = true
+				chatLog.MsgContent = c.String()
+			}
+		}
+		return "", nil
+	}
+
+	title := src.Title
+	if title == "" {
+		title = defaultMergeForwardTitle
+	}
+	chatLog.MsgType = constants.MergeForwardMType
+	chatLog.MsgContent = "[" + title + "]"
+	chatLog.MergeForward = immodels.NewChatLogMergeForward(src.ConversationId, title, chatLogs)
+	return "", nil
+}
+
+// visibleChatLogs 查询用户可见的原消息，按发送时间正序
+// 用户必须在该会话中，且消息未撤回、未被用户删除、不早于用户清空会话的时间
+func (m *MsgChatTransfer) visibleChatLogs(ctx context.Context, userId, conversationId string,
+	msgIds []string) ([]*immodels.ChatLog, string, error) {
+	conversations, err := m.svcCtx.ConversationsModel.FindByUserId(ctx, userId)
+	if err != nil && !errors.Is(err, immodels.ErrNotFound) {
+		return nil, "", err
+	}
+	if conversations == nil || conversations.ConversationList[conversationId] == nil {
+		return nil, "无权转发该会话的消息", nil
+	}
+	clearUpTo := conversations.ConversationList[conversationId].ClearUpTo
+
+	deletedRecords, err := m.svcCtx.UserMessageDeletesModel.ListByUserIdAndConversation(ctx, userId, conversationId)
+	if err != nil {
+		return nil, "", err
+	}
+	deletedSet := make(map[string]struct{}, len(deletedRecords))
+	for _, dr := range deletedRecords {
+		deletedSet[dr.MsgId] = struct{}{}
+	}
+
+	chatLogs, err := m.svcCtx.ChatLogModel.ListByMsgIds(ctx, msgIds)
+	if err != nil && !errors.Is(err, immodels.ErrNotFound) {
+		return nil, "", err
+	}
+	if len(chatLogs) != len(msgIds) {
+		return nil, "部分消息不存在", nil
+	}
+
+	for _, chatLog := range chatLogs {
+		if chatLog.ConversationId != conversationId || chatLog.Status == 4 {
+			return nil, "部分消息不存在", nil
+		}
+		if _, ok := deletedSet[chatLog.ID.Hex()]; ok || (clearUpTo > 0 && chatLog.SendTime <= clearUpTo) {
+			return nil, "部分消息已被删除", nil
+		}
+	}
+
+	sort.Slice(chatLogs, func(i, j int) bool {
+		return chatLogs[i].SendTime < chatLogs[j].SendTime
+	})
+	return chatLogs, "", nil
+}
@@ -0,0 +1,120 @@
+package msgTransfer
+
+import (
+	"context"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+	"imooc.com/easy-chat/pkg/card"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/moderation"
+)
+
+// 消息被敏感词拦截时通知发送者的内容
+const moderationBlockedMsg = "消息包含敏感内容，发送失败"
+
+// moderate 使用敏感词库检查消息中的全部文本，命中替换规则的内容直接改写；
+// 卡片检查标题、正文、页脚与按钮，合并转发检查标题、摘要与每条原消息
+// 返回 false 表示消息被拦截，返回的 words 不为空表示消息需要进入审核队列
+func (m *baseMsgTransfer) moderate(chatLog *immodels.ChatLog) (bool, []string) {
+	// 系统用户发送的公告不检查
+	if chatLog.MsgContent == "" || chatLog.SendId == constants.SYSTEM_ROOT_UID {
+		return true, nil
+	}
+
+	var (
+		c      *card.Card
+		fields = []*string{&chatLog.MsgContent}
+	)
+	switch chatLog.MsgType {
+	case constants.CardMType:
+		parsed, err := card.Parse(chatLog.MsgContent)
+		if err != nil {
+			// 卡片格式在 botCard 中校验，这里按原文检查
+			break
+		}
+		c = parsed
+		fields = cardFields(c)
+	case constants.MergeForwardMType:
+		fields = append(fields, mergeForwardFields(chatLog.MergeForward)...)
+	}
+
+	var words []string
+	for _, field := range fields {
+		if *field == "" {
+			continue
+		}
+		res := m.svcCtx.Moderation.Check(*field)
+		switch res.Action {
+		case moderation.ActionBlock:
+			m.Infof("moderation block msg from %v, words %v", chatLog.SendId, res.Words)
+			return false, nil
+		case moderation.ActionReview:
+			words = append(words, res.Words...)
+		}
+		*field = res.Content
+	}
+	if c != nil {
+		chatLog.MsgContent = c.String()
+	}
+	return true, words
+}
+
+// cardFields 卡片中展示给用户的文本
+func cardFields(c *card.Card) []*string {
+	fields := []*string{&c.Title, &c.Text, &c.Footer}
+	for i := range c.Buttons {
+		fields = append(fields, &c.Buttons[i].Text)
+	}
+	return fields
+}
+
+// mergeForwardFields 合并转发中展示给用户的文本，包括嵌套的合并转发
+func mergeForwardFields(mf *immodels.ChatLogMergeForward) []*string {
+	if mf == nil {
+		return nil
+	}
+
+	fields := []*string{&mf.Title}
+	for i := range mf.Summary {
+		fields = append(fields, &mf.Summary[i])
+	}
+	for _, item := range mf.Items {
+		fields = append(fields, &item.MsgContent)
+		fields = append(fields, mergeForwardFields(item.MergeForward)...)
+	}
+	return fields
+}
+
+// pushModerationBlocked 通知发送者消息被拦截，发送与编辑使用同一种通知
+func (m *baseMsgTransfer) pushModerationBlocked(ctx context.Context, userId string, chatLog *immodels.ChatLog) {
+	push := &ws.Push{
+		ConversationId: chatLog.ConversationId,
+		ChatType:       chatLog.ChatType,
+		RecvId:         userId,
+		RecvIds:        []string{userId},
+		MsgId:          chatLog.ID.Hex(),
+		ContentType:    constants.ContentModerationBlocked,
+		Content:        moderationBlockedMsg,
+	}
+	if err := m.single(ctx, push); err != nil {
+		m.Errorf("pushModerationBlocked err %v, userId %v", err, userId)
+	}
+}
+
+// flagForReview 把消息加入审核队列，消息照常发送，由管理员审核后决定是否撤回
+func (m *baseMsgTransfer) flagForReview(ctx context.Context, chatLog *immodels.ChatLog, words []string) {
+	err := m.svcCtx.ModerationReviewModel.Insert(ctx, &immodels.ModerationReview{
+		MsgId:          chatLog.ID.Hex(),
+		ConversationId: chatLog.ConversationId,
+		ChatType:       chatLog.ChatType,
+		SendId:         chatLog.SendId,
+		RecvId:         chatLog.RecvId,
+		MsgContent:     chatLog.MsgContent,
+		SendTime:       chatLog.SendTime,
+		Words:          words,
+	})
+	if err != nil {
+		m.Errorf("moderation flagForReview err %v, msgId %v", err, chatLog.ID.Hex())
+	}
+}
@@ -0,0 +1,73 @@
+package msgTransfer
+
+import (
+	"strings"
+	"testing"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/pkg/card"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/moderation"
+)
+
+func newModerationTransfer() *baseMsgTransfer {
+	return NewBaseMsgTransfer(&svc.ServiceContext{
+		Moderation: moderation.NewDictionary([]moderation.Rule{
+			{Word: "block", Action: moderation.ActionBlock},
+			{Word: "review", Action: moderation.ActionReview},
+			{Word: "mask", Action: moderation.ActionReplace},
+		}),
+	})
+}
+
+func TestModerate_Card(t *testing.T) {
+	m := newModerationTransfer()
+
+	c := &card.Card{Title: "poll", Buttons: []card.Button{{Id: "1", Text: "mask me"}}}
+	chatLog := &immodels.ChatLog{SendId: "u1", MsgType: constants.CardMType, MsgContent: c.String()}
+	pass, words := m.moderate(chatLog)
+	if !pass || len(words) != 0 {
+		t.Fatalf("moderate = %v %v, want pass", pass, words)
+	}
+	if strings.Contains(chatLog.MsgContent, "mask") {
+		t.Errorf("button text not replaced: %v", chatLog.MsgContent)
+	}
+	if _, err := card.Parse(chatLog.MsgContent); err != nil {
+		t.Errorf("rewritten card invalid: %v", err)
+	}
+
+	c = &card.Card{Title: "poll", Text: "block"}
+	chatLog = &immodels.ChatLog{SendId: "u1", MsgType: constants.CardMType, MsgContent: c.String()}
+	if pass, _ := m.moderate(chatLog); pass {
+		t.Error("card text with blocked word passed")
+	}
+}
+
+func TestModerate_MergeForward(t *testing.T) {
+	m := newModerationTransfer()
+
+	chatLog := &immodels.ChatLog{
+		SendId:     "u1",
+		MsgType:    constants.MergeForwardMType,
+		MsgContent: "[聊天记录]",
+		MergeForward: &immodels.ChatLogMergeForward{
+			Title: "聊天记录",
+			Items: []*immodels.ChatLogForwardItem{
+				{MsgContent: "hello"},
+				{MergeForward: &immodels.ChatLogMergeForward{
+					Items: []*immodels.ChatLogForwardItem{{MsgContent: "please review"}},
+				}},
+			},
+		},
+	}
+	pass, words := m.moderate(chatLog)
+	if !pass || len(words) != 1 || words[0] != "review" {
+		t.Fatalf("moderate = %v %v, want pass with review words", pass, words)
+	}
+
+	chatLog.MergeForward.Items[0].MsgContent = "block"
+	if pass, _ := m.moderate(chatLog); pass {
+		t.Error("forwarded item with blocked word passed")
+	}
+}
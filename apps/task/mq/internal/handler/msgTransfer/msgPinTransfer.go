@@ -0,0 +1,159 @@
+package msgTransfer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+// 默认每个会话最多置顶的消息数
+const defaultMaxPinsPerConversation = 10
+
+type MsgPinTransfer struct {
+	*baseMsgTransfer
+
+	maxPins int
+}
+
+func NewMsgPinTransfer(svc *svc.ServiceContext) *MsgPinTransfer {
+	maxPins := defaultMaxPinsPerConversation
+	if svc.Config.MsgPinHandler.MaxPins > 0 {
+		maxPins = svc.Config.MsgPinHandler.MaxPins
+	}
+
+	return &MsgPinTransfer{
+		baseMsgTransfer: NewBaseMsgTransfer(svc),
+		maxPins:         maxPins,
+	}
+}
+
+func (m *MsgPinTransfer) Consume(key, value string) error {
+	var (
+		data mq.MsgPinTransfer
+		ctx  = context.Background()
+	)
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return err
+	}
+
+	// Step 1: 查询 ChatLog
+	chatLog, err := m.svcCtx.ChatLogModel.FindOne(ctx, data.MsgId)
+	if err != nil {
+		m.Errorf("MsgPinTransfer FindOne err %v, msgId %v", err, data.MsgId)
+		return err
+	}
+	if chatLog.ConversationId != data.ConversationId {
+		m.Errorf("MsgPinTransfer conversation mismatch, msgId %v, conversationId %v", data.MsgId, data.ConversationId)
+		return nil
+	}
+
+	// Step 2: 权限校验
+	ok, err := m.checkPermission(ctx, data.SendId, chatLog)
+	if err != nil {
+		m.Errorf("MsgPinTransfer checkPermission err %v", err)
+		return err
+	}
+	if !ok {
+		m.pushErrorToUser(data.SendId, chatLog, "无权置顶该消息")
+		return nil
+	}
+
+	// 取消置顶
+	if data.Unpin {
+		return m.removePin(ctx, data.SendId, chatLog)
+	}
+
+	// Step 3: 置顶
+	if chatLog.Status == 4 {
+		m.pushErrorToUser(data.SendId, chatLog, "消息已撤回无法置顶")
+		return nil
+	}
+
+	conversation, err := m.svcCtx.ConversationModel.FindOne(ctx, chatLog.ConversationId)
+	if err != nil {
+		m.Errorf("MsgPinTransfer ConversationModel.FindOne err %v", err)
+		return err
+	}
+	for _, pin := range conversation.Pins {
+		if pin.MsgId == data.MsgId {
+			// 已经置顶，幂等处理
+			m.Infof("MsgPinTransfer msg already pinned, msgId %v", data.MsgId)
+			return nil
+		}
+	}
+
+	added, err := m.svcCtx.ConversationModel.AddPin(ctx, chatLog.ConversationId, &immodels.ConversationPin{
+		MsgId:   data.MsgId,
+		PinBy:   data.SendId,
+		PinTime: data.PinTime,
+	}, m.maxPins)
+	if err != nil {
+		m.Errorf("MsgPinTransfer AddPin err %v", err)
+		return err
+	}
+	if !added {
+		m.pushErrorToUser(data.SendId, chatLog, fmt.Sprintf("每个会话最多置顶%d条消息", m.maxPins))
+		return nil
+	}
+
+	// Step 4: 推送置顶通知给全体参与者
+	return m.Transfer(ctx, pinPush(data.SendId, chatLog, constants.ContentPin))
+}
+
+// checkPermission 单聊双方都可以置顶，群聊仅群主和管理员可以置顶
+func (m *MsgPinTransfer) checkPermission(ctx context.Context, userId string, chatLog *immodels.ChatLog) (bool, error) {
+	switch chatLog.ChatType {
+	case constants.SingleChatType:
+		return userId == chatLog.SendId || userId == chatLog.RecvId, nil
+	case constants.GroupChatType:
+		return m.isGroupManager(ctx, chatLog.RecvId, userId)
+	}
+	return false, nil
+}
+
+// pushErrorToUser 推送错误消息给操作者本人
+func (m *MsgPinTransfer) pushErrorToUser(userId string, chatLog *immodels.ChatLog, errMsg string) {
+	m.Errorf("MsgPinTransfer pin denied: userId=%s, err=%s", userId, errMsg)
+	m.pushToUser(context.Background(), userId, chatLog.ConversationId, chatLog.ChatType, constants.ContentPin, errMsg)
+}
+
+// removePin 取消消息的置顶并通知全体参与者，消息未置顶时不做处理
+func (m *baseMsgTransfer) removePin(ctx context.Context, userId string, chatLog *immodels.ChatLog) error {
+	removed, err := m.svcCtx.ConversationModel.RemovePin(ctx, chatLog.ConversationId, chatLog.ID.Hex())
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return nil
+	}
+
+	return m.Transfer(ctx, pinPush(userId, chatLog, constants.ContentUnpin))
+}
+
+func pinPush(userId string, chatLog *immodels.ChatLog, contentType constants.ContentType) *ws.Push {
+	push := &ws.Push{
+		ConversationId: chatLog.ConversationId,
+		ChatType:       chatLog.ChatType,
+		SendId:         userId,
+		RecvId:         chatLog.RecvId,
+		SendTime:       chatLog.SendTime,
+		MType:          chatLog.MsgType,
+		MsgId:          chatLog.ID.Hex(),
+		ContentType:    contentType,
+		Content:        chatLog.MsgContent,
+	}
+	// 单聊推送给操作者的对方
+	if chatLog.ChatType == constants.SingleChatType && userId == chatLog.RecvId {
+		push.RecvId = chatLog.SendId
+	}
+	if contentType == constants.ContentUnpin {
+		push.Content = ""
+	}
+	return push
+}
@@ -0,0 +1,204 @@
+package msgTransfer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/im/ws/ws"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/job"
+	"imooc.com/easy-chat/pkg/mqx"
+)
+
+// 默认每个用户对同一条消息最多使用的表情数
+const defaultMaxReactionsPerUser = 10
+
+type MsgReactionTransfer struct {
+	*baseMsgTransfer
+
+	maxReactionsPerUser int
+
+	mu sync.Mutex
+
+	groupMsgs map[string]*groupMsgRead
+	push      chan *ws.Push
+}
+
+func NewMsgReactionTransfer(svc *svc.ServiceContext) mqx.ConsumeHandler {
+	maxReactionsPerUser := defaultMaxReactionsPerUser
+	if svc.Config.MsgReactionHandler.MaxReactionsPerUser > 0 {
+		maxReactionsPerUser = svc.Config.MsgReactionHandler.MaxReactionsPerUser
+	}
+
+	m := &MsgReactionTransfer{
+		baseMsgTransfer:     NewBaseMsgTransfer(svc),
+		maxReactionsPerUser: maxReactionsPerUser,
+		groupMsgs:           make(map[string]*groupMsgRead, 1),
+		push:                make(chan *ws.Push, 1),
+	}
+
+	go m.transfer()
+
+	return m
+}
+
+func (m *MsgReactionTransfer) Consume(key, value string) error {
+	m.Info("MsgReactionTransfer ", value)
+
+	var (
+		data mq.MsgReactionTransfer
+		ctx  = context.Background()
+	)
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return job.Permanent(err)
+	}
+
+	chatLog, err := m.svcCtx.ChatLogModel.FindOne(ctx, data.MsgId)
+	if err != nil {
+		m.Errorf("MsgReactionTransfer FindOne err %v, msgId %v", err, data.MsgId)
+		return err
+	}
+	if chatLog.ConversationId != data.ConversationId {
+		m.Errorf("MsgReactionTransfer conversation mismatch, msgId %v, conversationId %v", data.MsgId, data.ConversationId)
+		return nil
+	}
+	if chatLog.Status == 4 {
+		m.pushErrorToUser(data.SendId, chatLog, "消息已撤回无法回应")
+		return nil
+	}
+	// 单聊只有双方可以回应，群聊只有群成员可以回应
+	switch chatLog.ChatType {
+	case constants.SingleChatType:
+		if data.SendId != chatLog.SendId && data.SendId != chatLog.RecvId {
+			m.pushErrorToUser(data.SendId, chatLog, "无权回应该消息")
+			return nil
+		}
+	case constants.GroupChatType:
+		role, err := m.groupRole(ctx, chatLog.RecvId, data.SendId)
+		if err != nil {
+			m.Errorf("MsgReactionTransfer groupRole err %v, groupId %v", err, chatLog.RecvId)
+			return err
+		}
+		if role == 0 {
+			m.pushErrorToUser(data.SendId, chatLog, "无权回应该消息")
+			return nil
+		}
+	}
+
+	// 数量限制，重复回应同一表情不计入
+	userReactions := chatLog.UserReactions(data.SendId)
+	reacted := false
+	for _, emoji := range userReactions {
+		if emoji == data.Emoji {
+			reacted = true
+			break
+		}
+	}
+	if reacted != data.Unreact {
+		// 已回应过再次回应、未回应过取消回应，均为幂等处理
+		m.Infof("MsgReactionTransfer nothing changed, msgId %v, emoji %v", data.MsgId, data.Emoji)
+		return nil
+	}
+	limitMsg := fmt.Sprintf("每条消息最多回应%d个表情", m.maxReactionsPerUser)
+	if !data.Unreact && len(userReactions) >= m.maxReactionsPerUser {
+		m.pushErrorToUser(data.SendId, chatLog, limitMsg)
+		return nil
+	}
+
+	// 并发回应时以更新条件中的数量校验为准
+	updated, err := m.svcCtx.ChatLogModel.UpdateReaction(ctx, chatLog.ID, data.Emoji, data.SendId, !data.Unreact,
+		m.maxReactionsPerUser)
+	if err != nil {
+		if errors.Is(err, immodels.ErrReactionLimit) {
+			m.pushErrorToUser(data.SendId, chatLog, limitMsg)
+			return nil
+		}
+		if errors.Is(err, immodels.ErrNotFound) {
+			m.Infof("MsgReactionTransfer msg revoked while reacting, msgId %v", data.MsgId)
+			return nil
+		}
+		m.Errorf("MsgReactionTransfer UpdateReaction err %v", err)
+		return err
+	}
+	chatLog = updated
+
+	push := &ws.Push{
+		ConversationId: chatLog.ConversationId,
+		ChatType:       chatLog.ChatType,
+		SendId:         data.SendId,
+		RecvId:         chatLog.RecvId,
+		MsgId:          data.MsgId,
+		ContentType:    constants.ContentReaction,
+		Reactions: map[string]map[string]int{
+			data.MsgId: chatLog.ReactionCountMap(),
+		},
+	}
+
+	switch chatLog.ChatType {
+	case constants.SingleChatType:
+		// 推送给对方
+		if push.RecvId == data.SendId {
+			push.RecvId = chatLog.SendId
+		}
+		m.push <- push
+	case constants.GroupChatType:
+		if m.svcCtx.Config.MsgReactionHandler.GroupMsgReactionHandler == GroupMsgReadHandlerAtTransfer {
+			m.push <- push
+			return nil
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		// 合并后的推送包含多人的回应，需要推送给全体成员
+		push.SendId = ""
+		push.MsgId = ""
+
+		if _, ok := m.groupMsgs[push.ConversationId]; ok {
+			m.Infof("merge reaction push %v", push.ConversationId)
+			m.groupMsgs[push.ConversationId].mergePush(push)
+		} else {
+			m.Infof("newGroupMsgRead reaction push %v", push.ConversationId)
+			m.groupMsgs[push.ConversationId] = newGroupMsgRead(push, m.push)
+		}
+	}
+
+	return nil
+}
+
+func (m *MsgReactionTransfer) transfer() {
+	for push := range m.push {
+		if push.RecvId != "" || len(push.RecvIds) > 0 {
+			if err := m.Transfer(context.Background(), push); err != nil {
+				m.Errorf("m transfer err %v push %v", err, push)
+			}
+		}
+
+		if push.ChatType == constants.SingleChatType {
+			continue
+		}
+
+		if m.svcCtx.Config.MsgReactionHandler.GroupMsgReactionHandler == GroupMsgReadHandlerAtTransfer {
+			continue
+		}
+		// 清空数据
+		m.mu.Lock()
+		if _, ok := m.groupMsgs[push.ConversationId]; ok && m.groupMsgs[push.ConversationId].IsIdle() {
+			m.groupMsgs[push.ConversationId].clear()
+			delete(m.groupMsgs, push.ConversationId)
+		}
+		m.mu.Unlock()
+	}
+}
+
+// pushErrorToUser 推送错误消息给操作者本人
+func (m *MsgReactionTransfer) pushErrorToUser(userId string, chatLog *immodels.ChatLog, errMsg string) {
+	m.Errorf("MsgReactionTransfer reaction denied: userId=%s, err=%s", userId, errMsg)
+	m.pushToUser(context.Background(), userId, chatLog.ConversationId, chatLog.ChatType, constants.ContentReaction, errMsg)
+}
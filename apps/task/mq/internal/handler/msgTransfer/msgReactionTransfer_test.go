@@ -0,0 +1,67 @@
+package msgTransfer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/job"
+	"imooc.com/easy-chat/pkg/membership"
+)
+
+func TestMsgReactionTransfer_GroupMember(t *testing.T) {
+	s, svcCtx := newMemStore()
+	svcCtx.GroupMembers = membership.NewCache(func(ctx context.Context, groupId string) ([]*membership.Member, error) {
+		return []*membership.Member{
+			{UserId: "u1", RoleLevel: int(constants.CreatorGroupRoleLevel)},
+			{UserId: "u2", RoleLevel: int(constants.AtLargeGroupRoleLevel)},
+		}, nil
+	})
+	m := NewMsgReactionTransfer(svcCtx)
+
+	chatLog := &immodels.ChatLog{
+		ID:             primitive.NewObjectID(),
+		ConversationId: "g1",
+		ChatType:       constants.GroupChatType,
+		SendId:         "u1",
+		RecvId:         "g1",
+	}
+	if err := s.chatLogs.Insert(context.Background(), chatLog); err != nil {
+		t.Fatal(err)
+	}
+
+	react := func(userId string) error {
+		body, _ := json.Marshal(&mq.MsgReactionTransfer{
+			MsgId:          chatLog.ID.Hex(),
+			ConversationId: "g1",
+			SendId:         userId,
+			Emoji:          "👍",
+		})
+		return m.Consume("g1", string(body))
+	}
+
+	if err := react("outsider"); err != nil {
+		t.Fatalf("outsider Consume err = %v", err)
+	}
+	if stored, _ := s.chatLogs.FindOne(context.Background(), chatLog.ID.Hex()); len(stored.Reactions) != 0 {
+		t.Fatalf("outsider reaction stored: %v", stored.Reactions)
+	}
+	if got := s.client.received("outsider"); len(got) != 1 || got[0].ContentType != constants.ContentReaction {
+		t.Errorf("outsider pushes = %+v, want one denial", got)
+	}
+
+	if err := react("u2"); err != nil {
+		t.Fatalf("member Consume err = %v", err)
+	}
+	if stored, _ := s.chatLogs.FindOne(context.Background(), chatLog.ID.Hex()); len(stored.Reactions["👍"]) != 1 {
+		t.Errorf("member reaction not stored: %v", stored.Reactions)
+	}
+
+	if err := m.Consume("g1", "{"); !job.IsPermanent(err) {
+		t.Errorf("invalid payload err = %v, want permanent", err)
+	}
+}
@@ -9,13 +9,16 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
-	"github.com/zeromicro/go-queue/kq"
+	"errors"
 	"github.com/zeromicro/go-zero/core/stores/cache"
+	"imooc.com/easy-chat/apps/im/immodels"
 	"imooc.com/easy-chat/apps/im/ws/ws"
 	"imooc.com/easy-chat/apps/task/mq/internal/svc"
 	"imooc.com/easy-chat/apps/task/mq/mq"
 	"imooc.com/easy-chat/pkg/bitmap"
 	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/job"
+	"imooc.com/easy-chat/pkg/mqx"
 	"sync"
 	"time"
 )
@@ -41,7 +44,7 @@ type MsgReadTransfer struct {
 	push      chan *ws.Push
 }
 
-func NewMsgReadTransfer(svc *svc.ServiceContext) kq.ConsumeHandler {
+func NewMsgReadTransfer(svc *svc.ServiceContext) mqx.ConsumeHandler {
 	m := &MsgReadTransfer{
 		baseMsgTransfer: NewBaseMsgTransfer(svc),
 		groupMsgs:       make(map[string]*groupMsgRead, 1),
@@ -64,29 +67,43 @@ func NewMsgReadTransfer(svc *svc.ServiceContext) kq.ConsumeHandler {
 }
 
 func (m *MsgReadTransfer) Consume(key, value string) error {
+	return m.ConsumeContext(context.Background(), key, value)
+}
+
+func (m *MsgReadTransfer) ConsumeContext(ctx context.Context, key, value string) error {
 	m.Info("MsgReadTransfer ", value)
 
-	var (
-		data mq.MsgMarkRead
-		ctx  = context.Background()
-	)
+	var data mq.MsgMarkRead
 	if err := json.Unmarshal([]byte(value), &data); err != nil {
-		return err
+		return job.Permanent(err)
+	}
+
+	// 送达回执
+	if data.Delivered {
+		return m.UpdateChatLogDelivered(ctx, &data)
 	}
 
 	// 业务处理 -- 更新
-	readRecords, err := m.UpdateChatLogRead(ctx, &data)
+	readRecords, deliveredRecords, readSeq, err := m.UpdateChatLogRead(ctx, &data)
 	if err != nil {
 		return err
 	}
+	if len(readRecords) == 0 && readSeq == 0 {
+		// 没有新的已读
+		return nil
+	}
 	// map[string]string
 	push := &ws.Push{
-		ConversationId: data.ConversationId,
-		ChatType:       data.ChatType,
-		SendId:         data.SendId,
-		RecvId:         data.RecvId,
-		ContentType:    constants.ContentMakeRead,
-		ReadRecords:    readRecords,
+		ConversationId:   data.ConversationId,
+		ChatType:         data.ChatType,
+		SendId:           data.SendId,
+		RecvId:           data.RecvId,
+		ContentType:      constants.ContentMakeRead,
+		ReadRecords:      readRecords,
+		DeliveredRecords: deliveredRecords,
+	}
+	if readSeq > 0 {
+		push.ReadCursors = map[string]int64{data.SendId: readSeq}
 	}
 
 	switch data.ChatType {
@@ -117,35 +134,177 @@ func (m *MsgReadTransfer) Consume(key, value string) error {
 	return nil
 }
 
-func (m *MsgReadTransfer) UpdateChatLogRead(ctx context.Context, data *mq.MsgMarkRead) (map[string]string, error) {
+// UpdateChatLogRead 记录已读
+// 有序号的消息只推进成员的已读游标，一次标记只需一次更新；没有序号的早期消息仍按消息记录已读
+// 返回早期消息的已读、送达记录，以及推进后的已读游标，游标没有前进时为 0
+func (m *MsgReadTransfer) UpdateChatLogRead(ctx context.Context, data *mq.MsgMarkRead) (map[string]string,
+	map[string]string, int64, error) {
 
 	res := make(map[string]string)
+	delivered := make(map[string]string)
 
-	chatLogs, err := m.svcCtx.ChatLogModel.ListByMsgIds(ctx, data.MsgIds)
-	if err != nil {
-		return nil, err
+	var chatLogs []*immodels.ChatLog
+	if len(data.MsgIds) > 0 {
+		var err error
+		chatLogs, err = m.svcCtx.ChatLogModel.ListByMsgIds(ctx, data.MsgIds)
+		if err != nil {
+			return nil, nil, 0, err
+		}
 	}
 
 	// 处理已读
+	var (
+		readSeq      = data.ReadSeq
+		readSendTime int64
+	)
 	for _, chatLog := range chatLogs {
-		switch chatLog.ChatType {
-		case constants.SingleChatType:
-			chatLog.ReadRecords = []byte{1}
-		case constants.GroupChatType:
-			readRecords := bitmap.Load(chatLog.ReadRecords)
-			readRecords.Set(data.SendId)
-			chatLog.ReadRecords = readRecords.Export()
+		if chatLog.ConversationId != data.ConversationId {
+			continue
+		}
+		if chatLog.SendTime > readSendTime {
+			readSendTime = chatLog.SendTime
 		}
+		if chatLog.Seq > 0 {
+			if chatLog.Seq > readSeq {
+				readSeq = chatLog.Seq
+			}
+			continue
+		}
+
+		// 已读的消息一定已送达
+		chatLog.ReadRecords = markReceipt(chatLog.ChatType, chatLog.ReadRecords, data.SendId)
+		chatLog.DeliveredRecords = markReceipt(chatLog.ChatType, chatLog.DeliveredRecords, data.SendId)
 
 		res[chatLog.ID.Hex()] = base64.StdEncoding.EncodeToString(chatLog.ReadRecords)
+		delivered[chatLog.ID.Hex()] = base64.StdEncoding.EncodeToString(chatLog.DeliveredRecords)
+
+		// 首次阅读后计时的阅后即焚消息，接收者第一次阅读时开始计时
+		if chatLog.DisappearTtl > 0 && chatLog.ExpireAt == 0 && chatLog.SendId != data.SendId {
+			expireAt := time.Now().UnixMilli() + chatLog.DisappearTtl*1000
+			if err := m.svcCtx.ChatLogModel.StartExpire(ctx, chatLog.ID, expireAt); err != nil {
+				m.Errorf("MsgReadTransfer StartExpire err %v, msgId %v", err, chatLog.ID.Hex())
+			}
+		}
+
+		err := m.svcCtx.ChatLogModel.UpdateReceipt(ctx, chatLog.ID, chatLog.DeliveredRecords, chatLog.ReadRecords)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	// 推进已读游标
+	if readSeq > 0 {
+		prevSeq, err := m.svcCtx.ReadCursorModel.Advance(ctx, data.ConversationId, data.SendId, readSeq)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if prevSeq >= readSeq {
+			readSeq = 0
+		} else {
+			m.readSeqAdvanced(ctx, data, prevSeq, readSeq, &readSendTime)
+		}
+	}
+
+	// 已读到@我的消息之后，清除"有人@我"标记
+	if readSendTime > 0 {
+		err := m.svcCtx.ConversationsModel.ClearMention(ctx, data.SendId, data.ConversationId, readSendTime)
+		if err != nil {
+			m.Errorf("MsgReadTransfer ClearMention err %v, userId %v", err, data.SendId)
+		}
+	}
+
+	return res, delivered, readSeq, nil
+}
+
+// readSeqAdvanced 已读游标从 prevSeq 推进到 readSeq 后，为新读到的消息开始阅后即焚计时
+// 只提供了 readSeq 时，查询该消息的发送时间用于清除"有人@我"标记
+func (m *MsgReadTransfer) readSeqAdvanced(ctx context.Context, data *mq.MsgMarkRead, prevSeq, readSeq int64,
+	readSendTime *int64) {
+	err := m.svcCtx.ChatLogModel.StartExpireBySeq(ctx, data.ConversationId, data.SendId, prevSeq, readSeq,
+		time.Now().UnixMilli())
+	if err != nil {
+		m.Errorf("MsgReadTransfer StartExpireBySeq err %v, conversationId %v", err, data.ConversationId)
+	}
 
-		err = m.svcCtx.ChatLogModel.UpdateMakeRead(ctx, chatLog.ID, chatLog.ReadRecords)
+	if readSeq != data.ReadSeq {
+		return
+	}
+	chatLog, err := m.svcCtx.ChatLogModel.FindOneBySeq(ctx, data.ConversationId, readSeq)
+	if err != nil {
+		if !errors.Is(err, immodels.ErrNotFound) {
+			m.Errorf("MsgReadTransfer FindOneBySeq err %v, seq %v", err, readSeq)
+		}
+		return
+	}
+	if chatLog.SendTime > *readSendTime {
+		*readSendTime = chatLog.SendTime
+	}
+}
+
+// UpdateChatLogDelivered 记录送达回执，并按消息的发送者分别推送
+// 发送者自己的消息和已经送达过的消息不会重复处理
+func (m *MsgReadTransfer) UpdateChatLogDelivered(ctx context.Context, data *mq.MsgMarkRead) error {
+	chatLogs, err := m.svcCtx.ChatLogModel.ListByMsgIds(ctx, data.MsgIds)
+	if err != nil {
+		return err
+	}
+
+	// 发送者 -> msgId -> 送达记录
+	senders := make(map[string]map[string]string)
+	for _, chatLog := range chatLogs {
+		if chatLog.ConversationId != data.ConversationId || chatLog.SendId == data.SendId {
+			continue
+		}
+		if isReceiptMarked(chatLog.ChatType, chatLog.DeliveredRecords, data.SendId) {
+			continue
+		}
+
+		chatLog.DeliveredRecords = markReceipt(chatLog.ChatType, chatLog.DeliveredRecords, data.SendId)
+		err = m.svcCtx.ChatLogModel.UpdateReceipt(ctx, chatLog.ID, chatLog.DeliveredRecords, nil)
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		if _, ok := senders[chatLog.SendId]; !ok {
+			senders[chatLog.SendId] = make(map[string]string)
+		}
+		senders[chatLog.SendId][chatLog.ID.Hex()] = base64.StdEncoding.EncodeToString(chatLog.DeliveredRecords)
+	}
+
+	// 送达回执只推送给消息的发送者，群聊也不会展开成员
+	for sendId, records := range senders {
+		push := &ws.Push{
+			ConversationId:   data.ConversationId,
+			ChatType:         data.ChatType,
+			SendId:           data.SendId,
+			RecvId:           sendId,
+			RecvIds:          []string{sendId},
+			ContentType:      constants.ContentDelivered,
+			DeliveredRecords: records,
+		}
+		if err := m.single(ctx, push); err != nil {
+			m.Errorf("MsgReadTransfer delivered push err %v, sendId %v", err, sendId)
 		}
 	}
 
-	return res, nil
+	return nil
+}
+
+// markReceipt 在送达或已读记录中标记用户，单聊只有一个接收者，直接标记为 1
+func markReceipt(chatType constants.ChatType, records []byte, userId string) []byte {
+	if chatType == constants.SingleChatType {
+		return []byte{1}
+	}
+	b := bitmap.Load(records)
+	b.Set(userId)
+	return b.Export()
+}
+
+func isReceiptMarked(chatType constants.ChatType, records []byte, userId string) bool {
+	if chatType == constants.SingleChatType {
+		return len(records) > 0 && records[0] != 0
+	}
+	return len(records) > 0 && bitmap.Load(records).IsSet(userId)
 }
 
 func (m *MsgReadTransfer) transfer() {
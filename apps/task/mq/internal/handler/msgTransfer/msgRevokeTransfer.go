@@ -8,34 +8,56 @@ import (
 
 	"imooc.com/easy-chat/apps/im/immodels"
 	"imooc.com/easy-chat/apps/im/ws/ws"
-	"imooc.com/easy-chat/apps/social/rpc/socialclient"
 	"imooc.com/easy-chat/apps/task/mq/internal/svc"
 	"imooc.com/easy-chat/apps/task/mq/mq"
 	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/job"
 )
 
-// 撤回时间窗口：2 分钟
-const RevokeTimeWindowMs = 120000
+// 默认撤回策略，群聊可以通过 Conversation.RevokePolicy 单独设置
+const (
+	// 发送者撤回自己消息的时间窗口：2 分钟
+	defaultRevokeSenderWindowMs int64 = 2 * 60 * 1000
+	// 群主和管理员撤回他人消息的时间窗口：2 分钟
+	defaultRevokeManagerWindowMs int64 = 2 * 60 * 1000
+)
 
 type MsgRevokeTransfer struct {
 	*baseMsgTransfer
+
+	// 默认撤回策略，时间窗口为毫秒
+	policy immodels.ConversationRevokePolicy
 }
 
 func NewMsgRevokeTransfer(svc *svc.ServiceContext) *MsgRevokeTransfer {
+	c := svc.Config.MsgRevokeHandler
+	policy := immodels.ConversationRevokePolicy{
+		SenderWindow:     defaultRevokeSenderWindowMs,
+		ManagerRevoke:    c.ManagerRevoke,
+		ManagerWindow:    defaultRevokeManagerWindowMs,
+		CreatorUnlimited: c.CreatorUnlimited,
+	}
+	if c.SenderWindow > 0 {
+		policy.SenderWindow = c.SenderWindow * 1000
+	}
+	if c.ManagerWindow > 0 {
+		policy.ManagerWindow = c.ManagerWindow * 1000
+	}
+
 	return &MsgRevokeTransfer{
-		NewBaseMsgTransfer(svc),
+		baseMsgTransfer: NewBaseMsgTransfer(svc),
+		policy:          policy,
 	}
 }
 
 func (m *MsgRevokeTransfer) Consume(key, value string) error {
-	fmt.Println("MsgRevokeTransfer key:", key, "value:", value)
+	return m.ConsumeContext(context.Background(), key, value)
+}
 
-	var (
-		data mq.MsgRevokeTransfer
-		ctx  = context.Background()
-	)
+func (m *MsgRevokeTransfer) ConsumeContext(ctx context.Context, key, value string) error {
+	var data mq.MsgRevokeTransfer
 	if err := json.Unmarshal([]byte(value), &data); err != nil {
-		return err
+		return job.Permanent(err)
 	}
 
 	// Step 1: 查询 ChatLog
@@ -44,36 +66,58 @@ func (m *MsgRevokeTransfer) Consume(key, value string) error {
 		m.Errorf("MsgRevokeTransfer FindOne err %v, msgId %v", err, data.MsgId)
 		return err
 	}
-	if chatLog.Status == 4 {
-		// 已经撤回，幂等处理
+	var role constants.GroupRoleLevel
+	switch {
+	case chatLog.Status == 4 && chatLog.RevokeBy != data.SendId:
+		// 已经被撤回，幂等处理
 		m.Infof("MsgRevokeTransfer msg already revoked, msgId %v", data.MsgId)
 		return nil
-	}
-
-	// Step 2: 权限校验
-	if !m.checkPermission(data.SendId, chatLog) {
-		// 推送权限不足通知给操作者
-		m.pushErrorToUser(data.SendId, data.ConversationId, chatLog.ChatType, "无权撤回他人消息")
-		return nil
-	}
+	case chatLog.Status == 4:
+		// 同一操作者已经撤回，是撤回后的步骤失败后的重试，从记录审计开始继续
+		m.Infof("MsgRevokeTransfer msg already revoked by %v, resume, msgId %v", data.SendId, data.MsgId)
+		if role, err = m.operatorRole(ctx, data.SendId, chatLog); err != nil {
+			return err
+		}
+	default:
+		// Step 2: 按撤回策略校验权限与时间
+		var errMsg string
+		role, errMsg = m.checkPermission(ctx, data.SendId, chatLog)
+		if errMsg != "" {
+			// 推送权限不足通知给操作者
+			m.pushErrorToUser(data.SendId, data.ConversationId, chatLog.ChatType, errMsg)
+			return nil
+		}
 
-	// Step 3: 时间校验
-	if time.Now().UnixMilli()-chatLog.SendTime > RevokeTimeWindowMs {
-		m.pushErrorToUser(data.SendId, data.ConversationId, chatLog.ChatType, "已超过2分钟无法撤回")
-		return nil
+		// Step 3: 执行撤回
+		chatLog.Status = 4 // 已撤回
+		chatLog.RevokeBy = data.SendId
+		if err = m.svcCtx.ChatLogModel.Update(ctx, chatLog); err != nil {
+			m.Errorf("MsgRevokeTransfer Update chatLog err %v", err)
+			return err
+		}
 	}
 
-	// Step 4: 执行撤回
-	chatLog.Status = 4 // 已撤回
-	if err = m.svcCtx.ChatLogModel.Update(ctx, chatLog); err != nil {
-		m.Errorf("MsgRevokeTransfer Update chatLog err %v", err)
-		return err
+	// Step 4: 撤回他人的消息记录审计，撤回成功后才记录
+	if data.SendId != chatLog.SendId {
+		if err := m.audit(ctx, data.SendId, role, chatLog); err != nil {
+			m.Errorf("MsgRevokeTransfer audit err %v, msgId %v", err, data.MsgId)
+			return err
+		}
 	}
 
 	// 如果撤回的是最后一条消息，更新会话摘要
 	m.updateLastMsgIfNeeded(ctx, chatLog)
 
+	// 撤回的消息自动取消置顶
+	if err := m.removePin(ctx, data.SendId, chatLog); err != nil {
+		m.Errorf("MsgRevokeTransfer removePin err %v, msgId %v", err, data.MsgId)
+	}
+
 	// Step 5: 推送撤回通知给全体参与者
+	content := fmt.Sprintf("%s撤回了一条消息", m.nickname(ctx, data.SendId))
+	if data.SendId == constants.SYSTEM_ROOT_UID {
+		content = "管理员撤回了一条违规消息"
+	}
 	push := &ws.Push{
 		ConversationId: chatLog.ConversationId,
 		ChatType:       chatLog.ChatType,
@@ -81,57 +125,131 @@ func (m *MsgRevokeTransfer) Consume(key, value string) error {
 		RecvId:         chatLog.RecvId,
 		MsgId:          data.MsgId,
 		ContentType:    constants.ContentRevoke,
-		Content:        fmt.Sprintf("%s撤回了一条消息", data.SendId),
+		Content:        content,
 	}
 
 	return m.Transfer(ctx, push)
 }
 
-// checkPermission 检查撤回权限
-// 返回 true 表示允许撤回
-func (m *MsgRevokeTransfer) checkPermission(userId string, chatLog *immodels.ChatLog) bool {
-	// 发送者本人可以撤回
-	if userId == chatLog.SendId {
-		return true
-	}
-
-	// 群聊中，群主和管理员也可以撤回
-	if chatLog.ChatType == constants.GroupChatType {
-		users, err := m.svcCtx.Social.GroupUsers(context.Background(), &socialclient.GroupUsersReq{
-			GroupId: chatLog.RecvId,
-		})
-		if err != nil {
-			m.Errorf("checkPermission GroupUsers err %v", err)
-			return false
-		}
-		for _, member := range users.List {
-			if member.UserId == userId {
-				roleLevel := constants.GroupRoleLevel(member.RoleLevel)
-				if roleLevel == constants.CreatorGroupRoleLevel ||
-					roleLevel == constants.ManagerGroupRoleLevel {
-					return true
-				}
-			}
+// checkPermission 按撤回策略检查撤回权限，返回操作者在群中的角色，不允许撤回时返回原因
+// 发送者在时间窗口内撤回自己的消息时不需要查询群成员
+func (m *MsgRevokeTransfer) checkPermission(ctx context.Context, userId string,
+	chatLog *immodels.ChatLog) (constants.GroupRoleLevel, string) {
+	// 系统撤回（如审核不通过的违规消息）不受撤回策略限制
+	if userId == constants.SYSTEM_ROOT_UID {
+		return 0, ""
+	}
+
+	policy := m.revokePolicy(ctx, chatLog)
+	elapsed := time.Now().UnixMilli() - chatLog.SendTime
+
+	isSender := userId == chatLog.SendId
+	if isSender && elapsed <= policy.SenderWindow {
+		return 0, ""
+	}
+
+	if chatLog.ChatType != constants.GroupChatType {
+		if isSender {
+			return 0, fmt.Sprintf("已超过%s无法撤回", formatWindow(policy.SenderWindow))
 		}
+		return 0, "无权撤回他人消息"
+	}
+
+	role, err := m.groupRole(ctx, chatLog.RecvId, userId)
+	if err != nil {
+		m.Errorf("checkPermission GroupUsers err %v", err)
+		return 0, "撤回失败，请稍后重试"
 	}
 
-	return false
+	// 群主不受时间限制
+	if role == constants.CreatorGroupRoleLevel && policy.CreatorUnlimited {
+		return role, ""
+	}
+	if isSender {
+		return role, fmt.Sprintf("已超过%s无法撤回", formatWindow(policy.SenderWindow))
+	}
+
+	if role != constants.CreatorGroupRoleLevel && role != constants.ManagerGroupRoleLevel || !policy.ManagerRevoke {
+		return role, "无权撤回他人消息"
+	}
+	if elapsed > policy.ManagerWindow {
+		return role, fmt.Sprintf("已超过%s无法撤回", formatWindow(policy.ManagerWindow))
+	}
+	return role, ""
 }
 
-// pushErrorToUser 推送错误消息给特定用户
-func (m *MsgRevokeTransfer) pushErrorToUser(userId, conversationId string, chatType constants.ChatType, errMsg string) {
-	m.Errorf("MsgRevokeTransfer revoke denied: userId=%s, err=%s", userId, errMsg)
-	// 只推送给操作者本人
-	push := &ws.Push{
-		ConversationId: conversationId,
-		ChatType:       chatType,
-		RecvId:         userId,
-		ContentType:    constants.ContentRevoke,
-		Content:        errMsg,
+// revokePolicy 获取消息所在会话的撤回策略，时间窗口统一转换为毫秒
+// 单聊和没有单独设置的群聊使用默认策略
+func (m *MsgRevokeTransfer) revokePolicy(ctx context.Context, chatLog *immodels.ChatLog) *immodels.ConversationRevokePolicy {
+	policy := m.policy
+	if chatLog.ChatType != constants.GroupChatType {
+		return &policy
+	}
+
+	conversation, err := m.svcCtx.ConversationModel.FindOne(ctx, chatLog.ConversationId)
+	if err != nil {
+		m.Errorf("revokePolicy FindOne err %v, conversationId %v", err, chatLog.ConversationId)
+		return &policy
+	}
+	if conversation.RevokePolicy == nil {
+		return &policy
+	}
+
+	custom := conversation.RevokePolicy
+	if custom.SenderWindow > 0 {
+		policy.SenderWindow = custom.SenderWindow * 1000
+	}
+	if custom.ManagerWindow > 0 {
+		policy.ManagerWindow = custom.ManagerWindow * 1000
 	}
-	if err := m.Transfer(context.Background(), push); err != nil {
-		m.Errorf("pushErrorToUser err %v", err)
+	policy.ManagerRevoke = custom.ManagerRevoke
+	policy.CreatorUnlimited = custom.CreatorUnlimited
+	return &policy
+}
+
+// operatorRole 查询撤回操作者在群中的角色，单聊与系统撤回为 0
+func (m *MsgRevokeTransfer) operatorRole(ctx context.Context, userId string,
+	chatLog *immodels.ChatLog) (constants.GroupRoleLevel, error) {
+	if chatLog.ChatType != constants.GroupChatType || userId == constants.SYSTEM_ROOT_UID {
+		return 0, nil
 	}
+	return m.groupRole(ctx, chatLog.RecvId, userId)
+}
+
+// audit 记录撤回他人消息的审计，同一操作者对同一条消息只记录一次
+func (m *MsgRevokeTransfer) audit(ctx context.Context, operatorId string, role constants.GroupRoleLevel,
+	chatLog *immodels.ChatLog) error {
+	return m.svcCtx.RevokeAuditModel.InsertIfNotExists(ctx, &immodels.RevokeAudit{
+		ConversationId: chatLog.ConversationId,
+		ChatType:       chatLog.ChatType,
+		MsgId:          chatLog.ID.Hex(),
+		OperatorId:     operatorId,
+		OperatorRole:   role,
+		SendId:         chatLog.SendId,
+		MsgType:        chatLog.MsgType,
+		MsgContent:     chatLog.MsgContent,
+		SendTime:       chatLog.SendTime,
+		RevokeTime:     time.Now().UnixMilli(),
+	})
+}
+
+// formatWindow 把时间窗口（毫秒）转换为提示文字，如"2分钟"
+func formatWindow(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%d小时", d/time.Hour)
+	case d >= time.Minute && d%time.Minute == 0:
+		return fmt.Sprintf("%d分钟", d/time.Minute)
+	default:
+		return fmt.Sprintf("%d秒", d/time.Second)
+	}
+}
+
+// pushErrorToUser 推送错误消息给操作者本人
+func (m *MsgRevokeTransfer) pushErrorToUser(userId, conversationId string, chatType constants.ChatType, errMsg string) {
+	m.Errorf("MsgRevokeTransfer revoke denied: userId=%s, err=%s", userId, errMsg)
+	m.pushToUser(context.Background(), userId, conversationId, chatType, constants.ContentRevoke, errMsg)
 }
 
 // updateLastMsgIfNeeded 如果撤回的是最后一条消息，更新会话摘要
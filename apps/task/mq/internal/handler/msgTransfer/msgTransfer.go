@@ -8,10 +8,11 @@ package msgTransfer
 import (
 	"context"
 	"github.com/zeromicro/go-zero/core/logx"
+	"imooc.com/easy-chat/apps/im/immodels"
 	"imooc.com/easy-chat/apps/im/ws/websocket"
 	"imooc.com/easy-chat/apps/im/ws/ws"
-	"imooc.com/easy-chat/apps/social/rpc/socialclient"
 	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/apps/user/rpc/userclient"
 	"imooc.com/easy-chat/pkg/constants"
 )
 
@@ -38,37 +39,112 @@ func (m *baseMsgTransfer) Transfer(ctx context.Context, data *ws.Push) error {
 	return err
 }
 
+// single 推送给接收者所在的网关节点
 func (m *baseMsgTransfer) single(ctx context.Context, data *ws.Push) error {
-	return m.svcCtx.WsClient.Send(websocket.Message{
-		FrameType: websocket.FrameData,
-		Method:    "push",
-		FormId:    constants.SYSTEM_ROOT_UID,
-		Data:      data,
+	return m.svcCtx.WsClient.SendBatch([]string{data.RecvId}, func([]string) any {
+		return newPushMessage(data)
 	})
 }
 
+// group 推送给群成员，成员按所在的网关节点分组，每个节点只发送一帧，帧中只包含该节点上的成员
 func (m *baseMsgTransfer) group(ctx context.Context, data *ws.Push) error {
-	// 就要查询，群的用户
-	users, err := m.svcCtx.Social.GroupUsers(ctx, &socialclient.GroupUsersReq{
-		GroupId: data.RecvId,
-	})
+	members, err := m.svcCtx.GroupMembers.Members(ctx, data.RecvId)
 	if err != nil {
 		return err
 	}
-	data.RecvIds = make([]string, 0, len(users.List))
+	data.RecvIds = make([]string, 0, len(members))
 
-	for _, members := range users.List {
-		if members.UserId == data.SendId {
+	for _, member := range members {
+		if member.UserId == data.SendId {
 			continue
 		}
 
-		data.RecvIds = append(data.RecvIds, members.UserId)
+		data.RecvIds = append(data.RecvIds, member.UserId)
 	}
 
-	return m.svcCtx.WsClient.Send(websocket.Message{
+	return m.svcCtx.WsClient.SendBatch(data.RecvIds, func(recvIds []string) any {
+		push := *data
+		push.RecvIds = recvIds
+		return newPushMessage(&push)
+	})
+}
+
+func newPushMessage(data *ws.Push) websocket.Message {
+	return websocket.Message{
 		FrameType: websocket.FrameData,
 		Method:    "push",
 		FormId:    constants.SYSTEM_ROOT_UID,
 		Data:      data,
+	}
+}
+
+// pushToParticipants 以系统用户推送给消息所在会话的全部参与者，包括发送者本人，用于消息内容的更新
+func (m *baseMsgTransfer) pushToParticipants(ctx context.Context, chatLog *immodels.ChatLog, data *ws.Push) error {
+	// 系统用户不是群成员，群聊的全部成员都会收到
+	data.SendId = constants.SYSTEM_ROOT_UID
+	switch chatLog.ChatType {
+	case constants.GroupChatType:
+		return m.group(ctx, data)
+	case constants.SingleChatType:
+		for _, userId := range []string{chatLog.SendId, chatLog.RecvId} {
+			push := *data
+			push.RecvId = userId
+			if err := m.single(ctx, &push); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pushToUser 只推送给特定用户，用于把处理结果（如权限不足）通知给操作者
+// 群聊也不会展开成员，避免错误通知发给全体群成员
+func (m *baseMsgTransfer) pushToUser(ctx context.Context, userId, conversationId string, chatType constants.ChatType,
+	contentType constants.ContentType, content string) {
+	push := &ws.Push{
+		ConversationId: conversationId,
+		ChatType:       chatType,
+		RecvId:         userId,
+		RecvIds:        []string{userId},
+		ContentType:    contentType,
+		Content:        content,
+	}
+	if err := m.single(ctx, push); err != nil {
+		m.Errorf("pushToUser err %v, userId %v", err, userId)
+	}
+}
+
+// isGroupManager 判断用户是否为群主或管理员
+func (m *baseMsgTransfer) isGroupManager(ctx context.Context, groupId, userId string) (bool, error) {
+	roleLevel, err := m.groupRole(ctx, groupId, userId)
+	if err != nil {
+		return false, err
+	}
+	return roleLevel == constants.CreatorGroupRoleLevel || roleLevel == constants.ManagerGroupRoleLevel, nil
+}
+
+// groupRole 查询用户在群中的角色，不是群成员时返回 0
+func (m *baseMsgTransfer) groupRole(ctx context.Context, groupId, userId string) (constants.GroupRoleLevel, error) {
+	roleLevel, err := m.svcCtx.GroupMembers.Role(ctx, groupId, userId)
+	if err != nil {
+		return 0, err
+	}
+	return constants.GroupRoleLevel(roleLevel), nil
+}
+
+// nickname 查询用户昵称，查询失败时返回用户ID
+func (m *baseMsgTransfer) nickname(ctx context.Context, userId string) string {
+	users, err := m.svcCtx.User.FindUser(ctx, &userclient.FindUserReq{
+		Ids: []string{userId},
 	})
+	if err != nil {
+		m.Errorf("nickname FindUser err %v, userId %v", err, userId)
+		return userId
+	}
+	for _, user := range users.User {
+		if user.Id == userId && user.Nickname != "" {
+			return user.Nickname
+		}
+	}
+	return userId
 }
@@ -0,0 +1,77 @@
+package msgTransfer
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/zeromicro/go-queue/kq"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/apps/task/mq/mqclient"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/mqx"
+)
+
+// TestPipeline_Memory 消息经 memory 后端投递给 MsgChatTransfer，入库后推送给网关，同一会话的消息按发送顺序分配序号与推送
+func TestPipeline_Memory(t *testing.T) {
+	const (
+		conversations = 10
+		perConv       = 50
+	)
+
+	var list []*immodels.Conversation
+	for conv := 0; conv < conversations; conv++ {
+		list = append(list, &immodels.Conversation{
+			ConversationId: "s_r" + strconv.Itoa(conv),
+			ChatType:       constants.SingleChatType,
+		})
+	}
+	s, svcCtx := newMemStore(list...)
+
+	c := mqx.Conf{Type: mqx.MemoryType}
+	consumer := mqx.MustNewConsumer(c, kq.KqConf{Topic: "pipeline-chat", Processors: 4},
+		NewMsgChatTransfer(svcCtx, nil, nil, nil))
+	go consumer.Start()
+	defer consumer.Stop()
+
+	client := mqclient.NewMsgChatTransferClient(mqx.NewProducer(c, nil, "pipeline-chat"))
+	for i := 0; i < perConv; i++ {
+		for conv := 0; conv < conversations; conv++ {
+			err := client.Push(&mq.MsgChatTransfer{
+				ConversationId: "s_r" + strconv.Itoa(conv),
+				ChatType:       constants.SingleChatType,
+				SendId:         "s",
+				RecvId:         "r" + strconv.Itoa(conv),
+				SendTime:       time.Now().UnixMilli(),
+				MType:          constants.TextMType,
+				Content:        strconv.Itoa(i),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	deadline := time.After(5 * time.Second)
+	for conv := 0; conv < conversations; conv++ {
+		recvId := "r" + strconv.Itoa(conv)
+		for len(s.client.received(recvId)) < perConv {
+			select {
+			case <-deadline:
+				t.Fatalf("timeout waiting for pushes to %v, got %d", recvId, len(s.client.received(recvId)))
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+
+		for i, push := range s.client.received(recvId) {
+			if push.Content != strconv.Itoa(i) || push.Seq != int64(i+1) {
+				t.Fatalf("%v push %d = content %v seq %v, want %d seq %d", recvId, i, push.Content, push.Seq, i, i+1)
+			}
+		}
+	}
+
+	if n := len(s.chatLogs.logs); n != conversations*perConv {
+		t.Errorf("persisted %d messages, want %d", n, conversations*perConv)
+	}
+}
@@ -0,0 +1,288 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/md5"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/apps/user/rpc/userclient"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/wuid"
+)
+
+const (
+	defaultAnnouncementInterval      = 2 * time.Second
+	defaultAnnouncementLease         = time.Minute
+	defaultAnnouncementBatchSize     = 200
+	defaultAnnouncementBatchInterval = time.Second
+	defaultAnnouncementMaxRetry      = 3
+)
+
+var errAnnouncementStopped = errors.New("announcement sender stopped")
+
+// AnnouncementSender 发送系统公告，以系统用户的身份把公告投递到每个接收者与系统用户的单聊会话中
+//
+// 接收者按用户ID排序后分批投递到 MsgChatTransfer，每批之间暂停 BatchInterval，
+// 避免全员公告挤占 kafka 与网关；在线用户实时收到推送，离线用户下次同步会话时拉取。
+// 每批完成后记录进度并续租，实例异常退出后由其他实例从进度处继续；
+// 聊天记录id由公告id与接收者生成，重复投递不会重复入库
+type AnnouncementSender struct {
+	svcCtx *svc.ServiceContext
+	logx.Logger
+
+	// 实例标识，用于领取公告
+	owner string
+
+	interval      time.Duration
+	lease         time.Duration
+	batchSize     int
+	batchInterval time.Duration
+	maxRetry      int
+
+	once sync.Once
+	done chan struct{}
+}
+
+func NewAnnouncementSender(svc *svc.ServiceContext) *AnnouncementSender {
+	s := &AnnouncementSender{
+		svcCtx:        svc,
+		Logger:        logx.WithContext(context.Background()),
+		owner:         primitive.NewObjectID().Hex(),
+		interval:      defaultAnnouncementInterval,
+		lease:         defaultAnnouncementLease,
+		batchSize:     defaultAnnouncementBatchSize,
+		batchInterval: defaultAnnouncementBatchInterval,
+		maxRetry:      defaultAnnouncementMaxRetry,
+		done:          make(chan struct{}),
+	}
+
+	c := svc.Config.Announcement
+	if c.Interval > 0 {
+		s.interval = time.Duration(c.Interval) * time.Second
+	}
+	if c.Lease > 0 {
+		s.lease = time.Duration(c.Lease) * time.Second
+	}
+	if c.BatchSize > 0 {
+		s.batchSize = c.BatchSize
+	}
+	if c.BatchInterval > 0 {
+		s.batchInterval = time.Duration(c.BatchInterval) * time.Millisecond
+	}
+	if c.MaxRetry > 0 {
+		s.maxRetry = c.MaxRetry
+	}
+	return s
+}
+
+func (s *AnnouncementSender) Start() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.dispatch(context.Background())
+		}
+	}
+}
+
+func (s *AnnouncementSender) Stop() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+// dispatch 依次领取并发送待发送的公告
+func (s *AnnouncementSender) dispatch(ctx context.Context) {
+	for {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		announcement, err := s.svcCtx.AnnouncementModel.Claim(ctx, s.owner, time.Now().UnixMilli(),
+			s.lease.Milliseconds())
+		if err != nil {
+			if !errors.Is(err, immodels.ErrNotFound) {
+				s.Errorf("AnnouncementSender Claim err %v", err)
+			}
+			return
+		}
+
+		s.send(ctx, announcement)
+	}
+}
+
+func (s *AnnouncementSender) send(ctx context.Context, announcement *immodels.Announcement) {
+	err := s.sendBatches(ctx, announcement)
+	switch {
+	case err == nil:
+		if err := s.svcCtx.AnnouncementModel.MarkDone(ctx, announcement.ID, s.owner); err != nil {
+			s.Errorf("AnnouncementSender MarkDone err %v, id %v", err, announcement.ID.Hex())
+		}
+	case errors.Is(err, errAnnouncementStopped), errors.Is(err, immodels.ErrNotFound):
+		// 实例退出或租期已过被其他实例领取，由其他实例从进度处继续
+		s.Infof("AnnouncementSender interrupted %v, id %v", err, announcement.ID.Hex())
+	default:
+		failed := announcement.RetryCount+1 >= s.maxRetry
+		s.Errorf("AnnouncementSender send err %v, id %v, failed %v", err, announcement.ID.Hex(), failed)
+		if err := s.svcCtx.AnnouncementModel.Release(ctx, announcement.ID, s.owner, failed, err.Error()); err != nil {
+			s.Errorf("AnnouncementSender Release err %v, id %v", err, announcement.ID.Hex())
+		}
+	}
+}
+
+// sendBatches 从公告的进度处开始分批投递，全部投递完成时返回 nil
+func (s *AnnouncementSender) sendBatches(ctx context.Context, announcement *immodels.Announcement) error {
+	cursor := announcement.Cursor
+	for {
+		userIds, err := s.recipients(ctx, announcement, cursor)
+		if err != nil {
+			return err
+		}
+		if len(userIds) == 0 {
+			return nil
+		}
+
+		sendTime := time.Now().UnixMilli()
+		for _, userId := range userIds {
+			if err := s.deliver(ctx, announcement, userId, sendTime); err != nil {
+				return err
+			}
+		}
+
+		cursor = userIds[len(userIds)-1]
+		err = s.svcCtx.AnnouncementModel.Progress(ctx, announcement.ID, s.owner, cursor, int64(len(userIds)),
+			time.Now().UnixMilli())
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-s.done:
+			return errAnnouncementStopped
+		case <-time.After(s.batchInterval):
+		}
+	}
+}
+
+// recipients 返回用户ID大于 cursor 的下一批接收者，按用户ID升序
+func (s *AnnouncementSender) recipients(ctx context.Context, announcement *immodels.Announcement,
+	cursor string) ([]string, error) {
+	var userIds []string
+
+	switch announcement.Target {
+	case immodels.AnnouncementToAll, immodels.AnnouncementToRegistered:
+		resp, err := s.svcCtx.User.ListUserIds(ctx, &userclient.ListUserIdsReq{
+			AfterId:       cursor,
+			RegisterStart: announcement.RegisterStart,
+			RegisterEnd:   announcement.RegisterEnd,
+			Limit:         int64(s.batchSize),
+		})
+		if err != nil {
+			return nil, err
+		}
+		userIds = resp.Ids
+	case immodels.AnnouncementToUsers:
+		userIds = nextBatch(announcement.UserIds, cursor, s.batchSize)
+	case immodels.AnnouncementToGroup:
+		groupMembers, err := s.svcCtx.GroupMembers.Members(ctx, announcement.GroupId)
+		if err != nil {
+			return nil, err
+		}
+		members := make([]string, 0, len(groupMembers))
+		for _, member := range groupMembers {
+			members = append(members, member.UserId)
+		}
+		userIds = nextBatch(members, cursor, s.batchSize)
+	}
+
+	// 系统用户本身不接收公告
+	res := make([]string, 0, len(userIds))
+	for _, userId := range userIds {
+		if userId != constants.SYSTEM_ROOT_UID {
+			res = append(res, userId)
+		}
+	}
+	if len(res) == 0 && len(userIds) > 0 {
+		// 本批只有系统用户，继续下一批
+		return s.recipients(ctx, announcement, userIds[len(userIds)-1])
+	}
+	return res, nil
+}
+
+// nextBatch 对用户ID去重排序后，返回大于 cursor 的下一批
+func nextBatch(userIds []string, cursor string, batchSize int) []string {
+	sorted := append([]string(nil), userIds...)
+	sort.Strings(sorted)
+
+	res := make([]string, 0, batchSize)
+	for i, userId := range sorted {
+		if userId <= cursor || (i > 0 && userId == sorted[i-1]) {
+			continue
+		}
+		res = append(res, userId)
+		if len(res) >= batchSize {
+			break
+		}
+	}
+	return res
+}
+
+// deliver 确保接收者有系统会话，并把公告作为系统用户的单聊消息投递
+func (s *AnnouncementSender) deliver(ctx context.Context, announcement *immodels.Announcement, userId string,
+	sendTime int64) error {
+	conversationId := wuid.CombineId(constants.SYSTEM_ROOT_UID, userId)
+
+	err := s.svcCtx.ConversationModel.InsertIfNotExists(ctx, &immodels.Conversation{
+		ConversationId: conversationId,
+		ChatType:       constants.SingleChatType,
+		TargetId:       userId,
+		IsShow:         true,
+	})
+	if err != nil {
+		return err
+	}
+	err = s.svcCtx.ConversationsModel.AddIfNotExists(ctx, userId, &immodels.Conversation{
+		ConversationId: conversationId,
+		ChatType:       constants.SingleChatType,
+		TargetId:       constants.SYSTEM_ROOT_UID,
+		IsShow:         true,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.svcCtx.MsgChatTransferClient.Push(&mq.MsgChatTransfer{
+		ConversationId: conversationId,
+		ChatType:       constants.SingleChatType,
+		SendId:         constants.SYSTEM_ROOT_UID,
+		RecvId:         userId,
+		SendTime:       sendTime,
+		MType:          announcement.MsgType,
+		Content:        announcement.MsgContent,
+		ChatLogId:      announcementChatLogId(announcement.ID, userId),
+	})
+}
+
+// announcementChatLogId 由公告id与接收者生成聊天记录id，保留公告id中的时间部分
+func announcementChatLogId(announcementId primitive.ObjectID, userId string) string {
+	sum := md5.Sum(append(announcementId[:], userId...))
+
+	var id primitive.ObjectID
+	copy(id[:4], announcementId[:4])
+	copy(id[4:], sum[:8])
+	return id.Hex()
+}
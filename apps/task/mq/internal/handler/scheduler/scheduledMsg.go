@@ -0,0 +1,140 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"imooc.com/easy-chat/apps/im/immodels"
+	"imooc.com/easy-chat/apps/task/mq/internal/svc"
+	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/pkg/job"
+)
+
+const (
+	defaultScheduledMsgInterval  = time.Second
+	defaultScheduledMsgLease     = 30 * time.Second
+	defaultScheduledMsgBatchSize = 100
+	defaultScheduledMsgMaxRetry  = 5
+)
+
+// ScheduledMsg 定时消息调度器，把到期的定时消息投递到 MsgChatTransfer 按普通消息处理
+//
+// 多个 task 实例同时运行时，通过 ScheduledMessageModel.Claim 原子领取，同一条消息只会被一个实例发送；
+// 投递时以定时消息的id作为聊天记录id，即使领取者在投递后异常退出导致重新投递，也不会重复入库
+type ScheduledMsg struct {
+	svcCtx *svc.ServiceContext
+	logx.Logger
+
+	// 实例标识，用于领取消息
+	owner string
+
+	interval  time.Duration
+	lease     time.Duration
+	batchSize int
+	maxRetry  int
+
+	once sync.Once
+	done chan struct{}
+}
+
+func NewScheduledMsg(svc *svc.ServiceContext) *ScheduledMsg {
+	s := &ScheduledMsg{
+		svcCtx:    svc,
+		Logger:    logx.WithContext(context.Background()),
+		owner:     primitive.NewObjectID().Hex(),
+		interval:  defaultScheduledMsgInterval,
+		lease:     defaultScheduledMsgLease,
+		batchSize: defaultScheduledMsgBatchSize,
+		maxRetry:  defaultScheduledMsgMaxRetry,
+		done:      make(chan struct{}),
+	}
+
+	c := svc.Config.ScheduledMsg
+	if c.Interval > 0 {
+		s.interval = time.Duration(c.Interval) * time.Second
+	}
+	if c.Lease > 0 {
+		s.lease = time.Duration(c.Lease) * time.Second
+	}
+	if c.BatchSize > 0 {
+		s.batchSize = c.BatchSize
+	}
+	if c.MaxRetry > 0 {
+		s.maxRetry = c.MaxRetry
+	}
+	return s
+}
+
+func (s *ScheduledMsg) Start() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.dispatch(context.Background())
+		}
+	}
+}
+
+func (s *ScheduledMsg) Stop() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+// dispatch 领取并发送到期的定时消息
+func (s *ScheduledMsg) dispatch(ctx context.Context) {
+	for i := 0; i < s.batchSize; i++ {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		msg, err := s.svcCtx.ScheduledMessageModel.Claim(ctx, s.owner, time.Now().UnixMilli(), s.lease.Milliseconds())
+		if err != nil {
+			if !errors.Is(err, immodels.ErrNotFound) {
+				s.Errorf("ScheduledMsg Claim err %v", err)
+			}
+			return
+		}
+
+		s.send(ctx, msg)
+	}
+}
+
+func (s *ScheduledMsg) send(ctx context.Context, msg *immodels.ScheduledMessage) {
+	err := job.WithRetry(ctx, func(ctx context.Context) error {
+		return s.svcCtx.MsgChatTransferClient.Push(&mq.MsgChatTransfer{
+			ConversationId: msg.ConversationId,
+			ChatType:       msg.ChatType,
+			SendId:         msg.SendId,
+			RecvId:         msg.RecvId,
+			SendTime:       time.Now().UnixMilli(),
+			MType:          msg.MsgType,
+			Content:        msg.MsgContent,
+			ChatLogId:      msg.ID.Hex(),
+		})
+	}, job.WithRetryNums(3), job.WithRetryTimeout(s.lease/2))
+
+	if err != nil {
+		failed := msg.RetryCount+1 >= s.maxRetry
+		s.Errorf("ScheduledMsg push err %v, id %v, failed %v", err, msg.ID.Hex(), failed)
+		if err := s.svcCtx.ScheduledMessageModel.Release(ctx, msg.ID, s.owner, failed, err.Error()); err != nil {
+			s.Errorf("ScheduledMsg Release err %v, id %v", err, msg.ID.Hex())
+		}
+		return
+	}
+
+	if err := s.svcCtx.ScheduledMessageModel.MarkSent(ctx, msg.ID, s.owner); err != nil {
+		// 租期到期后会被重新投递，chatLogId 保证不会重复入库
+		s.Errorf("ScheduledMsg MarkSent err %v, id %v", err, msg.ID.Hex())
+	}
+}
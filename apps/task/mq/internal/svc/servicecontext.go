@@ -6,13 +6,20 @@
 package svc
 
 import (
+	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/core/stores/redis"
 	"github.com/zeromicro/go-zero/zrpc"
 	"imooc.com/easy-chat/apps/im/immodels"
 	"imooc.com/easy-chat/apps/im/ws/websocket"
 	"imooc.com/easy-chat/apps/social/rpc/socialclient"
 	"imooc.com/easy-chat/apps/task/mq/internal/config"
+	"imooc.com/easy-chat/apps/task/mq/mqclient"
+	"imooc.com/easy-chat/apps/user/rpc/userclient"
+	"imooc.com/easy-chat/pkg/configserver"
 	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/membership"
+	"imooc.com/easy-chat/pkg/moderation"
+	"imooc.com/easy-chat/pkg/mqx"
 	"net/http"
 )
 
@@ -23,18 +30,63 @@ type ServiceContext struct {
 	*redis.Redis
 
 	socialclient.Social
+	userclient.User
 	immodels.ChatLogModel
 	immodels.ConversationModel
+	immodels.ConversationsModel
+	immodels.UserMessageDeletesModel
+	immodels.ScheduledMessageModel
+	immodels.ReadCursorModel
+	immodels.RevokeAuditModel
+	immodels.ExportJobModel
+	immodels.ModerationReviewModel
+	immodels.AnnouncementModel
+	immodels.BotModel
+	immodels.DeadLetterModel
+
+	Moderation   *moderation.Dictionary
+	GroupMembers *membership.Cache
+
+	MsgChatTransferClient mqclient.MsgChatTransferClient
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
 	svc := &ServiceContext{
-		Config:            c,
-		Redis:             redis.MustNewRedis(c.Redisx),
-		ChatLogModel:      immodels.MustChatLogModel(c.Mongo.Url, c.Mongo.Db),
-		ConversationModel: immodels.MustConversationModel(c.Mongo.Url, c.Mongo.Db),
+		Config:                  c,
+		Redis:                   redis.MustNewRedis(c.Redisx),
+		ChatLogModel:            immodels.MustChatLogModel(c.Mongo.Url, c.Mongo.Db),
+		ConversationModel:       immodels.MustConversationModel(c.Mongo.Url, c.Mongo.Db),
+		ConversationsModel:      immodels.MustConversationsModel(c.Mongo.Url, c.Mongo.Db),
+		UserMessageDeletesModel: immodels.MustUserMessageDeletesModel(c.Mongo.Url, c.Mongo.Db),
+		ScheduledMessageModel:   immodels.MustScheduledMessageModel(c.Mongo.Url, c.Mongo.Db),
+		ReadCursorModel:         immodels.MustReadCursorModel(c.Mongo.Url, c.Mongo.Db),
+		RevokeAuditModel:        immodels.MustRevokeAuditModel(c.Mongo.Url, c.Mongo.Db),
+		ExportJobModel:          immodels.MustExportJobModel(c.Mongo.Url, c.Mongo.Db),
+		ModerationReviewModel:   immodels.MustModerationReviewModel(c.Mongo.Url, c.Mongo.Db),
+		AnnouncementModel:       immodels.MustAnnouncementModel(c.Mongo.Url, c.Mongo.Db),
+		BotModel:                immodels.MustBotModel(c.Mongo.Url, c.Mongo.Db),
+		DeadLetterModel:         immodels.MustDeadLetterModel(c.Mongo.Url, c.Mongo.Db),
+		Moderation:              moderation.NewDictionary(c.Moderation.Rules),
+		MsgChatTransferClient: mqclient.NewMsgChatTransferClient(
+			mqx.NewProducer(c.Queue, c.MsgChatTransfer.Brokers, c.MsgChatTransfer.Topic)),
 
 		Social: socialclient.NewSocial(zrpc.MustNewClient(c.SocialRpc)),
+		User:   userclient.NewUser(zrpc.MustNewClient(c.UserRpc)),
+	}
+	svc.GroupMembers = membership.NewCache(socialclient.NewMembershipLoader(svc.Social),
+		membership.WithRedis(svc.Redis), membership.WithConf(c.GroupMembers))
+	svc.GroupMembers.Watch(c.Redisx)
+
+	if c.Moderation.DataId != "" {
+		err := svc.Moderation.Watch(configserver.NewNacos(&configserver.NacosConfig{
+			Addr:      c.Moderation.Addr,
+			Namespace: c.Moderation.Namespace,
+			Group:     c.Moderation.Group,
+			DataId:    c.Moderation.DataId,
+		}))
+		if err != nil {
+			logx.Errorf("moderation dictionary watch err %v, use local rules", err)
+		}
 	}
 
 	token, err := svc.GetSystemToken()
@@ -18,7 +18,22 @@ type MsgChatTransfer struct {
 	SendTime           int64    `json:"sendTime"`
 
 	constants.MType `json:"mType"`
-	Content         string `json:"content"`
+	Content         string   `json:"content"`
+	QuoteMsgId      string   `json:"quoteMsgId"` // 引用的消息
+	Mentions        []string `json:"mentions"`   // 被@的用户
+
+	Forward *ChatForward `json:"forward,omitempty"` // 转发的来源，不为空时消息内容由原消息生成
+
+	ChatLogId string `json:"chatLogId,omitempty"` // 指定聊天记录的id，重复投递时不会重复入库
+
+	MsgFrom constants.MsgFrom `json:"msgFrom,omitempty"` // 消息来源，机器人消息为 constants.BotMsgFrom
+}
+
+type ChatForward struct {
+	ConversationId string   `json:"conversationId"`
+	MsgIds         []string `json:"msgIds"`
+	Merge          bool     `json:"merge"`
+	Title          string   `json:"title"`
 }
 
 type MsgMarkRead struct {
@@ -27,6 +42,8 @@ type MsgMarkRead struct {
 	SendId             string   `json:"sendId"`
 	RecvId             string   `json:"recvId"`
 	MsgIds             []string `json:"msgIds"`
+	ReadSeq            int64    `json:"readSeq,omitempty"`   // 已读到的消息序号，与 MsgIds 可同时使用
+	Delivered          bool     `json:"delivered,omitempty"` // true 表示送达回执，否则为已读回执
 }
 
 type MsgRevokeTransfer struct {
@@ -35,3 +52,53 @@ type MsgRevokeTransfer struct {
 	ChatType       int32  `json:"chatType"`
 	SendId         string `json:"sendId"` // 发起撤回的人
 }
+
+type MsgEditTransfer struct {
+	MsgId          string `json:"msgId"`
+	ConversationId string `json:"conversationId"`
+	SendId         string `json:"sendId"` // 发起编辑的人
+	Content        string `json:"content"`
+	EditTime       int64  `json:"editTime"`
+}
+
+type MsgReactionTransfer struct {
+	MsgId          string `json:"msgId"`
+	ConversationId string `json:"conversationId"`
+	SendId         string `json:"sendId"` // 发起回应的人
+	Emoji          string `json:"emoji"`
+	Unreact        bool   `json:"unreact"` // true 表示取消回应
+}
+
+type MsgPinTransfer struct {
+	MsgId          string `json:"msgId"`
+	ConversationId string `json:"conversationId"`
+	SendId         string `json:"sendId"` // 发起置顶的人
+	Unpin          bool   `json:"unpin"`  // true 表示取消置顶
+	PinTime        int64  `json:"pinTime"`
+}
+
+type MsgCardActionTransfer struct {
+	MsgId          string `json:"msgId"`
+	ConversationId string `json:"conversationId"`
+	SendId         string `json:"sendId"` // 点击按钮的人
+	ButtonId       string `json:"buttonId"`
+	ActionTime     int64  `json:"actionTime"`
+}
+
+// DeadLetterSuffix 死信 topic 的后缀，每个 topic 对应一个死信 topic
+const DeadLetterSuffix = "-dlq"
+
+func DeadLetterTopic(topic string) string {
+	return topic + DeadLetterSuffix
+}
+
+// DeadLetter 重试后仍然处理失败的消息，保留原消息与失败原因，可以重放到原 topic
+type DeadLetter struct {
+	Topic     string `json:"topic"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	Error     string `json:"error"`
+	Attempts  int    `json:"attempts"`
+	Permanent bool   `json:"permanent"` // 是否为无法通过重试恢复的错误，如消息格式错误
+	FailTime  int64  `json:"failTime"`
+}
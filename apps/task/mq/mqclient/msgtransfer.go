@@ -8,21 +8,23 @@ package mqclient
 import (
 	"encoding/json"
 
-	"github.com/zeromicro/go-queue/kq"
 	"imooc.com/easy-chat/apps/task/mq/mq"
+	"imooc.com/easy-chat/pkg/mqx"
 )
 
+// 全部消息以 conversationId 作为 key，同一会话的消息按发送的顺序处理，后端由 mqx.Conf 选择
+
 type MsgChatTransferClient interface {
 	Push(msg *mq.MsgChatTransfer) error
 }
 
 type msgChatTransferClient struct {
-	pusher *kq.Pusher
+	producer mqx.Producer
 }
 
-func NewMsgChatTransferClient(addr []string, topic string, opts ...kq.PushOption) MsgChatTransferClient {
+func NewMsgChatTransferClient(producer mqx.Producer) MsgChatTransferClient {
 	return &msgChatTransferClient{
-		pusher: kq.NewPusher(addr, topic),
+		producer: producer,
 	}
 }
 
@@ -32,7 +34,7 @@ func (c *msgChatTransferClient) Push(msg *mq.MsgChatTransfer) error {
 		return err
 	}
 
-	return c.pusher.Push(string(body))
+	return c.producer.Push(msg.ConversationId, string(body))
 }
 
 type MsgReadTransferClient interface {
@@ -40,12 +42,12 @@ type MsgReadTransferClient interface {
 }
 
 type msgReadTransferClient struct {
-	pusher *kq.Pusher
+	producer mqx.Producer
 }
 
-func NewMsgReadTransferClient(addr []string, topic string, opts ...kq.PushOption) MsgReadTransferClient {
+func NewMsgReadTransferClient(producer mqx.Producer) MsgReadTransferClient {
 	return &msgReadTransferClient{
-		pusher: kq.NewPusher(addr, topic),
+		producer: producer,
 	}
 }
 
@@ -55,7 +57,7 @@ func (c *msgReadTransferClient) Push(msg *mq.MsgMarkRead) error {
 		return err
 	}
 
-	return c.pusher.Push(string(body))
+	return c.producer.Push(msg.ConversationId, string(body))
 }
 
 type MsgRevokeTransferClient interface {
@@ -63,12 +65,12 @@ type MsgRevokeTransferClient interface {
 }
 
 type msgRevokeTransferClient struct {
-	pusher *kq.Pusher
+	producer mqx.Producer
 }
 
-func NewMsgRevokeTransferClient(addr []string, topic string, opts ...kq.PushOption) MsgRevokeTransferClient {
+func NewMsgRevokeTransferClient(producer mqx.Producer) MsgRevokeTransferClient {
 	return &msgRevokeTransferClient{
-		pusher: kq.NewPusher(addr, topic),
+		producer: producer,
 	}
 }
 
@@ -78,5 +80,97 @@ func (c *msgRevokeTransferClient) Push(msg *mq.MsgRevokeTransfer) error {
 		return err
 	}
 
-	return c.pusher.Push(string(body))
+	return c.producer.Push(msg.ConversationId, string(body))
+}
+
+type MsgEditTransferClient interface {
+	Push(msg *mq.MsgEditTransfer) error
+}
+
+type msgEditTransferClient struct {
+	producer mqx.Producer
+}
+
+func NewMsgEditTransferClient(producer mqx.Producer) MsgEditTransferClient {
+	return &msgEditTransferClient{
+		producer: producer,
+	}
+}
+
+func (c *msgEditTransferClient) Push(msg *mq.MsgEditTransfer) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return c.producer.Push(msg.ConversationId, string(body))
+}
+
+type MsgReactionTransferClient interface {
+	Push(msg *mq.MsgReactionTransfer) error
+}
+
+type msgReactionTransferClient struct {
+	producer mqx.Producer
+}
+
+func NewMsgReactionTransferClient(producer mqx.Producer) MsgReactionTransferClient {
+	return &msgReactionTransferClient{
+		producer: producer,
+	}
+}
+
+func (c *msgReactionTransferClient) Push(msg *mq.MsgReactionTransfer) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return c.producer.Push(msg.ConversationId, string(body))
+}
+
+type MsgPinTransferClient interface {
+	Push(msg *mq.MsgPinTransfer) error
+}
+
+type msgPinTransferClient struct {
+	producer mqx.Producer
+}
+
+func NewMsgPinTransferClient(producer mqx.Producer) MsgPinTransferClient {
+	return &msgPinTransferClient{
+		producer: producer,
+	}
+}
+
+func (c *msgPinTransferClient) Push(msg *mq.MsgPinTransfer) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return c.producer.Push(msg.ConversationId, string(body))
+}
+
+type MsgCardActionTransferClient interface {
+	Push(msg *mq.MsgCardActionTransfer) error
+}
+
+type msgCardActionTransferClient struct {
+	producer mqx.Producer
+}
+
+func NewMsgCardActionTransferClient(producer mqx.Producer) MsgCardActionTransferClient {
+	return &msgCardActionTransferClient{
+		producer: producer,
+	}
+}
+
+func (c *msgCardActionTransferClient) Push(msg *mq.MsgCardActionTransfer) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return c.producer.Push(msg.ConversationId, string(body))
 }
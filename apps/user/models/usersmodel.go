@@ -1,6 +1,10 @@
 package models
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/zeromicro/go-zero/core/stores/cache"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
@@ -12,6 +16,7 @@ type (
 	// and implement the added methods in customUsersModel.
 	UsersModel interface {
 		usersModel
+		ListIds(ctx context.Context, afterId string, registerStart, registerEnd time.Time, limit int64) ([]string, error)
 	}
 
 	customUsersModel struct {
@@ -25,3 +30,29 @@ func NewUsersModel(conn sqlx.SqlConn, c cache.CacheConf) UsersModel {
 		defaultUsersModel: newUsersModel(conn, c),
 	}
 }
+
+// ListIds 按用户ID升序分页查询用户ID，registerStart 与 registerEnd 为零值时不限制注册时间
+func (m *customUsersModel) ListIds(ctx context.Context, afterId string, registerStart, registerEnd time.Time,
+	limit int64) ([]string, error) {
+	query := fmt.Sprintf("select `id` from %s where `id` > ?", m.table)
+	args := []any{afterId}
+	if !registerStart.IsZero() {
+		query += " and `created_at` >= ?"
+		args = append(args, registerStart)
+	}
+	if !registerEnd.IsZero() {
+		query += " and `created_at` < ?"
+		args = append(args, registerEnd)
+	}
+	query += " order by `id` limit ?"
+	args = append(args, limit)
+
+	var resp []string
+	err := m.QueryRowsNoCacheCtx(ctx, &resp, query, args...)
+	switch err {
+	case nil:
+		return resp, nil
+	default:
+		return nil, err
+	}
+}
@@ -0,0 +1,48 @@
+package logic
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+	"github.com/zeromicro/go-zero/core/logx"
+	"imooc.com/easy-chat/apps/user/models"
+	"imooc.com/easy-chat/apps/user/rpc/internal/svc"
+	"imooc.com/easy-chat/apps/user/rpc/user"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/wuid"
+	"imooc.com/easy-chat/pkg/xerr"
+)
+
+type CreateBotLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewCreateBotLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateBotLogic {
+	return &CreateBotLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+func (l *CreateBotLogic) CreateBot(in *user.CreateBotReq) (*user.CreateBotResp, error) {
+	// 机器人没有手机号与密码，无法通过登录获取 token
+	bot := &models.Users{
+		Id:       wuid.GenUid(l.svcCtx.Config.Mysql.DataSource),
+		Avatar:   in.Avatar,
+		Nickname: in.Nickname,
+		Status: sql.NullInt64{
+			Int64: constants.BotUserStatus,
+			Valid: true,
+		},
+	}
+
+	if _, err := l.svcCtx.UsersModel.Insert(l.ctx, bot); err != nil {
+		return nil, errors.Wrapf(xerr.NewDBErr(), "insert bot err %v, req %v", err, in)
+	}
+
+	return &user.CreateBotResp{Id: bot.Id}, nil
+}
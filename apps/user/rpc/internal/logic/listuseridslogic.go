@@ -0,0 +1,56 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/zeromicro/go-zero/core/logx"
+	"imooc.com/easy-chat/apps/user/rpc/internal/svc"
+	"imooc.com/easy-chat/apps/user/rpc/user"
+	"imooc.com/easy-chat/pkg/xerr"
+)
+
+const (
+	defaultListUserIdsLimit = 500
+	maxListUserIdsLimit     = 1000
+)
+
+type ListUserIdsLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewListUserIdsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListUserIdsLogic {
+	return &ListUserIdsLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+func (l *ListUserIdsLogic) ListUserIds(in *user.ListUserIdsReq) (*user.ListUserIdsResp, error) {
+	limit := in.Limit
+	if limit <= 0 {
+		limit = defaultListUserIdsLimit
+	}
+	if limit > maxListUserIdsLimit {
+		limit = maxListUserIdsLimit
+	}
+
+	var registerStart, registerEnd time.Time
+	if in.RegisterStart > 0 {
+		registerStart = time.UnixMilli(in.RegisterStart)
+	}
+	if in.RegisterEnd > 0 {
+		registerEnd = time.UnixMilli(in.RegisterEnd)
+	}
+
+	ids, err := l.svcCtx.UsersModel.ListIds(l.ctx, in.AfterId, registerStart, registerEnd, limit)
+	if err != nil {
+		return nil, errors.Wrapf(xerr.NewDBErr(), "ListIds err %v, req %v", err, in)
+	}
+
+	return &user.ListUserIdsResp{Ids: ids}, nil
+}
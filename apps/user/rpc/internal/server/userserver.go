@@ -46,3 +46,13 @@ func (s *UserServer) FindUser(ctx context.Context, in *user.FindUserReq) (*user.
 	l := logic.NewFindUserLogic(ctx, s.svcCtx)
 	return l.FindUser(in)
 }
+
+func (s *UserServer) ListUserIds(ctx context.Context, in *user.ListUserIdsReq) (*user.ListUserIdsResp, error) {
+	l := logic.NewListUserIdsLogic(ctx, s.svcCtx)
+	return l.ListUserIds(in)
+}
+
+func (s *UserServer) CreateBot(ctx context.Context, in *user.CreateBotReq) (*user.CreateBotResp, error) {
+	l := logic.NewCreateBotLogic(ctx, s.svcCtx)
+	return l.CreateBot(in)
+}
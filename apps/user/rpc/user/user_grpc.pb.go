@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
 // versions:
-// - protoc-gen-go-grpc v1.2.0
-// - protoc             v3.21.8
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.19.4
 // source: apps/user/rpc/user.proto
 
 package user
@@ -18,6 +18,16 @@ import (
 // Requires gRPC-Go v1.32.0 or later.
 const _ = grpc.SupportPackageIsVersion7
 
+const (
+	User_Ping_FullMethodName        = "/user.User/Ping"
+	User_Login_FullMethodName       = "/user.User/Login"
+	User_Register_FullMethodName    = "/user.User/Register"
+	User_GetUserInfo_FullMethodName = "/user.User/GetUserInfo"
+	User_FindUser_FullMethodName    = "/user.User/FindUser"
+	User_ListUserIds_FullMethodName = "/user.User/ListUserIds"
+	User_CreateBot_FullMethodName   = "/user.User/CreateBot"
+)
+
 // UserClient is the client API for User service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
@@ -27,6 +37,8 @@ type UserClient interface {
 	Register(ctx context.Context, in *RegisterReq, opts ...grpc.CallOption) (*RegisterResp, error)
 	GetUserInfo(ctx context.Context, in *GetUserInfoReq, opts ...grpc.CallOption) (*GetUserInfoResp, error)
 	FindUser(ctx context.Context, in *FindUserReq, opts ...grpc.CallOption) (*FindUserResp, error)
+	ListUserIds(ctx context.Context, in *ListUserIdsReq, opts ...grpc.CallOption) (*ListUserIdsResp, error)
+	CreateBot(ctx context.Context, in *CreateBotReq, opts ...grpc.CallOption) (*CreateBotResp, error)
 }
 
 type userClient struct {
@@ -39,7 +51,7 @@ func NewUserClient(cc grpc.ClientConnInterface) UserClient {
 
 func (c *userClient) Ping(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
 	out := new(Response)
-	err := c.cc.Invoke(ctx, "/user.User/Ping", in, out, opts...)
+	err := c.cc.Invoke(ctx, User_Ping_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +60,7 @@ func (c *userClient) Ping(ctx context.Context, in *Request, opts ...grpc.CallOpt
 
 func (c *userClient) Login(ctx context.Context, in *LoginReq, opts ...grpc.CallOption) (*LoginResp, error) {
 	out := new(LoginResp)
-	err := c.cc.Invoke(ctx, "/user.User/Login", in, out, opts...)
+	err := c.cc.Invoke(ctx, User_Login_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +69,7 @@ func (c *userClient) Login(ctx context.Context, in *LoginReq, opts ...grpc.CallO
 
 func (c *userClient) Register(ctx context.Context, in *RegisterReq, opts ...grpc.CallOption) (*RegisterResp, error) {
 	out := new(RegisterResp)
-	err := c.cc.Invoke(ctx, "/user.User/Register", in, out, opts...)
+	err := c.cc.Invoke(ctx, User_Register_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +78,7 @@ func (c *userClient) Register(ctx context.Context, in *RegisterReq, opts ...grpc
 
 func (c *userClient) GetUserInfo(ctx context.Context, in *GetUserInfoReq, opts ...grpc.CallOption) (*GetUserInfoResp, error) {
 	out := new(GetUserInfoResp)
-	err := c.cc.Invoke(ctx, "/user.User/GetUserInfo", in, out, opts...)
+	err := c.cc.Invoke(ctx, User_GetUserInfo_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -75,7 +87,25 @@ func (c *userClient) GetUserInfo(ctx context.Context, in *GetUserInfoReq, opts .
 
 func (c *userClient) FindUser(ctx context.Context, in *FindUserReq, opts ...grpc.CallOption) (*FindUserResp, error) {
 	out := new(FindUserResp)
-	err := c.cc.Invoke(ctx, "/user.User/FindUser", in, out, opts...)
+	err := c.cc.Invoke(ctx, User_FindUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) ListUserIds(ctx context.Context, in *ListUserIdsReq, opts ...grpc.CallOption) (*ListUserIdsResp, error) {
+	out := new(ListUserIdsResp)
+	err := c.cc.Invoke(ctx, User_ListUserIds_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) CreateBot(ctx context.Context, in *CreateBotReq, opts ...grpc.CallOption) (*CreateBotResp, error) {
+	out := new(CreateBotResp)
+	err := c.cc.Invoke(ctx, User_CreateBot_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -91,6 +121,8 @@ type UserServer interface {
 	Register(context.Context, *RegisterReq) (*RegisterResp, error)
 	GetUserInfo(context.Context, *GetUserInfoReq) (*GetUserInfoResp, error)
 	FindUser(context.Context, *FindUserReq) (*FindUserResp, error)
+	ListUserIds(context.Context, *ListUserIdsReq) (*ListUserIdsResp, error)
+	CreateBot(context.Context, *CreateBotReq) (*CreateBotResp, error)
 	mustEmbedUnimplementedUserServer()
 }
 
@@ -113,6 +145,12 @@ func (UnimplementedUserServer) GetUserInfo(context.Context, *GetUserInfoReq) (*G
 func (UnimplementedUserServer) FindUser(context.Context, *FindUserReq) (*FindUserResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindUser not implemented")
 }
+func (UnimplementedUserServer) ListUserIds(context.Context, *ListUserIdsReq) (*ListUserIdsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserIds not implemented")
+}
+func (UnimplementedUserServer) CreateBot(context.Context, *CreateBotReq) (*CreateBotResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBot not implemented")
+}
 func (UnimplementedUserServer) mustEmbedUnimplementedUserServer() {}
 
 // UnsafeUserServer may be embedded to opt out of forward compatibility for this service.
@@ -136,7 +174,7 @@ func _User_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/user.User/Ping",
+		FullMethod: User_Ping_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(UserServer).Ping(ctx, req.(*Request))
@@ -154,7 +192,7 @@ func _User_Login_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/user.User/Login",
+		FullMethod: User_Login_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(UserServer).Login(ctx, req.(*LoginReq))
@@ -172,7 +210,7 @@ func _User_Register_Handler(srv interface{}, ctx context.Context, dec func(inter
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/user.User/Register",
+		FullMethod: User_Register_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(UserServer).Register(ctx, req.(*RegisterReq))
@@ -190,7 +228,7 @@ func _User_GetUserInfo_Handler(srv interface{}, ctx context.Context, dec func(in
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/user.User/GetUserInfo",
+		FullMethod: User_GetUserInfo_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(UserServer).GetUserInfo(ctx, req.(*GetUserInfoReq))
@@ -208,7 +246,7 @@ func _User_FindUser_Handler(srv interface{}, ctx context.Context, dec func(inter
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/user.User/FindUser",
+		FullMethod: User_FindUser_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(UserServer).FindUser(ctx, req.(*FindUserReq))
@@ -216,6 +254,42 @@ func _User_FindUser_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _User_ListUserIds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserIdsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).ListUserIds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_ListUserIds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).ListUserIds(ctx, req.(*ListUserIdsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_CreateBot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBotReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).CreateBot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: User_CreateBot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).CreateBot(ctx, req.(*CreateBotReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // User_ServiceDesc is the grpc.ServiceDesc for User service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -243,6 +317,14 @@ var User_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FindUser",
 			Handler:    _User_FindUser_Handler,
 		},
+		{
+			MethodName: "ListUserIds",
+			Handler:    _User_ListUserIds_Handler,
+		},
+		{
+			MethodName: "CreateBot",
+			Handler:    _User_CreateBot_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "apps/user/rpc/user.proto",
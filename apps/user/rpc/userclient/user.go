@@ -13,10 +13,14 @@ import (
 )
 
 type (
+	CreateBotReq    = user.CreateBotReq
+	CreateBotResp   = user.CreateBotResp
 	FindUserReq     = user.FindUserReq
 	FindUserResp    = user.FindUserResp
 	GetUserInfoReq  = user.GetUserInfoReq
 	GetUserInfoResp = user.GetUserInfoResp
+	ListUserIdsReq  = user.ListUserIdsReq
+	ListUserIdsResp = user.ListUserIdsResp
 	LoginReq        = user.LoginReq
 	LoginResp       = user.LoginResp
 	RegisterReq     = user.RegisterReq
@@ -31,6 +35,8 @@ type (
 		Register(ctx context.Context, in *RegisterReq, opts ...grpc.CallOption) (*RegisterResp, error)
 		GetUserInfo(ctx context.Context, in *GetUserInfoReq, opts ...grpc.CallOption) (*GetUserInfoResp, error)
 		FindUser(ctx context.Context, in *FindUserReq, opts ...grpc.CallOption) (*FindUserResp, error)
+		ListUserIds(ctx context.Context, in *ListUserIdsReq, opts ...grpc.CallOption) (*ListUserIdsResp, error)
+		CreateBot(ctx context.Context, in *CreateBotReq, opts ...grpc.CallOption) (*CreateBotResp, error)
 	}
 
 	defaultUser struct {
@@ -68,3 +74,13 @@ func (m *defaultUser) FindUser(ctx context.Context, in *FindUserReq, opts ...grp
 	client := user.NewUserClient(m.cli.Conn())
 	return client.FindUser(ctx, in, opts...)
 }
+
+func (m *defaultUser) ListUserIds(ctx context.Context, in *ListUserIdsReq, opts ...grpc.CallOption) (*ListUserIdsResp, error) {
+	client := user.NewUserClient(m.cli.Conn())
+	return client.ListUserIds(ctx, in, opts...)
+}
+
+func (m *defaultUser) CreateBot(ctx context.Context, in *CreateBotReq, opts ...grpc.CallOption) (*CreateBotResp, error) {
+	client := user.NewUserClient(m.cli.Conn())
+	return client.CreateBot(ctx, in, opts...)
+}
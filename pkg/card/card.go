@@ -0,0 +1,109 @@
+package card
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxTitleLen   = 100
+	MaxTextLen    = 2000
+	MaxButtons    = 10
+	MaxButtonText = 40
+	MaxButtonId   = 64
+)
+
+// HandlerBotPrefix 机器人发送的卡片，按钮点击投递给机器人的 webhook，Handler 为前缀加机器人ID
+const HandlerBotPrefix = "bot:"
+
+var (
+	ErrInvalidCard   = errors.New("card: invalid card")
+	ErrEmptyCard     = errors.New("card: title and text are empty")
+	ErrTooLong       = errors.New("card: content too long")
+	ErrTooManyButton = errors.New("card: too many buttons")
+	ErrInvalidButton = errors.New("card: invalid button")
+)
+
+// Card 交互式消息卡片，以 JSON 保存在消息内容中
+type Card struct {
+	Title   string   `json:"title,omitempty"`
+	Text    string   `json:"text,omitempty"`
+	Buttons []Button `json:"buttons,omitempty"`
+	Footer  string   `json:"footer,omitempty"`
+	// Closed 卡片已关闭，按钮不再响应
+	Closed bool `json:"closed,omitempty"`
+	// Handler 处理按钮点击的命令或机器人，由服务端设置
+	Handler string `json:"handler,omitempty"`
+}
+
+type Button struct {
+	Id    string `json:"id"`
+	Text  string `json:"text"`
+	Style string `json:"style,omitempty"`
+	// Count 按钮上展示的计数，如投票数
+	Count int `json:"count,omitempty"`
+}
+
+// Parse 解析并校验卡片
+func Parse(content string) (*Card, error) {
+	var c Card
+	if err := json.Unmarshal([]byte(content), &c); err != nil {
+		return nil, ErrInvalidCard
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *Card) Validate() error {
+	if strings.TrimSpace(c.Title) == "" && strings.TrimSpace(c.Text) == "" {
+		return ErrEmptyCard
+	}
+	if utf8.RuneCountInString(c.Title) > MaxTitleLen || utf8.RuneCountInString(c.Text) > MaxTextLen ||
+		utf8.RuneCountInString(c.Footer) > MaxTitleLen {
+		return ErrTooLong
+	}
+	if len(c.Buttons) > MaxButtons {
+		return ErrTooManyButton
+	}
+
+	ids := make(map[string]struct{}, len(c.Buttons))
+	for _, b := range c.Buttons {
+		if b.Id == "" || len(b.Id) > MaxButtonId || strings.TrimSpace(b.Text) == "" ||
+			utf8.RuneCountInString(b.Text) > MaxButtonText {
+			return ErrInvalidButton
+		}
+		if _, ok := ids[b.Id]; ok {
+			return ErrInvalidButton
+		}
+		ids[b.Id] = struct{}{}
+	}
+	return nil
+}
+
+// Button 按 id 查找按钮
+func (c *Card) Button(id string) (*Button, bool) {
+	for i := range c.Buttons {
+		if c.Buttons[i].Id == id {
+			return &c.Buttons[i], true
+		}
+	}
+	return nil, false
+}
+
+// BotId 机器人发送的卡片返回机器人ID
+func (c *Card) BotId() string {
+	if strings.HasPrefix(c.Handler, HandlerBotPrefix) {
+		return strings.TrimPrefix(c.Handler, HandlerBotPrefix)
+	}
+	return ""
+}
+
+// String 序列化为消息内容
+func (c *Card) String() string {
+	b, _ := json.Marshal(c)
+	return string(b)
+}
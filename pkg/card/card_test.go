@@ -0,0 +1,43 @@
+package card
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	c, err := Parse(`{"title":"投票","buttons":[{"id":"1","text":"A"},{"id":"2","text":"B"}],"handler":"bot:b1"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, ok := c.Button("2"); !ok || b.Text != "B" {
+		t.Errorf("Button(2) = %v, %v", b, ok)
+	}
+	if _, ok := c.Button("3"); ok {
+		t.Error("Button(3) found")
+	}
+	if c.BotId() != "b1" {
+		t.Errorf("BotId = %q, want b1", c.BotId())
+	}
+
+	again, err := Parse(c.String())
+	if err != nil || again.Title != c.Title || len(again.Buttons) != 2 {
+		t.Errorf("Parse(String()) = %+v, %v", again, err)
+	}
+
+	tests := map[string]error{
+		`not json`:      ErrInvalidCard,
+		`{}`:            ErrEmptyCard,
+		`{"title":" "}`: ErrEmptyCard,
+		`{"title":"` + strings.Repeat("长", MaxTitleLen+1) + `"}`:                ErrTooLong,
+		`{"title":"t","buttons":[{"id":"1","text":"A"},{"id":"1","text":"B"}]}`: ErrInvalidButton,
+		`{"title":"t","buttons":[{"id":"","text":"A"}]}`:                        ErrInvalidButton,
+		`{"title":"t","buttons":[{"id":"1","text":""}]}`:                        ErrInvalidButton,
+	}
+	for content, want := range tests {
+		if _, err := Parse(content); !errors.Is(err, want) {
+			t.Errorf("Parse(%.40q) err = %v, want %v", content, err, want)
+		}
+	}
+}
@@ -9,6 +9,20 @@ type MType int
 
 const (
 	TextMType MType = iota
+	// 合并转发的聊天记录卡片
+	MergeForwardMType
+	// 交互式卡片，消息内容为卡片的 JSON
+	CardMType
+)
+
+// MsgFrom 消息来源
+type MsgFrom int
+
+const (
+	// 用户发送
+	UserMsgFrom MsgFrom = iota
+	// 机器人通过 incoming webhook 发送
+	BotMsgFrom
 )
 
 type ChatType int
@@ -24,4 +38,44 @@ const (
 	ContentChatMsg ContentType = iota
 	ContentMakeRead
 	ContentRevoke
+	ContentEdit
+	ContentReaction
+	ContentPin
+	ContentUnpin
+	ContentExpire
+	ContentDelivered
+	// 会话导出任务结束，content 为任务ID
+	ContentExport
+	// 消息的链接预览抓取完成，预览在 linkPreview 中
+	ContentLinkPreview
+	// 卡片被更新，content 为新的卡片
+	ContentCardUpdate
+	// 发送或编辑的消息被敏感词拦截，只通知发送者，content 为提示
+	ContentModerationBlocked
 )
+
+type DisappearMode int
+
+const (
+	// 关闭阅后即焚
+	DisappearOff DisappearMode = iota
+	// 发送后开始计时
+	DisappearAfterSend
+	// 首次被阅读后开始计时
+	DisappearAfterRead
+)
+
+// ChatLogDirection 聊天记录的翻页方向
+type ChatLogDirection int
+
+const (
+	// 按发送时间查询，兼容旧的翻页方式
+	ChatLogBySendTime ChatLogDirection = iota
+	// 查询游标之前（更早）的消息
+	ChatLogBefore
+	// 查询游标之后（更新）的消息
+	ChatLogAfter
+)
+
+// MentionAll @全体成员，仅群主与管理员可用
+const MentionAll = "all"
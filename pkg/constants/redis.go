@@ -9,4 +9,14 @@ const (
 	REDIS_SYSTEM_ROOT_TOKEN string = "system:root:token"
 	REDIS_ONLINE_USER       string = "online:user"
 	REDIS_DISCOVER_SRV      string = "easy-im-srv"
+	// 链接预览缓存，后接链接的 sha1
+	REDIS_LINK_PREVIEW string = "link:preview:"
+	// 慢速模式的发言记录，后接 {groupId}:{userId}，过期时间为发言间隔
+	REDIS_SLOW_MODE string = "slow:mode:"
+	// 群成员列表的版本号，后接 groupId，成员变化时递增，用于使各服务缓存的成员列表失效
+	REDIS_GROUP_MEMBERS_VERSION string = "group:members:version:"
+	// 群成员列表的共享缓存，后接 groupId，内容带有写入时的版本号
+	REDIS_GROUP_MEMBERS string = "group:members:list:"
+	// 群成员变化的通知频道，消息内容为 groupId
+	REDIS_GROUP_MEMBERS_CHANNEL string = "group:members:changed"
 )
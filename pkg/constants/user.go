@@ -8,3 +8,9 @@ package constants
 const (
 	SYSTEM_ROOT_UID = "root"
 )
+
+// 用户状态，普通用户的状态为空
+const (
+	// BotUserStatus 机器人账号，不能登录
+	BotUserStatus = 1
+)
@@ -0,0 +1,70 @@
+// Package fanout 大群消息的扇出
+//
+// 发送方按接收方所在的网关节点分组，每个节点只发送一帧；
+// 网关节点内同一份内容只序列化一次，再由固定数量的 goroutine 写给各个连接
+package fanout
+
+import "sync"
+
+// DefaultConcurrency 网关节点写连接的默认并发数
+const DefaultConcurrency = 32
+
+// Partition 按节点对接收方分组，nodes[i] 为 uids[i] 所在的节点，节点为空的接收方（不在线）被忽略
+func Partition(uids, nodes []string) map[string][]string {
+	res := make(map[string][]string)
+	for i, uid := range uids {
+		if i >= len(nodes) || nodes[i] == "" {
+			continue
+		}
+		res[nodes[i]] = append(res[nodes[i]], uid)
+	}
+	return res
+}
+
+// Broadcast 对 n 个接收方调用 write，接收方按顺序分成 concurrency 段，每段由一个 goroutine 依次写入
+//
+// 与每个接收方一个 goroutine 相比，goroutine 的数量固定，大群不会瞬间创建上千个任务；
+// 返回写入失败的数量
+func Broadcast(n, concurrency int, write func(i int) error) int {
+	if n <= 0 {
+		return 0
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+	)
+	size := (n + concurrency - 1) / concurrency
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			var count int
+			for i := start; i < end; i++ {
+				if err := write(i); err != nil {
+					count++
+				}
+			}
+			if count > 0 {
+				mu.Lock()
+				failed += count
+				mu.Unlock()
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	return failed
+}
@@ -0,0 +1,39 @@
+package fanout
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestPartition(t *testing.T) {
+	got := Partition([]string{"u1", "u2", "u3", "u4"}, []string{"n1", "", "n2", "n1"})
+	if len(got) != 2 || fmt.Sprint(got["n1"]) != "[u1 u4]" || fmt.Sprint(got["n2"]) != "[u3]" {
+		t.Errorf("Partition = %v", got)
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	for _, n := range []int{0, 1, 7, 100} {
+		var (
+			mu   sync.Mutex
+			seen = make(map[int]bool)
+		)
+		failed := Broadcast(n, 8, func(i int) error {
+			mu.Lock()
+			seen[i] = true
+			mu.Unlock()
+			if i%10 == 0 {
+				return errors.New("write failed")
+			}
+			return nil
+		})
+		if len(seen) != n {
+			t.Errorf("n = %d, written %d", n, len(seen))
+		}
+		if want := (n + 9) / 10; failed != want {
+			t.Errorf("n = %d, failed = %d, want %d", n, failed, want)
+		}
+	}
+}
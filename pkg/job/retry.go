@@ -20,6 +20,19 @@ func RetryJetLagAlways(ctx context.Context, retryCount int, lastTime time.Durati
 	return DefaultRetryJetLag
 }
 
+// RetryJetLagExponential 指数退避，首次间隔为 base，之后每次翻倍，最长不超过 max
+func RetryJetLagExponential(base, max time.Duration) RetryJetLagFunc {
+	return func(ctx context.Context, retryCount int, lastTime time.Duration) time.Duration {
+		if lastTime <= 0 {
+			return base
+		}
+		if next := lastTime * 2; next < max {
+			return next
+		}
+		return max
+	}
+}
+
 // 是否进行重试
 type IsRetryFunc func(ctx context.Context, retryCount int, err error) bool
 
@@ -27,6 +40,40 @@ func RetryAlways(ctx context.Context, retryCount int, err error) bool {
 	return true
 }
 
+// RetryTransient 只重试临时性的错误，Permanent 标记的错误不再重试
+func RetryTransient(ctx context.Context, retryCount int, err error) bool {
+	return !IsPermanent(err)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent 标记重试也无法成功的错误，如消息格式错误
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+func IsPermanent(err error) bool {
+	var perr *permanentError
+	return errors.As(err, &perr)
+}
+
+// WithRetry 执行 handler，失败时按 opts 重试，重试间隔同样受 ctx 的超时控制
+//
+// 超时或 ctx 被取消时 handler 的 ctx 同时被取消，WithRetry 等待正在执行的 handler 返回后才返回 ErrJobTimeout，
+// 调用方拿到结果时不会再有 handler 在执行；handler 需要响应 ctx 的取消，否则会一直等到 handler 结束
 func WithRetry(ctx context.Context, handler func(ctx context.Context) error, opts ...RetryOptions) error {
 	opt := newOptions(opts...)
 
@@ -51,17 +98,27 @@ func WithRetry(ctx context.Context, handler func(ctx context.Context) error, opt
 
 		select {
 		case herr = <-ch:
-			if herr == nil {
+		case <-ctx.Done():
+			// 等待本次执行结束，超时之后才完成的也算成功
+			if herr = <-ch; herr == nil {
 				return nil
 			}
+			return ErrJobTimeout
+		}
 
-			if !opt.isRetryFunc(ctx, i, herr) {
-				return herr
-			}
+		if herr == nil {
+			return nil
+		}
+		if !opt.isRetryFunc(ctx, i, herr) || i == opt.retryNums-1 {
+			return herr
+		}
 
-			retryJetLag = opt.retryJetLag(ctx, i, retryJetLag)
-			time.Sleep(retryJetLag)
+		retryJetLag = opt.retryJetLag(ctx, i, retryJetLag)
+		timer := time.NewTimer(retryJetLag)
+		select {
+		case <-timer.C:
 		case <-ctx.Done():
+			timer.Stop()
 			return ErrJobTimeout
 		}
 	}
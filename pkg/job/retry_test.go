@@ -8,6 +8,8 @@ package job
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -69,3 +71,70 @@ func TestWithRetry(t *testing.T) {
 		})
 	}
 }
+
+func TestWithRetry_Permanent(t *testing.T) {
+	var (
+		ErrTest = errors.New("测试异常")
+		count   int
+	)
+	err := WithRetry(context.Background(), func(ctx context.Context) error {
+		count++
+		return fmt.Errorf("wrap: %w", Permanent(ErrTest))
+	}, WithIsRetryFunc(RetryTransient))
+
+	if !IsPermanent(err) || !errors.Is(err, ErrTest) {
+		t.Errorf("WithRetry() error = %v, want permanent %v", err, ErrTest)
+	}
+	if count != 1 {
+		t.Errorf("handler count = %d, want 1", count)
+	}
+}
+
+func TestWithRetry_TimeoutWaitsForHandler(t *testing.T) {
+	var returned atomic.Bool
+	err := WithRetry(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		returned.Store(true)
+		return ctx.Err()
+	}, WithRetryTimeout(50*time.Millisecond))
+
+	if err != ErrJobTimeout {
+		t.Errorf("WithRetry() error = %v, want %v", err, ErrJobTimeout)
+	}
+	if !returned.Load() {
+		t.Error("WithRetry() returned before the handler")
+	}
+}
+
+func TestWithRetry_JetLagCancelled(t *testing.T) {
+	start := time.Now()
+	err := WithRetry(context.Background(), func(ctx context.Context) error {
+		return errors.New("测试异常")
+	}, WithRetryTimeout(50*time.Millisecond), WithRetryJetLagFunc(
+		func(ctx context.Context, retryCount int, lastTime time.Duration) time.Duration {
+			return time.Minute
+		}))
+
+	if err != ErrJobTimeout {
+		t.Errorf("WithRetry() error = %v, want %v", err, ErrJobTimeout)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("WithRetry() took %v, jet lag should stop at the timeout", d)
+	}
+}
+
+func TestRetryJetLagExponential(t *testing.T) {
+	jetLag := RetryJetLagExponential(100*time.Millisecond, time.Second)
+
+	var (
+		last time.Duration
+		want = []time.Duration{100, 200, 400, 800, 1000, 1000}
+	)
+	for i, w := range want {
+		last = jetLag(context.Background(), i, last)
+		if last != w*time.Millisecond {
+			t.Errorf("jetLag(%d) = %v, want %v", i, last, w*time.Millisecond)
+		}
+	}
+}
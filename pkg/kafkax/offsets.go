@@ -0,0 +1,62 @@
+package kafkax
+
+import "sync"
+
+// offsetTracker 记录各分区已拉取未提交的 offset
+//
+// 同一分区的消息按 key 分配给不同协程，完成的顺序与拉取的顺序不同；只有之前的消息都处理完成才能提交，
+// 避免进程退出时跳过未处理的消息
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+type partitionOffsets struct {
+	// 已拉取未提交的 offset，按拉取的顺序递增
+	pending []int64
+	done    map[int64]struct{}
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{
+		partitions: make(map[int]*partitionOffsets),
+	}
+}
+
+// add 记录拉取的消息；offset 回退说明分区重新分配后从已提交的位置重新拉取，之前未提交的记录作废
+func (t *offsetTracker) add(partition int, offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[partition]
+	if !ok || len(p.pending) > 0 && offset <= p.pending[len(p.pending)-1] {
+		p = &partitionOffsets{done: make(map[int64]struct{})}
+		t.partitions[partition] = p
+	}
+	p.pending = append(p.pending, offset)
+}
+
+// done 标记消息处理完成，返回可以提交的最大 offset，之前还有未完成的消息时返回 -1
+func (t *offsetTracker) done(partition int, offset int64) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// 重新分配之前拉取的消息已经提交或作废
+	p, ok := t.partitions[partition]
+	if !ok || len(p.pending) == 0 || offset < p.pending[0] {
+		return -1
+	}
+	p.done[offset] = struct{}{}
+
+	commit := int64(-1)
+	for len(p.pending) > 0 {
+		head := p.pending[0]
+		if _, ok := p.done[head]; !ok {
+			break
+		}
+		delete(p.done, head)
+		p.pending = p.pending[1:]
+		commit = head
+	}
+	return commit
+}
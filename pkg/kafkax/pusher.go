@@ -0,0 +1,56 @@
+// Package kafkax 按 key 保序的 kafka 生产与消费
+//
+// go-queue 的 kq.Pusher 不能指定 key，消息随机分配到分区；kq 的多个 Processors 并发处理同一分区的消息，
+// 同一会话的消息可能乱序入库与推送。这里生产时按 key 选择分区，消费时同一个 key 的消息串行处理
+package kafkax
+
+import (
+	"context"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// 批量发送的等待时间，同步写入时每次 Push 最多等待这么久，并发的 Push 合并为一批发送
+const pushBatchTimeout = 10 * time.Millisecond
+
+// Pusher 相同 key 的消息发送到同一个分区，分区内按发送的顺序写入
+//
+// 同步写入，Push 等待 broker 确认后返回，发送失败时返回错误，由调用方重试或记录
+type Pusher struct {
+	topic  string
+	writer *kafka.Writer
+}
+
+func NewPusher(addrs []string, topic string) *Pusher {
+	return &Pusher{
+		topic: topic,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(addrs...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			Compression:  kafka.Snappy,
+			BatchTimeout: pushBatchTimeout,
+			RequiredAcks: kafka.RequireOne,
+		},
+	}
+}
+
+func (p *Pusher) Name() string {
+	return p.topic
+}
+
+// Push key 为空时轮询分配分区，不保证顺序
+func (p *Pusher) Push(key, value string) error {
+	msg := kafka.Message{
+		Value: []byte(value),
+	}
+	if key != "" {
+		msg.Key = []byte(key)
+	}
+	return p.writer.WriteMessages(context.Background(), msg)
+}
+
+func (p *Pusher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,131 @@
+package kafkax
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/zeromicro/go-queue/kq"
+	"github.com/zeromicro/go-zero/core/logx"
+	"imooc.com/easy-chat/pkg/keyed"
+)
+
+const (
+	commitInterval = time.Second
+	maxWait        = time.Second
+	queueCapacity  = 1000
+)
+
+type reader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// Queue 按 key 保序的消费者，配置与 kq.KqConf 一致
+//
+// 单个协程按顺序拉取消息，再按 key 分配给 Processors 个协程处理，同一个 key 的消息串行处理，不同 key 并发处理。
+// 同一分区中之前的消息都处理完成后才提交 offset，进程退出或分区重新分配时未提交的消息会被重新消费。
+// 处理失败的消息也视为完成，与 kq 一致由之后提交的 offset 一并提交，重试由 handler 负责
+//
+// 队头阻塞：key 按哈希分配到协程，一条消息处理（包括 handler 内的重试）耗时多久，同一协程上其他 key 的消息就等待多久，
+// 所在分区的 offset 在此期间也不会提交。handler 需要限制单条消息的处理时间，task 的 deadletter.Handler 会暂停持续失败的 key
+type Queue struct {
+	c        kq.KqConf
+	reader   reader
+	handler  kq.ConsumeHandler
+	executor *keyed.Executor
+	offsets  *offsetTracker
+}
+
+func NewQueue(c kq.KqConf, handler kq.ConsumeHandler) (*Queue, error) {
+	if err := c.SetUp(); err != nil {
+		return nil, err
+	}
+
+	offset := kafka.LastOffset
+	if c.Offset == "first" {
+		offset = kafka.FirstOffset
+	}
+
+	readerConfig := kafka.ReaderConfig{
+		Brokers:        c.Brokers,
+		GroupID:        c.Group,
+		Topic:          c.Topic,
+		StartOffset:    offset,
+		MinBytes:       c.MinBytes,
+		MaxBytes:       c.MaxBytes,
+		MaxWait:        maxWait,
+		CommitInterval: commitInterval,
+		QueueCapacity:  queueCapacity,
+	}
+	if len(c.Username) > 0 && len(c.Password) > 0 {
+		readerConfig.Dialer = &kafka.Dialer{
+			SASLMechanism: plain.Mechanism{
+				Username: c.Username,
+				Password: c.Password,
+			},
+		}
+	}
+
+	return newQueue(c, kafka.NewReader(readerConfig), handler), nil
+}
+
+func newQueue(c kq.KqConf, r reader, handler kq.ConsumeHandler) *Queue {
+	return &Queue{
+		c:        c,
+		reader:   r,
+		handler:  handler,
+		executor: keyed.NewExecutor(c.Processors, 0),
+		offsets:  newOffsetTracker(),
+	}
+}
+
+func (q *Queue) Start() {
+	defer q.executor.Stop()
+
+	for {
+		msg, err := q.reader.FetchMessage(context.Background())
+		// io.EOF 表示消费者已关闭
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) {
+			return
+		}
+		if err != nil {
+			logx.Errorf("kafkax fetch %v err %v", q.c.Topic, err)
+			continue
+		}
+
+		q.offsets.add(msg.Partition, msg.Offset)
+		q.executor.Submit(string(msg.Key), func() {
+			q.consumeOne(msg)
+		})
+	}
+}
+
+func (q *Queue) Stop() {
+	if err := q.reader.Close(); err != nil {
+		logx.Errorf("kafkax close %v err %v", q.c.Topic, err)
+	}
+}
+
+func (q *Queue) consumeOne(msg kafka.Message) {
+	if err := q.handler.Consume(string(msg.Key), string(msg.Value)); err != nil {
+		logx.Errorf("kafkax consume %v err %v, value %s", q.c.Topic, err, msg.Value)
+	}
+
+	offset := q.offsets.done(msg.Partition, msg.Offset)
+	if offset < 0 {
+		return
+	}
+	err := q.reader.CommitMessages(context.Background(), kafka.Message{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    offset,
+	})
+	if err != nil {
+		logx.Errorf("kafkax commit %v err %v", q.c.Topic, err)
+	}
+}
@@ -0,0 +1,200 @@
+package kafkax
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/zeromicro/go-queue/kq"
+)
+
+// fakeReader 按顺序返回预先生成的消息，记录每个分区提交的 offset
+type fakeReader struct {
+	t        *testing.T
+	mu       sync.Mutex
+	msgs     []kafka.Message
+	commits  map[int]int64
+	closed   chan struct{}
+	consumed func(kafka.Message) bool
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	r.mu.Lock()
+	if len(r.msgs) > 0 {
+		msg := r.msgs[0]
+		r.msgs = r.msgs[1:]
+		r.mu.Unlock()
+		return msg, nil
+	}
+	r.mu.Unlock()
+
+	<-r.closed
+	return kafka.Message{}, io.EOF
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, msg := range msgs {
+		// 提交的 offset 之前的消息必须都已处理完成
+		if !r.consumed(msg) {
+			r.t.Errorf("partition %d committed %d before earlier messages consumed", msg.Partition, msg.Offset)
+		}
+		if msg.Offset > r.commits[msg.Partition] {
+			r.commits[msg.Partition] = msg.Offset
+		}
+	}
+	return nil
+}
+
+func (r *fakeReader) Close() error {
+	close(r.closed)
+	return nil
+}
+
+type recorder struct {
+	mu   sync.Mutex
+	got  map[string][]int
+	done map[string]bool
+}
+
+func (r *recorder) Consume(key, value string) error {
+	if rand.Intn(5) == 0 {
+		time.Sleep(time.Duration(rand.Intn(200)) * time.Microsecond)
+	}
+
+	seq, _ := strconv.Atoi(value)
+	r.mu.Lock()
+	r.got[key] = append(r.got[key], seq)
+	r.done[key+":"+value] = true
+	r.mu.Unlock()
+	return nil
+}
+
+func TestQueue_OrderUnderConcurrency(t *testing.T) {
+	const (
+		partitions    = 3
+		conversations = 30
+		perKey        = 100
+	)
+
+	// 同一会话的消息在同一分区，分区内不同会话的消息交错
+	var (
+		msgs    []kafka.Message
+		offsets = make([]int64, partitions)
+		keyOf   = make(map[int]map[int64]string)
+	)
+	for p := 0; p < partitions; p++ {
+		keyOf[p] = make(map[int64]string)
+	}
+	for i := 0; i < perKey; i++ {
+		for c := 0; c < conversations; c++ {
+			key := "conversation-" + strconv.Itoa(c)
+			p := c % partitions
+			msgs = append(msgs, kafka.Message{
+				Topic:     "test",
+				Partition: p,
+				Offset:    offsets[p],
+				Key:       []byte(key),
+				Value:     []byte(strconv.Itoa(i)),
+			})
+			keyOf[p][offsets[p]] = key + ":" + strconv.Itoa(i)
+			offsets[p]++
+		}
+	}
+
+	h := &recorder{got: make(map[string][]int), done: make(map[string]bool)}
+	r := &fakeReader{
+		t:       t,
+		msgs:    msgs,
+		commits: make(map[int]int64),
+		closed:  make(chan struct{}),
+	}
+	r.consumed = func(msg kafka.Message) bool {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		for offset := int64(0); offset <= msg.Offset; offset++ {
+			if !h.done[keyOf[msg.Partition][offset]] {
+				return false
+			}
+		}
+		return true
+	}
+
+	q := newQueue(kq.KqConf{Topic: "test", Processors: 8}, r, h)
+	stopped := make(chan struct{})
+	go func() {
+		q.Start()
+		close(stopped)
+	}()
+
+	deadline := time.After(10 * time.Second)
+	for {
+		r.mu.Lock()
+		fetched := len(r.msgs) == 0
+		r.mu.Unlock()
+		h.mu.Lock()
+		finished := len(h.done) == len(msgs)
+		h.mu.Unlock()
+		if fetched && finished {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timeout waiting for messages")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	q.Stop()
+	<-stopped
+
+	for c := 0; c < conversations; c++ {
+		key := "conversation-" + strconv.Itoa(c)
+		got := h.got[key]
+		if len(got) != perKey {
+			t.Fatalf("%v got %d messages, want %d", key, len(got), perKey)
+		}
+		for i, seq := range got {
+			if seq != i {
+				t.Fatalf("%v message %d consumed at position %d", key, seq, i)
+			}
+		}
+	}
+	for p := 0; p < partitions; p++ {
+		if r.commits[p] != offsets[p]-1 {
+			t.Errorf("partition %d committed %d, want %d", p, r.commits[p], offsets[p]-1)
+		}
+	}
+}
+
+func TestOffsetTracker(t *testing.T) {
+	tracker := newOffsetTracker()
+	for offset := int64(10); offset < 14; offset++ {
+		tracker.add(0, offset)
+	}
+
+	if got := tracker.done(0, 11); got != -1 {
+		t.Errorf("done(11) = %d, want -1", got)
+	}
+	if got := tracker.done(0, 10); got != 11 {
+		t.Errorf("done(10) = %d, want 11", got)
+	}
+	if got := tracker.done(0, 13); got != -1 {
+		t.Errorf("done(13) = %d, want -1", got)
+	}
+
+	// 重新分配后从 12 重新拉取，之前的记录作废
+	tracker.add(0, 12)
+	if got := tracker.done(0, 12); got != 12 {
+		t.Errorf("done(12) after rebalance = %d, want 12", got)
+	}
+	if got := tracker.done(0, 13); got != -1 {
+		t.Errorf("stale done(13) = %d, want -1", got)
+	}
+}
@@ -0,0 +1,77 @@
+// Package keyed 按 key 把任务分配给固定的协程执行：同一个 key 的任务串行并保持提交的顺序，不同 key 的任务并发执行
+package keyed
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/zeromicro/go-zero/core/rescue"
+)
+
+const (
+	DefaultWorkers   = 8
+	DefaultQueueSize = 128
+)
+
+// Executor 同一个 key 的任务需要由同一个协程按顺序提交，执行顺序与提交顺序一致
+//
+// 多个 key 共享一个协程，耗时的任务会阻塞同一协程上其他 key 的任务，任务需要自行限制执行时间
+type Executor struct {
+	queues []chan func()
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+func NewExecutor(workers, queueSize int) *Executor {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+
+	e := &Executor{
+		queues: make([]chan func(), workers),
+	}
+	for i := range e.queues {
+		e.queues[i] = make(chan func(), queueSize)
+		e.wg.Add(1)
+		go e.run(e.queues[i])
+	}
+	return e
+}
+
+// Submit 提交任务，key 对应的队列已满时阻塞，Stop 之后不能再提交
+func (e *Executor) Submit(key string, task func()) {
+	e.queues[index(key, len(e.queues))] <- task
+}
+
+// Stop 等待已提交的任务执行完成
+func (e *Executor) Stop() {
+	e.once.Do(func() {
+		for _, queue := range e.queues {
+			close(queue)
+		}
+	})
+	e.wg.Wait()
+}
+
+func (e *Executor) run(queue chan func()) {
+	defer e.wg.Done()
+
+	for task := range queue {
+		runSafe(task)
+	}
+}
+
+// runSafe 任务 panic 时恢复，不影响同一协程上的其他 key
+func runSafe(task func()) {
+	defer rescue.Recover()
+	task()
+}
+
+func index(key string, n int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(n))
+}
@@ -0,0 +1,99 @@
+package keyed
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExecutor_Order(t *testing.T) {
+	const (
+		keys    = 50
+		perKey  = 200
+		workers = 8
+	)
+
+	var (
+		mu   sync.Mutex
+		got  = make(map[string][]int, keys)
+		busy = make(map[string]*int32, keys)
+	)
+	for k := 0; k < keys; k++ {
+		busy[strconv.Itoa(k)] = new(int32)
+	}
+
+	e := NewExecutor(workers, 16)
+	// 交替提交不同 key 的任务，模拟同一分区中多个会话的消息
+	for i := 0; i < perKey; i++ {
+		for k := 0; k < keys; k++ {
+			key, seq := strconv.Itoa(k), i
+			e.Submit(key, func() {
+				if atomic.AddInt32(busy[key], 1) != 1 {
+					t.Errorf("key %v processed concurrently", key)
+				}
+				if rand.Intn(10) == 0 {
+					time.Sleep(time.Duration(rand.Intn(100)) * time.Microsecond)
+				}
+
+				mu.Lock()
+				got[key] = append(got[key], seq)
+				mu.Unlock()
+				atomic.AddInt32(busy[key], -1)
+			})
+		}
+	}
+	e.Stop()
+
+	for k := 0; k < keys; k++ {
+		key := strconv.Itoa(k)
+		if len(got[key]) != perKey {
+			t.Fatalf("key %v got %d tasks, want %d", key, len(got[key]), perKey)
+		}
+		for i, seq := range got[key] {
+			if seq != i {
+				t.Fatalf("key %v task %d executed at position %d", key, seq, i)
+			}
+		}
+	}
+}
+
+func TestExecutor_Concurrent(t *testing.T) {
+	e := NewExecutor(4, 1)
+	defer e.Stop()
+
+	// 找到分配到不同协程的两个 key，一个阻塞时另一个仍然可以执行
+	a, b := "a", ""
+	for i := 0; b == ""; i++ {
+		if key := strconv.Itoa(i); index(key, 4) != index(a, 4) {
+			b = key
+		}
+	}
+
+	block := make(chan struct{})
+	done := make(chan struct{})
+	e.Submit(a, func() { <-block })
+	e.Submit(b, func() { close(done) })
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("key b blocked by key a")
+	}
+	close(block)
+}
+
+func TestExecutor_Panic(t *testing.T) {
+	e := NewExecutor(1, 1)
+
+	var ran int32
+	e.Submit("a", func() { panic("boom") })
+	e.Submit("a", func() { atomic.StoreInt32(&ran, 1) })
+	e.Stop()
+
+	if atomic.LoadInt32(&ran) != 1 {
+		t.Error("task after panic not executed")
+	}
+}
@@ -0,0 +1,211 @@
+package linkpreview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+var (
+	ErrUnsupportedURL = errors.New("linkpreview: unsupported url")
+	ErrBlockedAddress = errors.New("linkpreview: blocked address")
+	ErrNotHTML        = errors.New("linkpreview: not html")
+	ErrNoMetadata     = errors.New("linkpreview: no metadata")
+)
+
+const (
+	DefaultTimeout      = 5 * time.Second
+	DefaultMaxBytes     = 512 * 1024
+	DefaultMaxRedirects = 3
+	DefaultUserAgent    = "easy-chat-linkpreview/1.0"
+)
+
+// Preview 链接预览
+type Preview struct {
+	Url         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+	SiteName    string `json:"siteName,omitempty"`
+}
+
+type Option func(f *Fetcher)
+
+// WithTimeout 单次抓取的总耗时上限，包括重定向
+func WithTimeout(timeout time.Duration) Option {
+	return func(f *Fetcher) {
+		f.timeout = timeout
+	}
+}
+
+// WithMaxBytes 最多读取的响应字节数，超出部分直接丢弃
+func WithMaxBytes(n int64) Option {
+	return func(f *Fetcher) {
+		f.maxBytes = n
+	}
+}
+
+// WithMaxRedirects 最多跟随的重定向次数
+func WithMaxRedirects(n int) Option {
+	return func(f *Fetcher) {
+		f.maxRedirects = n
+	}
+}
+
+// Fetcher 抓取网页的预览信息
+//
+// 为防止 SSRF，建立连接时校验解析后的地址，拒绝内网、回环、链路本地等地址；
+// 校验发生在拨号阶段，对重定向和 DNS 重绑定同样有效。抓取不走环境变量中的代理
+type Fetcher struct {
+	client       *http.Client
+	timeout      time.Duration
+	maxBytes     int64
+	maxRedirects int
+
+	// allow 放行指定的地址（ip:port），仅用于测试
+	allow func(address string) bool
+}
+
+func NewFetcher(opts ...Option) *Fetcher {
+	f := &Fetcher{
+		timeout:      DefaultTimeout,
+		maxBytes:     DefaultMaxBytes,
+		maxRedirects: DefaultMaxRedirects,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	dialer := &net.Dialer{
+		Timeout: f.timeout,
+		Control: f.control,
+	}
+	f.client = &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   f.timeout,
+			ResponseHeaderTimeout: f.timeout,
+			MaxIdleConns:          16,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		CheckRedirect: f.checkRedirect,
+	}
+	return f
+}
+
+// control 在建立连接前校验目标地址，address 为解析后的 ip:port
+func (f *Fetcher) control(network, address string, _ syscall.RawConn) error {
+	if f.allow != nil && f.allow(address) {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || IsBlockedIP(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	return nil
+}
+
+func (f *Fetcher) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > f.maxRedirects {
+		return fmt.Errorf("linkpreview: stopped after %d redirects", f.maxRedirects)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return ErrUnsupportedURL
+	}
+	return nil
+}
+
+// Fetch 抓取网页并解析 Open Graph 信息，没有 Open Graph 时使用页面标题与描述
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Preview, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, ErrUnsupportedURL
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", DefaultUserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("linkpreview: unexpected status %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, ErrNotHTML
+	}
+
+	// 只读取前 maxBytes 字节，预览信息通常位于 head 中
+	body, err := charset.NewReader(io.LimitReader(resp.Body, f.maxBytes), contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := parse(body, resp.Request.URL)
+	if preview.Title == "" && preview.Description == "" && preview.Image == "" {
+		return nil, ErrNoMetadata
+	}
+	preview.Url = rawURL
+	return preview, nil
+}
+
+var blockedNets = mustParseCIDRs(
+	"0.0.0.0/8",     // 本网络
+	"100.64.0.0/10", // 运营商级 NAT
+	"192.0.0.0/24",  // IETF 协议分配
+	"198.18.0.0/15", // 基准测试
+	"240.0.0.0/4",   // 保留地址
+	"64:ff9b::/96",  // NAT64，可映射到内网 IPv4
+)
+
+// IsBlockedIP 是否为不允许访问的地址，包括内网、回环、链路本地、组播等
+func IsBlockedIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return true
+	}
+	for _, n := range blockedNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	res := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		res = append(res, n)
+	}
+	return res
+}
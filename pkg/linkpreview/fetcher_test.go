@@ -0,0 +1,170 @@
+package linkpreview
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>页面标题</title>
+<meta property="og:title" content="Open Graph 标题">
+<meta property="og:description" content="  一段
+描述  ">
+<meta property="og:image" content="/static/cover.png">
+<meta property="og:site_name" content="Example">
+</head>
+<body><meta property="og:title" content="正文中的标签不解析"></body>
+</html>`
+
+func newTestFetcher(opts ...Option) *Fetcher {
+	f := NewFetcher(opts...)
+	f.allow = func(string) bool { return true }
+	return f
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/og", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(testPage))
+	})
+	mux.HandleFunc("/title", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title> 只有标题 </title><meta name="description" content="普通描述"></head></html>`))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/og", http.StatusFound)
+	})
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><head>" + strings.Repeat(" ", 4096) + "<title>超出大小</title></head></html>"))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(testPage))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	f := newTestFetcher(WithMaxBytes(1024), WithTimeout(200*time.Millisecond))
+	ctx := context.Background()
+
+	preview, err := f.Fetch(ctx, srv.URL+"/og")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Preview{
+		Url:         srv.URL + "/og",
+		Title:       "Open Graph 标题",
+		Description: "一段 描述",
+		Image:       srv.URL + "/static/cover.png",
+		SiteName:    "Example",
+	}
+	if *preview != want {
+		t.Errorf("Fetch og = %+v, want %+v", *preview, want)
+	}
+
+	preview, err = f.Fetch(ctx, srv.URL+"/title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Title != "只有标题" || preview.Description != "普通描述" {
+		t.Errorf("Fetch title = %+v", *preview)
+	}
+
+	preview, err = f.Fetch(ctx, srv.URL+"/redirect")
+	if err != nil || preview.Title != want.Title {
+		t.Errorf("Fetch redirect = %+v, %v", preview, err)
+	}
+
+	if _, err := f.Fetch(ctx, srv.URL+"/json"); !errors.Is(err, ErrNotHTML) {
+		t.Errorf("Fetch json err = %v, want ErrNotHTML", err)
+	}
+	if _, err := f.Fetch(ctx, srv.URL+"/large"); !errors.Is(err, ErrNoMetadata) {
+		t.Errorf("Fetch large err = %v, want ErrNoMetadata", err)
+	}
+	if _, err := f.Fetch(ctx, srv.URL+"/slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Fetch slow err = %v, want DeadlineExceeded", err)
+	}
+	if _, err := f.Fetch(ctx, "file:///etc/passwd"); !errors.Is(err, ErrUnsupportedURL) {
+		t.Errorf("Fetch file err = %v, want ErrUnsupportedURL", err)
+	}
+}
+
+func TestFetcher_BlockPrivate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(testPage))
+	}))
+	defer srv.Close()
+
+	f := NewFetcher()
+	_, err := f.Fetch(context.Background(), srv.URL)
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Fetch loopback err = %v, want ErrBlockedAddress", err)
+	}
+
+	// 放行的地址重定向到内网同样被拦截
+	redirect := httptest.NewServer(http.RedirectHandler(srv.URL, http.StatusFound))
+	defer redirect.Close()
+	public := NewFetcher()
+	public.allow = func(address string) bool { return address == redirect.Listener.Addr().String() }
+	if _, err := public.Fetch(context.Background(), redirect.URL); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Fetch redirect to loopback err = %v, want ErrBlockedAddress", err)
+	}
+}
+
+func TestIsBlockedIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		blocked bool
+	}{
+		{"127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"::1", true},
+		{"fc00::1", true},
+		{"fe80::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"64:ff9b::a00:1", true},
+		{"8.8.8.8", false},
+		{"2001:4860:4860::8888", false},
+	}
+	for _, tt := range tests {
+		if got := IsBlockedIP(net.ParseIP(tt.ip)); got != tt.blocked {
+			t.Errorf("IsBlockedIP(%s) = %v, want %v", tt.ip, got, tt.blocked)
+		}
+	}
+}
+
+func TestExtractURL(t *testing.T) {
+	tests := map[string]string{
+		"看看这个 https://example.com/a?b=1，不错": "https://example.com/a?b=1",
+		"(http://example.com).":             "http://example.com",
+		"没有链接":                              "",
+		"ftp://example.com":                 "",
+	}
+	for text, want := range tests {
+		if got := ExtractURL(text); got != want {
+			t.Errorf("ExtractURL(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package linkpreview
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	maxTitleLen       = 200
+	maxDescriptionLen = 500
+)
+
+var urlRegexp = regexp.MustCompile(`https?://[^\s<>"'，。！？；、（）【】《》]+`)
+
+// ExtractURL 返回文本中的第一个链接，去掉结尾的标点
+func ExtractURL(text string) string {
+	return strings.TrimRight(urlRegexp.FindString(text), ".,;:!?)]}")
+}
+
+// parse 从 head 中解析预览信息，Open Graph 优先，其次为 twitter 卡片与普通的 title、description
+func parse(r io.Reader, base *url.URL) *Preview {
+	var (
+		z       = html.NewTokenizer(r)
+		meta    = make(map[string]string)
+		title   string
+		inTitle bool
+	)
+
+loop:
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			break loop
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = true
+			case "meta":
+				if hasAttr {
+					key, content := metaAttrs(z)
+					if key != "" && content != "" {
+						if _, ok := meta[key]; !ok {
+							meta[key] = content
+						}
+					}
+				}
+			case "body":
+				break loop
+			}
+		case html.TextToken:
+			if inTitle && title == "" {
+				title = string(z.Text())
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				break loop
+			}
+		}
+	}
+
+	preview := &Preview{
+		Title:       first(meta["og:title"], meta["twitter:title"], title),
+		Description: first(meta["og:description"], meta["twitter:description"], meta["description"]),
+		SiteName:    meta["og:site_name"],
+	}
+	preview.Title = truncate(preview.Title, maxTitleLen)
+	preview.Description = truncate(preview.Description, maxDescriptionLen)
+	preview.SiteName = truncate(preview.SiteName, maxTitleLen)
+
+	if image := first(meta["og:image"], meta["og:image:url"], meta["twitter:image"]); image != "" {
+		preview.Image = resolveImage(base, image)
+	}
+	return preview
+}
+
+// metaAttrs 返回 meta 标签的 property 或 name，以及 content
+func metaAttrs(z *html.Tokenizer) (string, string) {
+	var key, content string
+	for {
+		k, v, more := z.TagAttr()
+		switch string(k) {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(string(v)))
+			}
+		case "content":
+			content = string(v)
+		}
+		if !more {
+			return key, content
+		}
+	}
+}
+
+// resolveImage 把相对地址转换为绝对地址，只保留 http 与 https 地址
+func resolveImage(base *url.URL, image string) string {
+	u, err := url.Parse(strings.TrimSpace(image))
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
@@ -0,0 +1,224 @@
+// Package membership 群成员列表的缓存
+//
+// 一级缓存为进程内的 LRU，二级缓存为 redis 中按版本号保存的成员列表，都未命中时通过 Loader 查询社交服务。
+// 社交服务在成员变化后调用 Publish：递增群的版本号使二级缓存失效，并通过 redis 频道通知各个进程清除一级缓存。
+// 通知丢失时一级缓存在 LocalExpire 之后过期，成员列表最多滞后 LocalExpire
+package membership
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/collection"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+	"github.com/zeromicro/go-zero/core/syncx"
+	"imooc.com/easy-chat/pkg/constants"
+)
+
+const (
+	DefaultLocalExpire  = 30 * time.Second
+	DefaultRemoteExpire = 10 * time.Minute
+	DefaultLimit        = 10000
+)
+
+// Member 群成员
+type Member struct {
+	UserId    string `json:"userId"`
+	RoleLevel int    `json:"roleLevel"`
+}
+
+// Loader 从社交服务查询群成员
+type Loader func(ctx context.Context, groupId string) ([]*Member, error)
+
+// Conf 缓存配置，时间单位为秒，为 0 时使用默认值
+type Conf struct {
+	// 一级缓存的过期时间，也是丢失通知时成员列表的最大滞后时间
+	LocalExpire int64 `json:",optional"`
+	// 二级缓存的过期时间
+	RemoteExpire int64 `json:",optional"`
+	// 一级缓存最多保存的群数量
+	Limit int `json:",optional"`
+}
+
+type Option func(c *Cache)
+
+// WithRedis 使用 redis 作为二级缓存，多个进程共享查询结果
+func WithRedis(rds *redis.Redis) Option {
+	return func(c *Cache) {
+		c.redis = rds
+	}
+}
+
+// WithConf 按配置设置过期时间与数量
+func WithConf(conf Conf) Option {
+	return func(c *Cache) {
+		if conf.LocalExpire > 0 {
+			c.localExpire = time.Duration(conf.LocalExpire) * time.Second
+		}
+		if conf.RemoteExpire > 0 {
+			c.remoteExpire = time.Duration(conf.RemoteExpire) * time.Second
+		}
+		if conf.Limit > 0 {
+			c.limit = conf.Limit
+		}
+	}
+}
+
+// Cache 群成员列表的缓存，可以在多个 goroutine 中使用
+type Cache struct {
+	loader       Loader
+	redis        *redis.Redis
+	localExpire  time.Duration
+	remoteExpire time.Duration
+	limit        int
+
+	local  *collection.Cache
+	flight syncx.SingleFlight
+
+	// generation 每次失效时递增，加载期间发生过失效的结果不写入一级缓存
+	generation atomic.Uint64
+	// flushed 全部失效时的 generation，早于它的一级缓存视为过期
+	flushed atomic.Uint64
+
+	watcher *watcher
+}
+
+type localEntry struct {
+	generation uint64
+	members    []*Member
+}
+
+type remoteEntry struct {
+	Version string    `json:"version"`
+	Members []*Member `json:"members"`
+}
+
+func NewCache(loader Loader, opts ...Option) *Cache {
+	c := &Cache{
+		loader:       loader,
+		localExpire:  DefaultLocalExpire,
+		remoteExpire: DefaultRemoteExpire,
+		limit:        DefaultLimit,
+		flight:       syncx.NewSingleFlight(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	local, err := collection.NewCache(c.localExpire, collection.WithLimit(c.limit))
+	if err != nil {
+		panic(err)
+	}
+	c.local = local
+	return c
+}
+
+// Members 查询群成员，返回的列表在缓存间共享，调用方不能修改
+func (c *Cache) Members(ctx context.Context, groupId string) ([]*Member, error) {
+	if v, ok := c.local.Get(groupId); ok {
+		if entry := v.(*localEntry); entry.generation >= c.flushed.Load() {
+			metricRequests.Inc(resultLocal)
+			return entry.members, nil
+		}
+	}
+
+	// 失效之后的查询不与失效之前发起的查询合并
+	generation := c.generation.Load()
+	v, err := c.flight.Do(groupId+":"+strconv.FormatUint(generation, 10), func() (any, error) {
+		members, err := c.fetch(ctx, groupId)
+		if err != nil {
+			return nil, err
+		}
+		if c.generation.Load() == generation {
+			c.local.Set(groupId, &localEntry{generation: generation, members: members})
+		}
+		return members, nil
+	})
+	if err != nil {
+		metricRequests.Inc(resultError)
+		return nil, err
+	}
+	return v.([]*Member), nil
+}
+
+// Role 查询用户在群中的角色，不是群成员时返回 0
+func (c *Cache) Role(ctx context.Context, groupId, userId string) (int, error) {
+	members, err := c.Members(ctx, groupId)
+	if err != nil {
+		return 0, err
+	}
+	for _, member := range members {
+		if member.UserId == userId {
+			return member.RoleLevel, nil
+		}
+	}
+	return 0, nil
+}
+
+// Invalidate 清除群的一级缓存
+func (c *Cache) Invalidate(groupId string) {
+	c.generation.Add(1)
+	c.local.Del(groupId)
+}
+
+// InvalidateAll 清除全部一级缓存，用于通知可能丢失的情况
+func (c *Cache) InvalidateAll() {
+	c.flushed.Store(c.generation.Add(1))
+}
+
+func (c *Cache) fetch(ctx context.Context, groupId string) ([]*Member, error) {
+	if c.redis == nil {
+		metricRequests.Inc(resultLoad)
+		return c.loader(ctx, groupId)
+	}
+
+	versionKey := constants.REDIS_GROUP_MEMBERS_VERSION + groupId
+	listKey := constants.REDIS_GROUP_MEMBERS + groupId
+
+	vals, err := c.redis.MgetCtx(ctx, versionKey, listKey)
+	if err != nil {
+		// redis 不可用时直接查询，不写入二级缓存
+		logx.WithContext(ctx).Errorf("membership mget err %v, groupId %v", err, groupId)
+		metricRequests.Inc(resultLoad)
+		return c.loader(ctx, groupId)
+	}
+
+	version := vals[0]
+	if vals[1] != "" {
+		var entry remoteEntry
+		if err := json.Unmarshal([]byte(vals[1]), &entry); err == nil && entry.Version == version {
+			metricRequests.Inc(resultRemote)
+			return entry.Members, nil
+		}
+	}
+
+	// 先读版本号再查询，查询期间成员变化时版本号已经递增，写入的结果不会被使用
+	metricRequests.Inc(resultLoad)
+	members, err := c.loader(ctx, groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(&remoteEntry{Version: version, Members: members})
+	if err == nil {
+		err = c.redis.SetexCtx(ctx, listKey, string(data), int(c.remoteExpire/time.Second))
+	}
+	if err != nil {
+		logx.WithContext(ctx).Errorf("membership setex err %v, groupId %v", err, groupId)
+	}
+	return members, nil
+}
+
+// Publish 群成员变化后由社交服务调用，使各个进程的缓存失效
+func Publish(ctx context.Context, rds *redis.Redis, groupId string) error {
+	return rds.PipelinedCtx(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, constants.REDIS_GROUP_MEMBERS_VERSION+groupId)
+		pipe.Del(ctx, constants.REDIS_GROUP_MEMBERS+groupId)
+		pipe.Publish(ctx, constants.REDIS_GROUP_MEMBERS_CHANNEL, groupId)
+		return nil
+	})
+}
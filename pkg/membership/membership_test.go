@@ -0,0 +1,65 @@
+package membership
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCache_Members(t *testing.T) {
+	var loads int32
+	c := NewCache(func(ctx context.Context, groupId string) ([]*Member, error) {
+		atomic.AddInt32(&loads, 1)
+		return []*Member{{UserId: "u1", RoleLevel: 1}, {UserId: "u2", RoleLevel: 3}}, nil
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if members, err := c.Members(ctx, "g1"); err != nil || len(members) != 2 {
+			t.Fatalf("Members = %v, %v", members, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("loads = %d, want 1", loads)
+	}
+
+	c.Invalidate("g1")
+	if role, _ := c.Role(ctx, "g1", "u1"); role != 1 {
+		t.Errorf("Role(u1) = %d, want 1", role)
+	}
+	if role, _ := c.Role(ctx, "g1", "u3"); role != 0 {
+		t.Errorf("Role(u3) = %d, want 0", role)
+	}
+	if loads != 2 {
+		t.Errorf("loads after Invalidate = %d, want 2", loads)
+	}
+
+	c.InvalidateAll()
+	c.Members(ctx, "g1")
+	c.Members(ctx, "g1")
+	if loads != 3 {
+		t.Errorf("loads after InvalidateAll = %d, want 3", loads)
+	}
+}
+
+func TestCache_InvalidateDuringLoad(t *testing.T) {
+	var (
+		c     *Cache
+		loads int32
+	)
+	c = NewCache(func(ctx context.Context, groupId string) ([]*Member, error) {
+		// 第一次查询期间成员发生变化，查询结果可能是旧的，不能写入缓存
+		if atomic.AddInt32(&loads, 1) == 1 {
+			c.Invalidate(groupId)
+		}
+		return []*Member{{UserId: "u1"}}, nil
+	})
+	ctx := context.Background()
+
+	c.Members(ctx, "g1")
+	c.Members(ctx, "g1")
+	c.Members(ctx, "g1")
+	if loads != 2 {
+		t.Errorf("loads = %d, want 2", loads)
+	}
+}
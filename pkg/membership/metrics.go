@@ -0,0 +1,33 @@
+package membership
+
+import "github.com/zeromicro/go-zero/core/metric"
+
+const (
+	resultLocal  = "local"
+	resultRemote = "remote"
+	resultLoad   = "load"
+	resultError  = "error"
+
+	sourceEvent  = "event"
+	sourceResync = "resync"
+)
+
+var (
+	// metricRequests 查询成员列表的次数，result 为命中的缓存层级或查询失败
+	metricRequests = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "membership",
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "group membership cache requests by result.",
+		Labels:    []string{"result"},
+	})
+
+	// metricInvalidations 一级缓存失效的次数，source 为收到通知或重新订阅后的全部失效
+	metricInvalidations = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "membership",
+		Subsystem: "cache",
+		Name:      "invalidations_total",
+		Help:      "group membership cache invalidations by source.",
+		Labels:    []string{"source"},
+	})
+)
@@ -0,0 +1,85 @@
+package membership
+
+import (
+	"context"
+	"time"
+
+	red "github.com/go-redis/redis/v8"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+	"imooc.com/easy-chat/pkg/constants"
+	"imooc.com/easy-chat/pkg/redisx"
+)
+
+// 订阅出错后重试的间隔
+const watchRetryInterval = time.Second
+
+type watcher struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Watch 订阅成员变化的通知，收到通知时清除对应群的一级缓存
+//
+// 连接断开期间的通知会丢失，重新订阅成功后清除全部一级缓存。只需调用一次，Close 时停止订阅
+func (c *Cache) Watch(conf redis.RedisConf) {
+	if c.watcher != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.watcher = &watcher{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go c.watch(ctx, conf)
+}
+
+// Close 停止订阅
+func (c *Cache) Close() {
+	if c.watcher == nil {
+		return
+	}
+	c.watcher.cancel()
+	<-c.watcher.done
+}
+
+func (c *Cache) watch(ctx context.Context, conf redis.RedisConf) {
+	defer close(c.watcher.done)
+
+	client := redisx.NewClient(conf)
+	defer client.Close()
+
+	pubsub := client.Subscribe(ctx, constants.REDIS_GROUP_MEMBERS_CHANNEL)
+	defer pubsub.Close()
+
+	subscribed := false
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logx.Errorf("membership watch receive err %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(watchRetryInterval):
+			}
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *red.Subscription:
+			// 断线重连后会再次收到订阅确认，期间的通知可能已经丢失
+			if subscribed {
+				c.InvalidateAll()
+				metricInvalidations.Inc(sourceResync)
+			}
+			subscribed = true
+		case *red.Message:
+			c.Invalidate(msg.Payload)
+			metricInvalidations.Inc(sourceEvent)
+		}
+	}
+}
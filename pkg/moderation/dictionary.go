@@ -0,0 +1,55 @@
+package moderation
+
+import (
+	"sync/atomic"
+
+	"imooc.com/easy-chat/pkg/configserver"
+)
+
+// Dictionary 可热更新的敏感词库，更新时整体替换匹配器，不影响正在进行的检查
+type Dictionary struct {
+	matcher atomic.Pointer[Matcher]
+}
+
+func NewDictionary(rules []Rule) *Dictionary {
+	d := &Dictionary{}
+	d.Load(rules)
+	return d
+}
+
+// Load 使用新的规则替换词库
+func (d *Dictionary) Load(rules []Rule) {
+	d.matcher.Store(NewMatcher(rules))
+}
+
+func (d *Dictionary) Check(text string) *Result {
+	return d.matcher.Load().Check(text)
+}
+
+// dictionaryConfig 配置中心中词库的格式
+type dictionaryConfig struct {
+	Rules []Rule `json:",optional"`
+}
+
+// Watch 从配置中心加载词库，并在配置变化时热更新
+func (d *Dictionary) Watch(cs configserver.ConfigServer) error {
+	cs.SetOnChange(d.loadJson)
+	if err := cs.Build(); err != nil {
+		return err
+	}
+
+	data, err := cs.FromJsonBytes()
+	if err != nil {
+		return err
+	}
+	return d.loadJson(data)
+}
+
+func (d *Dictionary) loadJson(data []byte) error {
+	var c dictionaryConfig
+	if err := configserver.LoadFromJsonBytes(data, &c); err != nil {
+		return err
+	}
+	d.Load(c.Rules)
+	return nil
+}
@@ -0,0 +1,202 @@
+package moderation
+
+import (
+	"sort"
+	"unicode"
+)
+
+// 命中规则后的处理方式，按严重程度从低到高
+const (
+	// ActionReplace 把敏感词替换为 ***
+	ActionReplace = "replace"
+	// ActionReview 照常发送，并进入人工审核队列
+	ActionReview = "review"
+	// ActionBlock 拦截消息，并通知发送者
+	ActionBlock = "block"
+)
+
+// Mask 替换敏感词的内容
+const Mask = "***"
+
+var severity = map[string]int{
+	ActionReplace: 1,
+	ActionReview:  2,
+	ActionBlock:   3,
+}
+
+// Rule 词库中的一条规则
+type Rule struct {
+	Word   string `json:"word"`
+	Action string `json:"action,default=replace,options=replace|review|block"`
+}
+
+// Hit 文本中命中的规则，Start 与 End 为字符（rune）下标，左闭右开
+type Hit struct {
+	Rule  *Rule
+	Start int
+	End   int
+}
+
+type node struct {
+	next map[rune]int
+	fail int
+	// 以该节点结尾的规则
+	out []int
+}
+
+// Matcher 基于 Aho-Corasick 自动机的多模式匹配，忽略大小写
+// 构建后只读，可以并发使用
+type Matcher struct {
+	nodes []node
+	rules []*Rule
+	lens  []int
+}
+
+func NewMatcher(rules []Rule) *Matcher {
+	m := &Matcher{nodes: []node{{next: make(map[rune]int)}}}
+
+	for i := range rules {
+		word := normalize(rules[i].Word)
+		if len(word) == 0 {
+			continue
+		}
+		rule := rules[i]
+		if _, ok := severity[rule.Action]; !ok {
+			rule.Action = ActionReplace
+		}
+
+		cur := 0
+		for _, r := range word {
+			nxt, ok := m.nodes[cur].next[r]
+			if !ok {
+				nxt = len(m.nodes)
+				m.nodes = append(m.nodes, node{next: make(map[rune]int)})
+				m.nodes[cur].next[r] = nxt
+			}
+			cur = nxt
+		}
+		m.nodes[cur].out = append(m.nodes[cur].out, len(m.rules))
+		m.rules = append(m.rules, &rule)
+		m.lens = append(m.lens, len(word))
+	}
+
+	m.build()
+	return m
+}
+
+// build 按层序计算失配指针，并把失配链上的输出合并到当前节点
+func (m *Matcher) build() {
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for r, child := range m.nodes[cur].next {
+			fail := m.nodes[cur].fail
+			for fail > 0 {
+				if _, ok := m.nodes[fail].next[r]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if nxt, ok := m.nodes[fail].next[r]; ok && nxt != child {
+				m.nodes[child].fail = nxt
+			}
+			m.nodes[child].out = append(m.nodes[child].out, m.nodes[m.nodes[child].fail].out...)
+			queue = append(queue, child)
+		}
+	}
+}
+
+// Find 返回文本中全部命中的规则
+func (m *Matcher) Find(text string) []Hit {
+	if m == nil || len(m.rules) == 0 {
+		return nil
+	}
+
+	var (
+		hits []Hit
+		cur  int
+	)
+	for i, r := range normalize(text) {
+		for cur > 0 {
+			if _, ok := m.nodes[cur].next[r]; ok {
+				break
+			}
+			cur = m.nodes[cur].fail
+		}
+		if nxt, ok := m.nodes[cur].next[r]; ok {
+			cur = nxt
+		}
+		for _, idx := range m.nodes[cur].out {
+			hits = append(hits, Hit{Rule: m.rules[idx], Start: i + 1 - m.lens[idx], End: i + 1})
+		}
+	}
+	return hits
+}
+
+// Result 内容的检查结果
+type Result struct {
+	// Action 命中规则中最严重的处理方式，没有命中时为空
+	Action string
+	// Content 替换了 ActionReplace 敏感词之后的内容
+	Content string
+	// Words 命中的敏感词，已去重
+	Words []string
+}
+
+// Check 检查内容，替换需要替换的敏感词
+func (m *Matcher) Check(text string) *Result {
+	res := &Result{Content: text}
+
+	hits := m.Find(text)
+	if len(hits) == 0 {
+		return res
+	}
+
+	runes := []rune(text)
+	masked := make([]bool, len(runes))
+	seen := make(map[string]bool)
+	for _, hit := range hits {
+		if severity[hit.Rule.Action] > severity[res.Action] {
+			res.Action = hit.Rule.Action
+		}
+		if !seen[hit.Rule.Word] {
+			seen[hit.Rule.Word] = true
+			res.Words = append(res.Words, hit.Rule.Word)
+		}
+		if hit.Rule.Action == ActionReplace {
+			for i := hit.Start; i < hit.End; i++ {
+				masked[i] = true
+			}
+		}
+	}
+	sort.Strings(res.Words)
+
+	// 连续的敏感字符只替换为一个 Mask
+	out := make([]rune, 0, len(runes))
+	for i, r := range runes {
+		if !masked[i] {
+			out = append(out, r)
+			continue
+		}
+		if i == 0 || !masked[i-1] {
+			out = append(out, []rune(Mask)...)
+		}
+	}
+	res.Content = string(out)
+	return res
+}
+
+// normalize 转为小写，保持字符数不变以便定位
+func normalize(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
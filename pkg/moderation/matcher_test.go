@@ -0,0 +1,50 @@
+package moderation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatcher_Check(t *testing.T) {
+	m := NewMatcher([]Rule{
+		{Word: "he", Action: ActionReplace},
+		{Word: "she", Action: ActionReplace},
+		{Word: "his", Action: ActionReplace},
+		{Word: "hers", Action: ActionReplace},
+		{Word: "赌博", Action: ActionReview},
+		{Word: "诈骗", Action: ActionBlock},
+	})
+
+	tests := []struct {
+		text    string
+		action  string
+		content string
+		words   []string
+	}{
+		{"ushers", ActionReplace, "u***", []string{"he", "hers", "she"}},
+		{"USHERS", ActionReplace, "U***", []string{"he", "hers", "she"}},
+		{"正常的消息", "", "正常的消息", nil},
+		{"一起去赌博吗 he", ActionReview, "一起去赌博吗 ***", []string{"he", "赌博"}},
+		{"这是诈骗，赌博", ActionBlock, "这是诈骗，赌博", []string{"诈骗", "赌博"}},
+	}
+	for _, tt := range tests {
+		res := m.Check(tt.text)
+		if res.Action != tt.action || res.Content != tt.content || !reflect.DeepEqual(res.Words, tt.words) {
+			t.Errorf("Check(%q) = %+v, want action %q content %q words %v", tt.text, res, tt.action, tt.content, tt.words)
+		}
+	}
+}
+
+func TestDictionary_Load(t *testing.T) {
+	d := NewDictionary(nil)
+	if res := d.Check("赌博"); res.Action != "" {
+		t.Errorf("empty dictionary matched %v", res.Words)
+	}
+
+	if err := d.loadJson([]byte(`{"Rules":[{"word":"赌博"}]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if res := d.Check("赌博"); res.Action != ActionReplace || res.Content != "***" {
+		t.Errorf("Check after reload = %+v", res)
+	}
+}
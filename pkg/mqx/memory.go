@@ -0,0 +1,104 @@
+package mqx
+
+import (
+	"sync"
+
+	"github.com/zeromicro/go-queue/kq"
+	"github.com/zeromicro/go-zero/core/logx"
+	"imooc.com/easy-chat/pkg/keyed"
+)
+
+// 每个 topic 缓冲的消息数，消费跟不上时发送阻塞
+const memoryTopicSize = 1024
+
+// defaultBroker 进程内共享，同一进程中的生产者与消费者通过 topic 名称对应
+var defaultBroker = newMemoryBroker()
+
+type memoryMessage struct {
+	key   string
+	value string
+}
+
+// memoryBroker 每个 topic 一个 channel，消息只投递给一个消费者，进程退出时未处理的消息丢失
+type memoryBroker struct {
+	mu     sync.Mutex
+	topics map[string]chan memoryMessage
+}
+
+func newMemoryBroker() *memoryBroker {
+	return &memoryBroker{
+		topics: make(map[string]chan memoryMessage),
+	}
+}
+
+func (b *memoryBroker) topic(name string) chan memoryMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch, ok := b.topics[name]
+	if !ok {
+		ch = make(chan memoryMessage, memoryTopicSize)
+		b.topics[name] = ch
+	}
+	return ch
+}
+
+type memoryProducer struct {
+	ch chan memoryMessage
+}
+
+func newMemoryProducer(b *memoryBroker, topic string) *memoryProducer {
+	return &memoryProducer{ch: b.topic(topic)}
+}
+
+func (p *memoryProducer) Push(key, value string) error {
+	p.ch <- memoryMessage{key: key, value: value}
+	return nil
+}
+
+func (p *memoryProducer) Close() error {
+	return nil
+}
+
+type memoryConsumer struct {
+	topic    string
+	ch       chan memoryMessage
+	handler  ConsumeHandler
+	executor *keyed.Executor
+
+	once sync.Once
+	done chan struct{}
+}
+
+func newMemoryConsumer(b *memoryBroker, c kq.KqConf, handler ConsumeHandler) *memoryConsumer {
+	return &memoryConsumer{
+		topic:    c.Topic,
+		ch:       b.topic(c.Topic),
+		handler:  handler,
+		executor: keyed.NewExecutor(c.Processors, 0),
+		done:     make(chan struct{}),
+	}
+}
+
+func (q *memoryConsumer) Start() {
+	defer q.executor.Stop()
+
+	for {
+		select {
+		case <-q.done:
+			return
+		case msg := <-q.ch:
+			q.executor.Submit(msg.key, func() {
+				if err := q.handler.Consume(msg.key, msg.value); err != nil {
+					logx.Errorf("mqx memory consume %v err %v, value %s", q.topic, err, msg.value)
+				}
+			})
+		}
+	}
+}
+
+func (q *memoryConsumer) Stop() {
+	q.once.Do(func() {
+		close(q.done)
+	})
+}
@@ -0,0 +1,76 @@
+// Package mqx 消息队列的生产与消费，后端由配置选择
+//
+//   - kafka：默认，按 key 分区，见 kafkax
+//   - redis：redis stream，适合没有 kafka 的部署
+//   - memory：进程内的 channel，用于本地开发与测试，生产者与消费者需要在同一进程中
+//
+// 所有后端都保证同一个 key 的消息按发送的顺序处理
+package mqx
+
+import (
+	"github.com/zeromicro/go-queue/kq"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/queue"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+	"imooc.com/easy-chat/pkg/kafkax"
+)
+
+const (
+	KafkaType  = "kafka"
+	RedisType  = "redis"
+	MemoryType = "memory"
+)
+
+// Conf 消息队列的后端，生产者与消费者需要使用相同的配置
+type Conf struct {
+	Type string `json:",default=kafka,options=kafka|redis|memory"`
+	// Redis 后端为 redis 时的连接配置
+	Redis redis.RedisConf `json:",optional"`
+	// MaxLen 后端为 redis 时每个 stream 大约保留的消息数
+	MaxLen int64 `json:",optional"`
+}
+
+// Producer 发送消息，相同 key 的消息按发送的顺序处理
+type Producer interface {
+	Push(key, value string) error
+	Close() error
+}
+
+// ConsumeHandler 与 kq.ConsumeHandler 一致
+type ConsumeHandler interface {
+	Consume(key, value string) error
+}
+
+// NewProducer addrs 为 kafka 的地址，其他后端忽略
+func NewProducer(c Conf, addrs []string, topic string) Producer {
+	switch c.Type {
+	case RedisType:
+		return newRedisProducer(c, topic)
+	case MemoryType:
+		return newMemoryProducer(defaultBroker, topic)
+	default:
+		return kafkax.NewPusher(addrs, topic)
+	}
+}
+
+// NewConsumer 消费配置沿用 kq.KqConf，Topic、Group、Offset、Processors 对所有后端有效，其余为 kafka 的配置
+func NewConsumer(c Conf, kc kq.KqConf, handler ConsumeHandler) (queue.MessageQueue, error) {
+	switch c.Type {
+	case RedisType:
+		return newRedisConsumer(c, kc, handler), nil
+	case MemoryType:
+		return newMemoryConsumer(defaultBroker, kc, handler), nil
+	default:
+		q, err := kafkax.NewQueue(kc, handler)
+		if err != nil {
+			return nil, err
+		}
+		return q, nil
+	}
+}
+
+func MustNewConsumer(c Conf, kc kq.KqConf, handler ConsumeHandler) queue.MessageQueue {
+	q, err := NewConsumer(c, kc, handler)
+	logx.Must(err)
+	return q
+}
@@ -0,0 +1,92 @@
+package mqx
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zeromicro/go-queue/kq"
+)
+
+type recorder struct {
+	mu   sync.Mutex
+	got  map[string][]string
+	n    int
+	done chan struct{}
+	want int
+}
+
+func (r *recorder) Consume(key, value string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.got[key] = append(r.got[key], value)
+	if r.n++; r.n == r.want {
+		close(r.done)
+	}
+	return nil
+}
+
+func TestMemory_Order(t *testing.T) {
+	const (
+		keys   = 20
+		perKey = 100
+	)
+
+	c := Conf{Type: MemoryType}
+	r := &recorder{got: make(map[string][]string), done: make(chan struct{}), want: keys * perKey}
+	consumer := MustNewConsumer(c, kq.KqConf{Topic: "test-order", Processors: 8}, r)
+	go consumer.Start()
+	defer consumer.Stop()
+
+	producer := NewProducer(c, nil, "test-order")
+	for i := 0; i < perKey; i++ {
+		for k := 0; k < keys; k++ {
+			if err := producer.Push(strconv.Itoa(k), strconv.Itoa(i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	select {
+	case <-r.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for messages")
+	}
+
+	for k := 0; k < keys; k++ {
+		got := r.got[strconv.Itoa(k)]
+		for i, value := range got {
+			if value != strconv.Itoa(i) {
+				t.Fatalf("key %d message %v consumed at position %d", k, value, i)
+			}
+		}
+	}
+}
+
+func TestMemory_Topics(t *testing.T) {
+	c := Conf{Type: MemoryType}
+	a := &recorder{got: make(map[string][]string), done: make(chan struct{}), want: 1}
+	b := &recorder{got: make(map[string][]string), done: make(chan struct{}), want: 1}
+
+	for topic, r := range map[string]*recorder{"test-a": a, "test-b": b} {
+		consumer := MustNewConsumer(c, kq.KqConf{Topic: topic}, r)
+		go consumer.Start()
+		defer consumer.Stop()
+	}
+
+	NewProducer(c, nil, "test-a").Push("k", "a")
+	NewProducer(c, nil, "test-b").Push("k", "b")
+
+	for _, r := range []*recorder{a, b} {
+		select {
+		case <-r.done:
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for messages")
+		}
+	}
+	if a.got["k"][0] != "a" || b.got["k"][0] != "b" {
+		t.Errorf("got a=%v b=%v", a.got, b.got)
+	}
+}
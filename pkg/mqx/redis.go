@@ -0,0 +1,170 @@
+package mqx
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	red "github.com/go-redis/redis/v8"
+	"github.com/zeromicro/go-queue/kq"
+	"github.com/zeromicro/go-zero/core/logx"
+	"imooc.com/easy-chat/pkg/keyed"
+	"imooc.com/easy-chat/pkg/redisx"
+)
+
+const (
+	redisReadCount   = 100
+	redisReadBlock   = time.Second
+	redisRetryWait   = time.Second
+	redisDefaultLen  = 100000
+	redisFieldKey    = "key"
+	redisFieldValue  = "value"
+	redisOffsetFirst = "0"
+	redisOffsetLast  = "$"
+)
+
+type redisProducer struct {
+	client red.UniversalClient
+	stream string
+	maxLen int64
+}
+
+func newRedisProducer(c Conf, topic string) *redisProducer {
+	maxLen := c.MaxLen
+	if maxLen <= 0 {
+		maxLen = redisDefaultLen
+	}
+	return &redisProducer{
+		client: redisx.NewClient(c.Redis),
+		stream: topic,
+		maxLen: maxLen,
+	}
+}
+
+func (p *redisProducer) Push(key, value string) error {
+	return p.client.XAdd(context.Background(), &red.XAddArgs{
+		Stream: p.stream,
+		MaxLen: p.maxLen,
+		Approx: true,
+		Values: map[string]any{
+			redisFieldKey:   key,
+			redisFieldValue: value,
+		},
+	}).Err()
+}
+
+func (p *redisProducer) Close() error {
+	return p.client.Close()
+}
+
+// redisConsumer 以消费组读取 redis stream，处理完成后确认
+//
+// 单个协程按顺序读取，同一个 key 的消息串行处理；消费组中有多个实例时 redis 按实例轮流分配消息，
+// 不再保证同一个 key 的顺序，需要顺序的 topic 只部署一个消费实例。
+// 启动时先处理本实例上次退出前已读取未确认的消息，实例名默认为主机名，需要在重启后保持不变
+type redisConsumer struct {
+	client   red.UniversalClient
+	c        kq.KqConf
+	name     string
+	handler  ConsumeHandler
+	executor *keyed.Executor
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+func newRedisConsumer(c Conf, kc kq.KqConf, handler ConsumeHandler) *redisConsumer {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		name = kc.Group
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &redisConsumer{
+		client:   redisx.NewClient(c.Redis),
+		c:        kc,
+		name:     name,
+		handler:  handler,
+		executor: keyed.NewExecutor(kc.Processors, 0),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+func (q *redisConsumer) Start() {
+	defer q.client.Close()
+	defer q.executor.Stop()
+
+	start := redisOffsetLast
+	if q.c.Offset == "first" {
+		start = redisOffsetFirst
+	}
+	err := q.client.XGroupCreateMkStream(q.ctx, q.c.Topic, q.c.Group, start).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		logx.Errorf("mqx redis create group %v err %v", q.c.Topic, err)
+	}
+
+	// 上次退出前已读取未确认的消息，按 id 翻页读取
+	for id := redisOffsetFirst; id != ""; {
+		if id = q.read(id); q.ctx.Err() != nil {
+			return
+		}
+	}
+
+	for q.ctx.Err() == nil {
+		q.read(">")
+	}
+}
+
+func (q *redisConsumer) Stop() {
+	q.once.Do(q.cancel)
+}
+
+// read 读取一批消息并提交处理，id 为 ">" 时读取新消息；读取未确认的消息时返回下一页的起始 id，没有更多时返回空
+func (q *redisConsumer) read(id string) string {
+	streams, err := q.client.XReadGroup(q.ctx, &red.XReadGroupArgs{
+		Group:    q.c.Group,
+		Consumer: q.name,
+		Streams:  []string{q.c.Topic, id},
+		Count:    redisReadCount,
+		Block:    redisReadBlock,
+	}).Result()
+	switch {
+	case errors.Is(err, red.Nil):
+		return ""
+	case err != nil:
+		if q.ctx.Err() == nil {
+			logx.Errorf("mqx redis read %v err %v", q.c.Topic, err)
+			time.Sleep(redisRetryWait)
+		}
+		return id
+	}
+
+	var last string
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			q.submit(msg)
+			last = msg.ID
+		}
+	}
+	return last
+}
+
+func (q *redisConsumer) submit(msg red.XMessage) {
+	key, _ := msg.Values[redisFieldKey].(string)
+	value, _ := msg.Values[redisFieldValue].(string)
+
+	q.executor.Submit(key, func() {
+		if err := q.handler.Consume(key, value); err != nil {
+			logx.Errorf("mqx redis consume %v err %v, value %s", q.c.Topic, err, value)
+		}
+		// 与 kafka 一致，处理失败也确认，重试由 handler 负责
+		if err := q.client.XAck(context.Background(), q.c.Topic, q.c.Group, msg.ID).Err(); err != nil {
+			logx.Errorf("mqx redis ack %v err %v, id %v", q.c.Topic, err, msg.ID)
+		}
+	})
+}
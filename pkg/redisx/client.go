@@ -0,0 +1,30 @@
+// Package redisx go-zero 的 redis 不支持订阅与 stream 等阻塞命令，按相同的配置创建 go-redis 的连接
+package redisx
+
+import (
+	"crypto/tls"
+	"strings"
+
+	red "github.com/go-redis/redis/v8"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+func NewClient(conf redis.RedisConf) red.UniversalClient {
+	var tlsConfig *tls.Config
+	if conf.Tls {
+		tlsConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	if conf.Type == redis.ClusterType {
+		return red.NewClusterClient(&red.ClusterOptions{
+			Addrs:     strings.Split(conf.Host, ","),
+			Password:  conf.Pass,
+			TLSConfig: tlsConfig,
+		})
+	}
+	return red.NewClient(&red.Options{
+		Addr:      conf.Host,
+		Password:  conf.Pass,
+		TLSConfig: tlsConfig,
+	})
+}
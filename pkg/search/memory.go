@@ -0,0 +1,172 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+var _ Indexer = (*MemoryIndex)(nil)
+
+// MemoryIndex 进程内的倒排索引，用于不支持 Mongo 文本索引的环境
+// 索引不持久化，由调用方在启动时从数据库重建
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[string]*Document
+	postings map[string]map[string]struct{}
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		docs:     make(map[string]*Document),
+		postings: make(map[string]map[string]struct{}),
+	}
+}
+
+// Index 索引消息，已存在的消息会被替换（如消息被编辑）
+func (m *MemoryIndex) Index(ctx context.Context, docs ...*Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, doc := range docs {
+		m.remove(doc.MsgId)
+
+		d := *doc
+		m.docs[d.MsgId] = &d
+		for _, token := range Tokenize(d.Content) {
+			ids, ok := m.postings[token]
+			if !ok {
+				ids = make(map[string]struct{})
+				m.postings[token] = ids
+			}
+			ids[d.MsgId] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func (m *MemoryIndex) Delete(ctx context.Context, msgIds ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, msgId := range msgIds {
+		m.remove(msgId)
+	}
+	return nil
+}
+
+func (m *MemoryIndex) remove(msgId string) {
+	doc, ok := m.docs[msgId]
+	if !ok {
+		return
+	}
+	for _, token := range Tokenize(doc.Content) {
+		if ids, ok := m.postings[token]; ok {
+			delete(ids, msgId)
+			if len(ids) == 0 {
+				delete(m.postings, token)
+			}
+		}
+	}
+	delete(m.docs, msgId)
+}
+
+// Len 已索引的消息数
+func (m *MemoryIndex) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.docs)
+}
+
+func (m *MemoryIndex) Search(ctx context.Context, q *Query) (*Result, error) {
+	var (
+		cursorTime int64
+		cursorId   string
+	)
+	if q.Cursor != "" {
+		var err error
+		if cursorTime, cursorId, err = DecodeCursor(q.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	tokens := QueryTokens(q.Keyword)
+	if len(tokens) == 0 || len(q.Conversations) == 0 {
+		return &Result{}, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// 从最短的倒排表开始求交集
+	lists := make([]map[string]struct{}, 0, len(tokens))
+	for _, token := range tokens {
+		ids, ok := m.postings[token]
+		if !ok {
+			return &Result{}, nil
+		}
+		lists = append(lists, ids)
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		return len(lists[i]) < len(lists[j])
+	})
+
+	var docs []*Document
+	for msgId := range lists[0] {
+		matched := true
+		for _, ids := range lists[1:] {
+			if _, ok := ids[msgId]; !ok {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		doc := m.docs[msgId]
+		if !filter(doc, q) {
+			continue
+		}
+		if q.Cursor != "" && !Before(doc.SendTime, doc.MsgId, cursorTime, cursorId) {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+
+	sort.Slice(docs, func(i, j int) bool {
+		return Before(docs[j].SendTime, docs[j].MsgId, docs[i].SendTime, docs[i].MsgId)
+	})
+
+	res := &Result{}
+	if q.Limit > 0 && len(docs) > q.Limit {
+		docs = docs[:q.Limit]
+		last := docs[len(docs)-1]
+		res.NextCursor = EncodeCursor(last.SendTime, last.MsgId)
+	}
+	res.Hits = make([]*Hit, 0, len(docs))
+	for _, doc := range docs {
+		res.Hits = append(res.Hits, &Hit{MsgId: doc.MsgId, SendTime: doc.SendTime})
+	}
+	return res, nil
+}
+
+func filter(doc *Document, q *Query) bool {
+	since, ok := q.Conversations[doc.ConversationId]
+	if !ok || doc.SendTime <= since {
+		return false
+	}
+	if q.SendId != "" && doc.SendId != q.SendId {
+		return false
+	}
+	if q.MType >= 0 && doc.MType != q.MType {
+		return false
+	}
+	if q.StartTime > 0 && doc.SendTime < q.StartTime {
+		return false
+	}
+	if q.EndTime > 0 && doc.SendTime > q.EndTime {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("invalid search cursor")
+
+// Document 被索引的消息
+type Document struct {
+	MsgId          string
+	ConversationId string
+	SendId         string
+	MType          int
+	SendTime       int64
+	Content        string
+}
+
+// Query 搜索条件，结果按发送时间倒序
+type Query struct {
+	Keyword string
+
+	// Conversations 允许搜索的会话，conversationId -> 只搜索发送时间晚于该值的消息（用于 ClearUpTo）
+	Conversations map[string]int64
+
+	// 以下为可选的过滤条件
+	SendId    string
+	MType     int // 小于 0 表示不限
+	StartTime int64
+	EndTime   int64
+
+	// Cursor 上一页返回的 NextCursor，为空表示第一页
+	Cursor string
+	Limit  int
+}
+
+type Hit struct {
+	MsgId    string
+	SendTime int64
+}
+
+type Result struct {
+	Hits []*Hit
+	// NextCursor 下一页的游标，为空表示没有更多结果
+	NextCursor string
+}
+
+// Indexer 消息的全文索引
+// 索引只用于召回，调用方需要根据消息的最新状态（撤回、编辑、删除）再次校验
+type Indexer interface {
+	Index(ctx context.Context, docs ...*Document) error
+	Delete(ctx context.Context, msgIds ...string) error
+	Search(ctx context.Context, q *Query) (*Result, error)
+}
+
+// EncodeCursor 根据一页的最后一条结果生成游标
+func EncodeCursor(sendTime int64, msgId string) string {
+	return fmt.Sprintf("%d_%s", sendTime, msgId)
+}
+
+// DecodeCursor 解析游标，返回上一页最后一条结果的发送时间与消息ID
+func DecodeCursor(cursor string) (int64, string, error) {
+	idx := strings.IndexByte(cursor, '_')
+	if idx <= 0 || idx == len(cursor)-1 {
+		return 0, "", ErrInvalidCursor
+	}
+	sendTime, err := strconv.ParseInt(cursor[:idx], 10, 64)
+	if err != nil {
+		return 0, "", ErrInvalidCursor
+	}
+	return sendTime, cursor[idx+1:], nil
+}
+
+// Before 判断 (sendTime, msgId) 是否排在游标之后，即按发送时间倒序时更靠后
+func Before(sendTime int64, msgId string, cursorTime int64, cursorId string) bool {
+	if sendTime != cursorTime {
+		return sendTime < cursorTime
+	}
+	return msgId < cursorId
+}
+
+// Match 判断内容是否包含关键词中的全部词语，忽略大小写
+func Match(content, keyword string) bool {
+	terms := Terms(keyword)
+	if len(terms) == 0 {
+		return false
+	}
+	content = strings.ToLower(content)
+	for _, term := range terms {
+		if !strings.Contains(content, term) {
+			return false
+		}
+	}
+	return true
+}
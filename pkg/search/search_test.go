@@ -0,0 +1,124 @@
+package search
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryIndex_Search(t *testing.T) {
+	idx := NewMemoryIndex()
+	ctx := context.Background()
+
+	idx.Index(ctx,
+		&Document{MsgId: "a", ConversationId: "c1", SendId: "u1", SendTime: 1, Content: "明天一起去吃火锅"},
+		&Document{MsgId: "b", ConversationId: "c1", SendId: "u2", SendTime: 2, Content: "火锅 OK"},
+		&Document{MsgId: "c", ConversationId: "c2", SendId: "u1", SendTime: 3, Content: "Hotpot 火锅店见"},
+		&Document{MsgId: "d", ConversationId: "c3", SendId: "u1", SendTime: 4, Content: "火锅"},
+	)
+
+	all := map[string]int64{"c1": 0, "c2": 0}
+	tests := []struct {
+		name string
+		q    Query
+		want []string
+	}{
+		{"cjk", Query{Keyword: "火锅", Conversations: all, MType: -1}, []string{"c", "b", "a"}},
+		{"unigram", Query{Keyword: "锅", Conversations: all, MType: -1}, []string{"c", "b", "a"}},
+		{"and", Query{Keyword: "火锅 hotpot", Conversations: all, MType: -1}, []string{"c"}},
+		{"clearUpTo", Query{Keyword: "火锅", Conversations: map[string]int64{"c1": 1}, MType: -1}, []string{"b"}},
+		{"sender", Query{Keyword: "火锅", Conversations: all, SendId: "u1", MType: -1}, []string{"c", "a"}},
+		{"time", Query{Keyword: "火锅", Conversations: all, StartTime: 2, EndTime: 2, MType: -1}, []string{"b"}},
+		{"miss", Query{Keyword: "烧烤", Conversations: all, MType: -1}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := idx.Search(ctx, &tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hitIds(res); !equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryIndex_Cursor(t *testing.T) {
+	idx := NewMemoryIndex()
+	ctx := context.Background()
+
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		idx.Index(ctx, &Document{MsgId: id, ConversationId: "c1", SendTime: 1, Content: "hello"})
+	}
+
+	q := &Query{Keyword: "hello", Conversations: map[string]int64{"c1": 0}, MType: -1, Limit: 2}
+	var got []string
+	for i := 0; i < 5; i++ {
+		res, err := idx.Search(ctx, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, hitIds(res)...)
+		if res.NextCursor == "" {
+			break
+		}
+		q.Cursor = res.NextCursor
+	}
+	if want := []string{"e", "d", "c", "b", "a"}; !equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMemoryIndex_Reindex(t *testing.T) {
+	idx := NewMemoryIndex()
+	ctx := context.Background()
+	q := &Query{Keyword: "hello", Conversations: map[string]int64{"c1": 0}, MType: -1}
+
+	idx.Index(ctx, &Document{MsgId: "a", ConversationId: "c1", SendTime: 1, Content: "hello"})
+	idx.Index(ctx, &Document{MsgId: "a", ConversationId: "c1", SendTime: 1, Content: "bye"})
+	if res, _ := idx.Search(ctx, q); len(res.Hits) != 0 {
+		t.Errorf("edited message still matches old content")
+	}
+
+	idx.Delete(ctx, "a")
+	if idx.Len() != 0 {
+		t.Errorf("Len() = %d after delete", idx.Len())
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		content, keyword string
+		width            int
+		want             string
+	}{
+		{"明天一起去吃火锅", "火锅", 0, "明天一起去吃<em>火锅</em>"},
+		{"Hello World", "hello world", 0, "<em>Hello</em> <em>World</em>"},
+		{"0123456789火锅", "火锅", 8, "...456789<em>火锅</em>"},
+	}
+	for _, tt := range tests {
+		if got := Highlight(tt.content, tt.keyword, tt.width); got != tt.want {
+			t.Errorf("Highlight(%q, %q) = %q, want %q", tt.content, tt.keyword, got, tt.want)
+		}
+	}
+}
+
+func hitIds(res *Result) []string {
+	var ids []string
+	for _, hit := range res.Hits {
+		ids = append(ids, hit.MsgId)
+	}
+	return ids
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
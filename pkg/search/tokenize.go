@@ -0,0 +1,173 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// HighlightPre 与 HighlightPost 包裹摘要中命中的词语
+const (
+	HighlightPre  = "<em>"
+	HighlightPost = "</em>"
+)
+
+// Terms 把关键词按空白拆分为小写的词语，并去重
+func Terms(keyword string) []string {
+	fields := strings.Fields(strings.ToLower(keyword))
+
+	seen := make(map[string]struct{}, len(fields))
+	res := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if _, ok := seen[field]; ok {
+			continue
+		}
+		seen[field] = struct{}{}
+		res = append(res, field)
+	}
+	return res
+}
+
+// Tokenize 文档分词：字母数字连续的部分作为一个词，中日韩文字按单字与相邻两字切分
+func Tokenize(text string) []string {
+	return tokenize(text, true)
+}
+
+// QueryTokens 关键词分词，中日韩文字只在单字时使用单字，否则使用相邻两字，减少召回的数量
+func QueryTokens(keyword string) []string {
+	return tokenize(keyword, false)
+}
+
+func tokenize(text string, unigram bool) []string {
+	var (
+		tokens []string
+		word   []rune
+		cjk    []rune
+		seen   = make(map[string]struct{})
+	)
+	add := func(token string) {
+		if _, ok := seen[token]; ok {
+			return
+		}
+		seen[token] = struct{}{}
+		tokens = append(tokens, token)
+	}
+	flushWord := func() {
+		if len(word) > 0 {
+			add(string(word))
+			word = word[:0]
+		}
+	}
+	flushCJK := func() {
+		if len(cjk) == 0 {
+			return
+		}
+		if len(cjk) == 1 || unigram {
+			for _, r := range cjk {
+				add(string(r))
+			}
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			add(string(cjk[i : i+2]))
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		r = unicode.ToLower(r)
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+
+	return tokens
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
+
+// Highlight 截取内容中第一个命中词语附近的 width 个字符作为摘要，并标记所有命中的词语
+func Highlight(content, keyword string, width int) string {
+	runes := []rune(content)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	terms := Terms(keyword)
+	termRunes := make([][]rune, 0, len(terms))
+	for _, term := range terms {
+		termRunes = append(termRunes, []rune(term))
+	}
+
+	// 标记命中的位置
+	hit := make([]bool, len(runes))
+	first := -1
+	for _, term := range termRunes {
+		for i := 0; i+len(term) <= len(lower); i++ {
+			if !hasPrefix(lower[i:], term) {
+				continue
+			}
+			for j := i; j < i+len(term); j++ {
+				hit[j] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+	}
+
+	// 摘要的范围，命中的词语前保留少量上下文
+	start, end := 0, len(runes)
+	if width > 0 && len(runes) > width {
+		if first > width/4 {
+			start = first - width/4
+		}
+		end = start + width
+		if end > len(runes) {
+			end = len(runes)
+			start = end - width
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("...")
+	}
+	for i := start; i < end; i++ {
+		if hit[i] && (i == start || !hit[i-1]) {
+			b.WriteString(HighlightPre)
+		}
+		b.WriteRune(runes[i])
+		if hit[i] && (i == end-1 || !hit[i+1]) {
+			b.WriteString(HighlightPost)
+		}
+	}
+	if end < len(runes) {
+		b.WriteString("...")
+	}
+	return b.String()
+}
+
+func hasPrefix(s, prefix []rune) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,229 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"imooc.com/easy-chat/pkg/linkpreview"
+)
+
+var (
+	ErrUnsupportedURL = errors.New("webhook: unsupported url")
+	ErrBlockedAddress = errors.New("webhook: blocked address")
+)
+
+const (
+	DefaultTimeout    = 5 * time.Second
+	DefaultMaxRetry   = 3
+	DefaultBackoff    = time.Second
+	DefaultMaxBackoff = 30 * time.Second
+	DefaultUserAgent  = "easy-chat-webhook/1.0"
+	// MaxResponseBytes Call 最多读取的响应字节数
+	MaxResponseBytes = 64 * 1024
+)
+
+// StatusError 接收方返回了非 2xx 的状态码
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook: unexpected status %d", e.StatusCode)
+}
+
+// retryable 429 与 5xx 可以重试，其余状态码说明请求本身有问题
+func (e *StatusError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Delivery 一次投递
+type Delivery struct {
+	Id     string
+	Url    string
+	Secret string
+	Event  string
+	Body   []byte
+}
+
+type Option func(s *Sender)
+
+// WithTimeout 单次请求的超时时间
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Sender) {
+		s.timeout = timeout
+	}
+}
+
+// WithMaxRetry 失败后最多重试的次数
+func WithMaxRetry(n int) Option {
+	return func(s *Sender) {
+		s.maxRetry = n
+	}
+}
+
+// WithBackoff 首次重试的等待时间与等待时间的上限，每次重试等待时间翻倍
+func WithBackoff(backoff, maxBackoff time.Duration) Option {
+	return func(s *Sender) {
+		s.backoff = backoff
+		s.maxBackoff = maxBackoff
+	}
+}
+
+// WithAllowPrivate 允许投递到内网地址，仅在接收方部署在内网时使用
+func WithAllowPrivate() Option {
+	return func(s *Sender) {
+		s.allowPrivate = true
+	}
+}
+
+// Sender 投递签名的 webhook 请求，失败时按指数退避重试
+//
+// 与链接预览相同，默认在拨号阶段拒绝内网地址，避免 webhook 地址被用于访问内部服务
+type Sender struct {
+	client       *http.Client
+	timeout      time.Duration
+	maxRetry     int
+	backoff      time.Duration
+	maxBackoff   time.Duration
+	allowPrivate bool
+}
+
+func NewSender(opts ...Option) *Sender {
+	s := &Sender{
+		timeout:    DefaultTimeout,
+		maxRetry:   DefaultMaxRetry,
+		backoff:    DefaultBackoff,
+		maxBackoff: DefaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	dialer := &net.Dialer{
+		Timeout: s.timeout,
+		Control: s.control,
+	}
+	s.client = &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   s.timeout,
+			ResponseHeaderTimeout: s.timeout,
+			MaxIdleConns:          32,
+			IdleConnTimeout:       90 * time.Second,
+		},
+		// 不跟随重定向，避免签名的请求被转发到其他地址
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return s
+}
+
+func (s *Sender) control(network, address string, _ syscall.RawConn) error {
+	if s.allowPrivate {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || linkpreview.IsBlockedIP(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	return nil
+}
+
+// ValidateURL 校验 webhook 地址的格式，只支持 http 与 https
+func ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrUnsupportedURL
+	}
+	return nil
+}
+
+// Send 投递请求，返回最后一次失败的错误；ctx 取消时停止重试
+func (s *Sender) Send(ctx context.Context, d *Delivery) error {
+	_, err := s.Call(ctx, d)
+	return err
+}
+
+// Call 与 Send 相同，成功时返回响应内容，用于需要接收方返回处理结果的场景
+func (s *Sender) Call(ctx context.Context, d *Delivery) ([]byte, error) {
+	if err := ValidateURL(d.Url); err != nil {
+		return nil, err
+	}
+
+	backoff := s.backoff
+	for attempt := 0; ; attempt++ {
+		body, err := s.send(ctx, d)
+		if err == nil || attempt >= s.maxRetry || !retryable(err) {
+			return body, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
+
+func (s *Sender) send(ctx context.Context, d *Delivery) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Url, bytes.NewReader(d.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	// 每次重试重新签名，接收方可以按时间戳拒绝重放的请求
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", DefaultUserAgent)
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, d.Id)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(d.Secret, timestamp, d.Body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &StatusError{StatusCode: resp.StatusCode}
+	}
+	return body, nil
+}
+
+func retryable(err error) bool {
+	if errors.Is(err, ErrBlockedAddress) || errors.Is(err, ErrUnsupportedURL) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.retryable()
+	}
+	return true
+}
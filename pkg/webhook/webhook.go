@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// 请求头
+const (
+	HeaderEvent     = "X-Easy-Chat-Event"
+	HeaderDelivery  = "X-Easy-Chat-Delivery"
+	HeaderTimestamp = "X-Easy-Chat-Timestamp"
+	HeaderSignature = "X-Easy-Chat-Signature"
+)
+
+// DefaultTolerance 校验签名时允许的时间偏差，超出视为重放
+const DefaultTolerance = 5 * time.Minute
+
+const signaturePrefix = "sha256="
+
+// Sign 计算签名：HMAC-SHA256(secret, "{timestamp}.{body}")，timestamp 为秒级时间戳
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验签名与时间戳，供接收方使用
+func Verify(secret, signature string, timestamp int64, body []byte, now time.Time, tolerance time.Duration) bool {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	if d := now.Sub(time.Unix(timestamp, 0)); d > tolerance || d < -tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+// NewSecret 生成随机的密钥，用于签名与 incoming webhook 的 token
+func NewSecret() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"event":"message"}`)
+	now := time.Now()
+	sig := Sign("secret", now.Unix(), body)
+
+	if !Verify("secret", sig, now.Unix(), body, now, 0) {
+		t.Error("Verify valid signature = false")
+	}
+	if Verify("other", sig, now.Unix(), body, now, 0) {
+		t.Error("Verify with wrong secret = true")
+	}
+	if Verify("secret", sig, now.Unix(), []byte(`{}`), now, 0) {
+		t.Error("Verify with tampered body = true")
+	}
+	if Verify("secret", sig, now.Unix(), body, now.Add(10*time.Minute), 0) {
+		t.Error("Verify expired timestamp = true")
+	}
+}
+
+func TestSender_Send(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 前两次失败，第三次校验签名后成功
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if r.Header.Get(HeaderEvent) != "message" || r.Header.Get(HeaderDelivery) != "d1" ||
+			!Verify("secret", r.Header.Get(HeaderSignature), ts, body, time.Now(), 0) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	s := NewSender(WithAllowPrivate(), WithBackoff(time.Millisecond, 5*time.Millisecond))
+	body, err := s.Call(context.Background(), &Delivery{
+		Id:     "d1",
+		Url:    srv.URL,
+		Secret: "secret",
+		Event:  "message",
+		Body:   []byte(`{"content":"hi"}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 || string(body) != `{"ok":true}` {
+		t.Errorf("calls = %d, body = %s, want 3 calls", calls, body)
+	}
+}
+
+func TestSender_NoRetry(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	s := NewSender(WithAllowPrivate(), WithBackoff(time.Millisecond, time.Millisecond))
+	err := s.Send(context.Background(), &Delivery{Url: srv.URL, Secret: "secret"})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Send err = %v, want status 400", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+
+	// 默认拒绝内网地址
+	err = NewSender().Send(context.Background(), &Delivery{Url: srv.URL, Secret: "secret"})
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Send loopback err = %v, want ErrBlockedAddress", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+
+	if err := NewSender().Send(context.Background(), &Delivery{Url: "ftp://example.com"}); !errors.Is(err, ErrUnsupportedURL) {
+		t.Errorf("Send ftp err = %v, want ErrUnsupportedURL", err)
+	}
+}